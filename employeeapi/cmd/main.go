@@ -2,13 +2,17 @@ package main
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"employeeapi/internal/grpcapi"
 	"employeeapi/internal/handlers"
+	"employeeapi/internal/logging"
+	"employeeapi/internal/repos"
 	"employeeapi/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -16,13 +20,54 @@ import (
 	"github.com/rs/zerolog"
 )
 
+// Set at link time, e.g. -ldflags "-X main.version=1.2.0 -X main.commit=$(git rev-parse HEAD)"
+var (
+	version   = "dev"
+	commit    = ""
+	buildTime = ""
+)
+
 type Env struct {
 	ServerPort string `envconfig:"SERVER_PORT" required:"true" default:"9000"`
+	// GRPCPort is the port of the gRPC API served alongside the REST API
+	GRPCPort string `envconfig:"GRPC_PORT" default:"9001"`
+	// DrainDelay is how long the server keeps serving once it's not ready so load balancers
+	// stop sending traffic before it shuts down
+	DrainDelay time.Duration `envconfig:"DRAIN_DELAY" default:"5s"`
+
+	// Requests per second and burst of each client, identified by API key or IP; 0 disables it
+	RateLimitRPS   float64 `envconfig:"RATE_LIMIT_RPS" default:"10"`
+	RateLimitBurst int     `envconfig:"RATE_LIMIT_BURST" default:"20"`
+	// Comma separated API keys identifying clients; other keys are ignored
+	APIKeys []string `envconfig:"API_KEYS"`
+	// Comma separated IPs or CIDRs of the proxies whose X-Forwarded-For is trusted for the client
+	// IP; none by default so clients can't spoof it
+	TrustedProxies []string `envconfig:"TRUSTED_PROXIES"`
+	// Caps of POST and PUT bodies in bytes; 0 disables them
+	MaxBodyBytes       int64 `envconfig:"MAX_BODY_BYTES" default:"1048576"`
+	MaxImportBodyBytes int64 `envconfig:"MAX_IMPORT_BODY_BYTES" default:"10485760"`
+	// How long the response of a POST is replayed for its Idempotency-Key; 0 disables it
+	IdempotencyTTL time.Duration `envconfig:"IDEMPOTENCY_TTL" default:"24h"`
+
+	// Validation policy of the employees: ages in years, 0 disables the bound; comma separated
+	// email domains, any domain when empty; characters allowed in names besides letters
+	PolicyMinAge         int      `envconfig:"POLICY_MIN_AGE" default:"16"`
+	PolicyMaxAge         int      `envconfig:"POLICY_MAX_AGE" default:"100"`
+	PolicyEmailDomains   []string `envconfig:"POLICY_EMAIL_DOMAINS"`
+	PolicyNameCharacters string   `envconfig:"POLICY_NAME_CHARACTERS" default:" -'."`
+
+	// Bearer token of the admin endpoints, e.g. the purge of deleted employees; they're disabled
+	// while it's not set
+	AdminToken string `envconfig:"ADMIN_TOKEN"`
+
+	// Base64 of the 32 bytes key encrypting the date of birth and email of the employees at rest,
+	// e.g. from `openssl rand -base64 32`; they're stored in clear if it's not set
+	EncryptionKey string `envconfig:"ENCRYPTION_KEY"`
 }
 
 func main() {
-	// Set up logger
-	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	// Set up logger; PII is redacted from every log
+	logger := zerolog.New(logging.NewRedactWriter(os.Stdout)).With().Timestamp().Logger()
 	l := logger.With().Str("package", "main").Logger()
 
 	// Process env vars
@@ -32,12 +77,50 @@ func main() {
 		l.Fatal().Err(err).Msg("failed to process env vars")
 	}
 
+	// Set up the encryption of the PII at rest
+	var cipher *repos.FieldCipher
+	if cfg.EncryptionKey != "" {
+		cipher, err = repos.NewFieldCipherFromBase64(cfg.EncryptionKey)
+		if err != nil {
+			l.Fatal().Err(err).Msg("failed to set up encryption")
+		}
+	} else {
+		l.Warn().Msg("ENCRYPTION_KEY is not set, PII is stored in clear")
+	}
+
 	// Set up service and handler
-	svc := services.NewService(logger, true)
-	h := handlers.NewHandler(logger, svc)
+	svc := services.NewEncryptedService(logger, true, cipher)
+	svc.Policy = services.Policy{
+		MinAge:         cfg.PolicyMinAge,
+		MaxAge:         cfg.PolicyMaxAge,
+		EmailDomains:   cfg.PolicyEmailDomains,
+		NameCharacters: cfg.PolicyNameCharacters,
+	}
+	if err := svc.Policy.Validate(); err != nil {
+		l.Fatal().Err(err).Msg("invalid validation policy")
+	}
+
+	h := handlers.NewHandler(logger, svc).WithLimits(handlers.Limits{
+		RequestsPerSecond:  cfg.RateLimitRPS,
+		Burst:              cfg.RateLimitBurst,
+		MaxBodyBytes:       cfg.MaxBodyBytes,
+		MaxImportBodyBytes: cfg.MaxImportBodyBytes,
+		APIKeys:            cfg.APIKeys,
+	}).WithIdempotencyTTL(cfg.IdempotencyTTL).WithAdminToken(cfg.AdminToken)
+	h.SetBuildInfo(handlers.NewBuildInfo(version, commit, buildTime))
+
+	// Send employee events to webhooks in the background
+	svc.Webhooks.Start(svc.EmpRepo)
+
+	// Broadcast employee events to the change stream
+	svc.Events.Start(svc.EmpRepo)
 
 	// Set up server and routes
-	r := gin.Default()
+	// The handler logs the requests and recovers from panics so gin's defaults aren't used
+	r := gin.New()
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		l.Fatal().Err(err).Msg("invalid trusted proxies")
+	}
 	h.SetupRoutes(r)
 
 	srv := &http.Server{
@@ -53,6 +136,21 @@ func main() {
 		}
 	}()
 
+	// Start the gRPC server
+	grpcServer, grpcHealth := grpcapi.NewGRPCServer(logger, svc, cfg.AdminToken)
+
+	grpcListener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		l.Fatal().Err(err).Msg("failed to listen for gRPC")
+	}
+
+	go func() {
+		l.Info().Msg("Serving gRPC at localhost:" + cfg.GRPCPort)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			l.Fatal().Err(err).Msg("gRPC server failed")
+		}
+	}()
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 
@@ -60,8 +158,17 @@ func main() {
 
 	<-quit
 
+	// Fail readiness first so load balancers drain the traffic while requests are still served
+	h.SetReady(false)
+	grpcHealth.Shutdown()
+	l.Info().Dur("drainDelay", cfg.DrainDelay).Msg("Draining traffic...")
+	time.Sleep(cfg.DrainDelay)
+
 	l.Info().Msg("Shutting down server...")
 
+	// End the event streams first as Shutdown waits for the requests in progress
+	svc.Events.Stop()
+
 	// Gracefully shutdown the server with a timeout of 5 seconds
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -70,5 +177,21 @@ func main() {
 		l.Fatal().Err(err).Msg("Server forced to shutdown")
 	}
 
+	// Let the gRPC calls in flight finish within the same timeout
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		l.Error().Msg("gRPC server forced to shutdown")
+		grpcServer.Stop()
+	}
+
+	svc.Webhooks.Stop()
+
 	l.Info().Msg("Server exited")
 }
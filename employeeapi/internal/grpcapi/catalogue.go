@@ -0,0 +1,143 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+
+	"employeeapi/internal/grpcapi/employeepb"
+	"employeeapi/internal/handlers"
+	"employeeapi/internal/repos"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// catalogueOps are the repository operations of one kind of catalogue entry (departments or roles)
+type catalogueOps struct {
+	kind   string
+	list   func(ctx context.Context) ([]*repos.CatalogueEntry, error)
+	get    func(ctx context.Context, name string) (*repos.CatalogueEntry, error)
+	create func(ctx context.Context, entry *repos.CatalogueEntry) (*repos.CatalogueEntry, error)
+	update func(ctx context.Context, entry *repos.CatalogueEntry) (*repos.CatalogueEntry, error)
+	delete func(ctx context.Context, name string) error
+}
+
+func (s *Server) departments() catalogueOps {
+	return catalogueOps{
+		kind:   "department",
+		list:   s.svc.EmpRepo.GetDepartments,
+		get:    s.svc.EmpRepo.GetDepartment,
+		create: s.svc.EmpRepo.CreateDepartment,
+		update: s.svc.EmpRepo.UpdateDepartment,
+		delete: s.svc.EmpRepo.DeleteDepartment,
+	}
+}
+
+func (s *Server) roles() catalogueOps {
+	return catalogueOps{
+		kind:   "role",
+		list:   s.svc.EmpRepo.GetRoles,
+		get:    s.svc.EmpRepo.GetRole,
+		create: s.svc.EmpRepo.CreateRole,
+		update: s.svc.EmpRepo.UpdateRole,
+		delete: s.svc.EmpRepo.DeleteRole,
+	}
+}
+
+func (s *Server) ListDepartments(ctx context.Context, req *employeepb.ListCatalogueEntriesRequest) (*employeepb.ListCatalogueEntriesResponse, error) {
+	return s.listCatalogueEntries(ctx, s.departments())
+}
+
+func (s *Server) GetDepartment(ctx context.Context, req *employeepb.GetCatalogueEntryRequest) (*employeepb.CatalogueEntry, error) {
+	return s.getCatalogueEntry(ctx, s.departments(), req.GetName())
+}
+
+func (s *Server) CreateDepartment(ctx context.Context, req *employeepb.CatalogueEntry) (*employeepb.CatalogueEntry, error) {
+	return s.writeCatalogueEntry(ctx, s.departments(), s.departments().create, req, "department already exists")
+}
+
+func (s *Server) UpdateDepartment(ctx context.Context, req *employeepb.CatalogueEntry) (*employeepb.CatalogueEntry, error) {
+	return s.writeCatalogueEntry(ctx, s.departments(), s.departments().update, req, "department not found")
+}
+
+func (s *Server) DeleteDepartment(ctx context.Context, req *employeepb.DeleteCatalogueEntryRequest) (*emptypb.Empty, error) {
+	return s.deleteCatalogueEntry(ctx, s.departments(), req.GetName())
+}
+
+func (s *Server) ListRoles(ctx context.Context, req *employeepb.ListCatalogueEntriesRequest) (*employeepb.ListCatalogueEntriesResponse, error) {
+	return s.listCatalogueEntries(ctx, s.roles())
+}
+
+func (s *Server) GetRole(ctx context.Context, req *employeepb.GetCatalogueEntryRequest) (*employeepb.CatalogueEntry, error) {
+	return s.getCatalogueEntry(ctx, s.roles(), req.GetName())
+}
+
+func (s *Server) CreateRole(ctx context.Context, req *employeepb.CatalogueEntry) (*employeepb.CatalogueEntry, error) {
+	return s.writeCatalogueEntry(ctx, s.roles(), s.roles().create, req, "role already exists")
+}
+
+func (s *Server) UpdateRole(ctx context.Context, req *employeepb.CatalogueEntry) (*employeepb.CatalogueEntry, error) {
+	return s.writeCatalogueEntry(ctx, s.roles(), s.roles().update, req, "role not found")
+}
+
+func (s *Server) DeleteRole(ctx context.Context, req *employeepb.DeleteCatalogueEntryRequest) (*emptypb.Empty, error) {
+	return s.deleteCatalogueEntry(ctx, s.roles(), req.GetName())
+}
+
+func (s *Server) listCatalogueEntries(ctx context.Context, ops catalogueOps) (*employeepb.ListCatalogueEntriesResponse, error) {
+	l := s.logger.With().Str("package", packageName).Str("func", "listCatalogueEntries").Str("kind", ops.kind).Logger()
+
+	entries, err := ops.list(repoContext(ctx))
+	if err != nil {
+		l.Error().Err(err).Msg("failed to get catalogue entries")
+		return nil, toStatus(err)
+	}
+
+	return &employeepb.ListCatalogueEntriesResponse{Entries: newCatalogueEntries(entries)}, nil
+}
+
+func (s *Server) getCatalogueEntry(ctx context.Context, ops catalogueOps, name string) (*employeepb.CatalogueEntry, error) {
+	l := s.logger.With().Str("package", packageName).Str("func", "getCatalogueEntry").Str("kind", ops.kind).Logger()
+
+	entry, err := ops.get(repoContext(ctx), name)
+	if err != nil {
+		l.Error().Err(err).Str("name", name).Msg("failed to get catalogue entry")
+		return nil, toStatus(err, ops.kind+" not found")
+	}
+
+	return newCatalogueEntry(entry), nil
+}
+
+// Creates or updates a catalogue entry once validated like the REST body
+// detail is the message of the error if the write fails
+func (s *Server) writeCatalogueEntry(ctx context.Context, ops catalogueOps, write func(ctx context.Context, entry *repos.CatalogueEntry) (*repos.CatalogueEntry, error), req *employeepb.CatalogueEntry, detail string) (*employeepb.CatalogueEntry, error) {
+	l := s.logger.With().Str("package", packageName).Str("func", "writeCatalogueEntry").Str("kind", ops.kind).Logger()
+
+	entry := handlers.CatalogueEntry{Name: req.GetName(), Description: req.GetDescription()}
+	if err := s.validator.Struct(entry); err != nil {
+		l.Error().Err(err).Msg("failed to validate catalogue entry")
+		return nil, toStatus(err)
+	}
+
+	written, err := write(repoContext(ctx), &repos.CatalogueEntry{Name: entry.Name, Description: entry.Description})
+	if err != nil {
+		l.Error().Err(err).Str("name", entry.Name).Msg("failed to write catalogue entry")
+		return nil, toStatus(err, detail)
+	}
+
+	return newCatalogueEntry(written), nil
+}
+
+func (s *Server) deleteCatalogueEntry(ctx context.Context, ops catalogueOps, name string) (*emptypb.Empty, error) {
+	l := s.logger.With().Str("package", packageName).Str("func", "deleteCatalogueEntry").Str("kind", ops.kind).Logger()
+
+	if err := ops.delete(repoContext(ctx), name); err != nil {
+		l.Error().Err(err).Str("name", name).Msg("failed to delete catalogue entry")
+
+		if errors.Is(err, repos.ErrInUse) {
+			return nil, toStatus(err, ops.kind+" is still in use")
+		}
+		return nil, toStatus(err, ops.kind+" not found")
+	}
+
+	return &emptypb.Empty{}, nil
+}
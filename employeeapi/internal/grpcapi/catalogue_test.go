@@ -0,0 +1,60 @@
+package grpcapi
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"employeeapi/internal/grpcapi/employeepb"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Test the department and role operations
+func TestCatalogue(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+
+	created, err := client.CreateDepartment(ctx, &employeepb.CatalogueEntry{Name: "Legal", Description: "Contracts"})
+	require.NoError(t, err)
+	require.Equal(t, "Legal", created.GetName())
+
+	_, err = client.CreateDepartment(ctx, &employeepb.CatalogueEntry{Name: "Legal"})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+	require.Equal(t, "department already exists", status.Convert(err).Message())
+
+	_, err = client.CreateRole(ctx, &employeepb.CatalogueEntry{Name: strings.Repeat("a", 101)})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Equal(t, []string{"Name"}, fieldViolations(t, err))
+
+	updated, err := client.UpdateDepartment(ctx, &employeepb.CatalogueEntry{Name: "Legal", Description: "Contracts and compliance"})
+	require.NoError(t, err)
+	require.Equal(t, "Contracts and compliance", updated.GetDescription())
+
+	_, err = client.UpdateRole(ctx, &employeepb.CatalogueEntry{Name: "Astronaut"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	entry, err := client.GetDepartment(ctx, &employeepb.GetCatalogueEntryRequest{Name: "Legal"})
+	require.NoError(t, err)
+	require.Equal(t, "Contracts and compliance", entry.GetDescription())
+
+	// Departments in use can't be deleted
+	_, err = client.DeleteDepartment(ctx, &employeepb.DeleteCatalogueEntryRequest{Name: "Marketing"})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	require.Equal(t, "department is still in use", status.Convert(err).Message())
+
+	_, err = client.DeleteDepartment(ctx, &employeepb.DeleteCatalogueEntryRequest{Name: "Legal"})
+	require.NoError(t, err)
+
+	_, err = client.GetDepartment(ctx, &employeepb.GetCatalogueEntryRequest{Name: "Legal"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	roles, err := client.ListRoles(ctx, &employeepb.ListCatalogueEntriesRequest{})
+	require.NoError(t, err)
+	require.NotEmpty(t, roles.GetEntries())
+
+	_, err = client.GetRole(ctx, &employeepb.GetCatalogueEntryRequest{Name: "Software Developer"})
+	require.NoError(t, err)
+}
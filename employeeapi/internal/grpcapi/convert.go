@@ -0,0 +1,167 @@
+package grpcapi
+
+import (
+	"encoding/json"
+
+	"employeeapi/internal/grpcapi/employeepb"
+	"employeeapi/internal/handlers"
+	"employeeapi/internal/repos"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Maps a repository employee record to its message
+func newEmployee(emp *repos.Employee) *employeepb.Employee {
+	msg := &employeepb.Employee{
+		Id:         emp.ID,
+		FirstName:  emp.FirstName,
+		LastName:   emp.LastName,
+		Dob:        emp.DateOfBirth,
+		Email:      emp.Email,
+		IsActive:   emp.IsActive,
+		Department: emp.Department,
+		Role:       emp.Role,
+		HireDate:   emp.HireDate,
+	}
+
+	if emp.ManagerID != "" {
+		msg.ManagerId = &emp.ManagerID
+	}
+
+	if emp.DeletedAt != nil {
+		msg.DeletedAt = timestamppb.New(*emp.DeletedAt)
+	}
+
+	return msg
+}
+
+// Maps repository employee records to their messages
+func newEmployees(emps []*repos.Employee) []*employeepb.Employee {
+	msgs := make([]*employeepb.Employee, 0, len(emps))
+	for _, emp := range emps {
+		msgs = append(msgs, newEmployee(emp))
+	}
+
+	return msgs
+}
+
+// Maps an employee message to the body the REST handlers validate
+func newRequestEmployee(msg *employeepb.Employee) handlers.Employee {
+	return handlers.Employee{
+		ID:          msg.GetId(),
+		FirstName:   msg.GetFirstName(),
+		LastName:    msg.GetLastName(),
+		DateOfBirth: msg.GetDob(),
+		Email:       msg.GetEmail(),
+		IsActive:    msg.GetIsActive(),
+		Department:  msg.GetDepartment(),
+		Role:        msg.GetRole(),
+		ManagerID:   msg.ManagerId,
+		HireDate:    msg.GetHireDate(),
+	}
+}
+
+// Maps the audit entries of an employee to their messages
+func newAuditEntries(entries []*repos.AuditEntry) ([]*employeepb.AuditEntry, error) {
+	msgs := make([]*employeepb.AuditEntry, 0, len(entries))
+	for _, entry := range entries {
+		msg := &employeepb.AuditEntry{
+			Action:    entry.Action,
+			Actor:     entry.Actor,
+			Timestamp: timestamppb.New(entry.Timestamp),
+			Changes:   make([]*employeepb.FieldChange, 0, len(entry.Changes)),
+		}
+
+		for _, change := range entry.Changes {
+			before, err := newValue(change.Before)
+			if err != nil {
+				return nil, err
+			}
+
+			after, err := newValue(change.After)
+			if err != nil {
+				return nil, err
+			}
+
+			msg.Changes = append(msg.Changes, &employeepb.FieldChange{
+				Field:  change.Field,
+				Before: before,
+				After:  after,
+			})
+		}
+
+		msgs = append(msgs, msg)
+	}
+
+	return msgs, nil
+}
+
+// Converts a field value of an audit change to its JSON value, like the REST history
+func newValue(value any) (*structpb.Value, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+
+	return structpb.NewValue(decoded)
+}
+
+// Maps the periods of an employee to their messages
+func newPeriods(periods []*repos.Period) []*employeepb.Period {
+	msgs := make([]*employeepb.Period, 0, len(periods))
+	for _, period := range periods {
+		msg := &employeepb.Period{
+			Department: period.Department,
+			Role:       period.Role,
+			IsActive:   period.IsActive,
+		}
+
+		if period.From != nil {
+			msg.From = timestamppb.New(*period.From)
+		}
+		if period.To != nil {
+			msg.To = timestamppb.New(*period.To)
+		}
+
+		msgs = append(msgs, msg)
+	}
+
+	return msgs
+}
+
+// Maps an org chart node and its reports to their messages
+func newOrgNode(node *repos.OrgNode) *employeepb.OrgNode {
+	msg := &employeepb.OrgNode{
+		Employee: newEmployee(node.Employee),
+		Reports:  make([]*employeepb.OrgNode, 0, len(node.Reports)),
+	}
+
+	for _, report := range node.Reports {
+		msg.Reports = append(msg.Reports, newOrgNode(report))
+	}
+
+	return msg
+}
+
+// Maps repository catalogue entries to their messages
+func newCatalogueEntries(entries []*repos.CatalogueEntry) []*employeepb.CatalogueEntry {
+	msgs := make([]*employeepb.CatalogueEntry, 0, len(entries))
+	for _, entry := range entries {
+		msgs = append(msgs, newCatalogueEntry(entry))
+	}
+
+	return msgs
+}
+
+func newCatalogueEntry(entry *repos.CatalogueEntry) *employeepb.CatalogueEntry {
+	return &employeepb.CatalogueEntry{
+		Name:        entry.Name,
+		Description: entry.Description,
+	}
+}
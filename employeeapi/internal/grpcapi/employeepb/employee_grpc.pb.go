@@ -0,0 +1,1073 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: employee/v1/employee.proto
+
+package employeepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	EmployeeService_GetEmployee_FullMethodName            = "/employee.v1.EmployeeService/GetEmployee"
+	EmployeeService_ListEmployees_FullMethodName          = "/employee.v1.EmployeeService/ListEmployees"
+	EmployeeService_CreateEmployee_FullMethodName         = "/employee.v1.EmployeeService/CreateEmployee"
+	EmployeeService_CreateEmployees_FullMethodName        = "/employee.v1.EmployeeService/CreateEmployees"
+	EmployeeService_UpdateEmployee_FullMethodName         = "/employee.v1.EmployeeService/UpdateEmployee"
+	EmployeeService_DeleteEmployee_FullMethodName         = "/employee.v1.EmployeeService/DeleteEmployee"
+	EmployeeService_RestoreEmployee_FullMethodName        = "/employee.v1.EmployeeService/RestoreEmployee"
+	EmployeeService_PurgeEmployees_FullMethodName         = "/employee.v1.EmployeeService/PurgeEmployees"
+	EmployeeService_GetEmployeeHistory_FullMethodName     = "/employee.v1.EmployeeService/GetEmployeeHistory"
+	EmployeeService_GetEmployeeTimeline_FullMethodName    = "/employee.v1.EmployeeService/GetEmployeeTimeline"
+	EmployeeService_FindDuplicateEmployees_FullMethodName = "/employee.v1.EmployeeService/FindDuplicateEmployees"
+	EmployeeService_GetReports_FullMethodName             = "/employee.v1.EmployeeService/GetReports"
+	EmployeeService_GetManagementChain_FullMethodName     = "/employee.v1.EmployeeService/GetManagementChain"
+	EmployeeService_GetOrgChart_FullMethodName            = "/employee.v1.EmployeeService/GetOrgChart"
+	EmployeeService_SearchEmployees_FullMethodName        = "/employee.v1.EmployeeService/SearchEmployees"
+	EmployeeService_ListDepartments_FullMethodName        = "/employee.v1.EmployeeService/ListDepartments"
+	EmployeeService_GetDepartment_FullMethodName          = "/employee.v1.EmployeeService/GetDepartment"
+	EmployeeService_CreateDepartment_FullMethodName       = "/employee.v1.EmployeeService/CreateDepartment"
+	EmployeeService_UpdateDepartment_FullMethodName       = "/employee.v1.EmployeeService/UpdateDepartment"
+	EmployeeService_DeleteDepartment_FullMethodName       = "/employee.v1.EmployeeService/DeleteDepartment"
+	EmployeeService_ListRoles_FullMethodName              = "/employee.v1.EmployeeService/ListRoles"
+	EmployeeService_GetRole_FullMethodName                = "/employee.v1.EmployeeService/GetRole"
+	EmployeeService_CreateRole_FullMethodName             = "/employee.v1.EmployeeService/CreateRole"
+	EmployeeService_UpdateRole_FullMethodName             = "/employee.v1.EmployeeService/UpdateRole"
+	EmployeeService_DeleteRole_FullMethodName             = "/employee.v1.EmployeeService/DeleteRole"
+	EmployeeService_Health_FullMethodName                 = "/employee.v1.EmployeeService/Health"
+)
+
+// EmployeeServiceClient is the client API for EmployeeService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// EmployeeService has the operations of the employee repository.
+// The actor recorded in the audit trail is read from the x-actor metadata.
+type EmployeeServiceClient interface {
+	GetEmployee(ctx context.Context, in *GetEmployeeRequest, opts ...grpc.CallOption) (*Employee, error)
+	ListEmployees(ctx context.Context, in *ListEmployeesRequest, opts ...grpc.CallOption) (*ListEmployeesResponse, error)
+	CreateEmployee(ctx context.Context, in *CreateEmployeeRequest, opts ...grpc.CallOption) (*Employee, error)
+	// CreateEmployees creates all the employees or none of them
+	CreateEmployees(ctx context.Context, in *CreateEmployeesRequest, opts ...grpc.CallOption) (*CreateEmployeesResponse, error)
+	UpdateEmployee(ctx context.Context, in *UpdateEmployeeRequest, opts ...grpc.CallOption) (*Employee, error)
+	// DeleteEmployee soft deletes an employee; it's kept until it's purged
+	DeleteEmployee(ctx context.Context, in *DeleteEmployeeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RestoreEmployee(ctx context.Context, in *RestoreEmployeeRequest, opts ...grpc.CallOption) (*Employee, error)
+	// PurgeEmployees requires the admin token as a bearer token in the authorization metadata
+	PurgeEmployees(ctx context.Context, in *PurgeEmployeesRequest, opts ...grpc.CallOption) (*PurgeEmployeesResponse, error)
+	GetEmployeeHistory(ctx context.Context, in *GetEmployeeHistoryRequest, opts ...grpc.CallOption) (*GetEmployeeHistoryResponse, error)
+	GetEmployeeTimeline(ctx context.Context, in *GetEmployeeTimelineRequest, opts ...grpc.CallOption) (*GetEmployeeTimelineResponse, error)
+	FindDuplicateEmployees(ctx context.Context, in *FindDuplicateEmployeesRequest, opts ...grpc.CallOption) (*FindDuplicateEmployeesResponse, error)
+	GetReports(ctx context.Context, in *GetReportsRequest, opts ...grpc.CallOption) (*GetReportsResponse, error)
+	GetManagementChain(ctx context.Context, in *GetManagementChainRequest, opts ...grpc.CallOption) (*GetManagementChainResponse, error)
+	GetOrgChart(ctx context.Context, in *GetOrgChartRequest, opts ...grpc.CallOption) (*GetOrgChartResponse, error)
+	SearchEmployees(ctx context.Context, in *SearchEmployeesRequest, opts ...grpc.CallOption) (*SearchEmployeesResponse, error)
+	ListDepartments(ctx context.Context, in *ListCatalogueEntriesRequest, opts ...grpc.CallOption) (*ListCatalogueEntriesResponse, error)
+	GetDepartment(ctx context.Context, in *GetCatalogueEntryRequest, opts ...grpc.CallOption) (*CatalogueEntry, error)
+	CreateDepartment(ctx context.Context, in *CatalogueEntry, opts ...grpc.CallOption) (*CatalogueEntry, error)
+	UpdateDepartment(ctx context.Context, in *CatalogueEntry, opts ...grpc.CallOption) (*CatalogueEntry, error)
+	DeleteDepartment(ctx context.Context, in *DeleteCatalogueEntryRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListRoles(ctx context.Context, in *ListCatalogueEntriesRequest, opts ...grpc.CallOption) (*ListCatalogueEntriesResponse, error)
+	GetRole(ctx context.Context, in *GetCatalogueEntryRequest, opts ...grpc.CallOption) (*CatalogueEntry, error)
+	CreateRole(ctx context.Context, in *CatalogueEntry, opts ...grpc.CallOption) (*CatalogueEntry, error)
+	UpdateRole(ctx context.Context, in *CatalogueEntry, opts ...grpc.CallOption) (*CatalogueEntry, error)
+	DeleteRole(ctx context.Context, in *DeleteCatalogueEntryRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error)
+}
+
+type employeeServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEmployeeServiceClient(cc grpc.ClientConnInterface) EmployeeServiceClient {
+	return &employeeServiceClient{cc}
+}
+
+func (c *employeeServiceClient) GetEmployee(ctx context.Context, in *GetEmployeeRequest, opts ...grpc.CallOption) (*Employee, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Employee)
+	err := c.cc.Invoke(ctx, EmployeeService_GetEmployee_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) ListEmployees(ctx context.Context, in *ListEmployeesRequest, opts ...grpc.CallOption) (*ListEmployeesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEmployeesResponse)
+	err := c.cc.Invoke(ctx, EmployeeService_ListEmployees_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) CreateEmployee(ctx context.Context, in *CreateEmployeeRequest, opts ...grpc.CallOption) (*Employee, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Employee)
+	err := c.cc.Invoke(ctx, EmployeeService_CreateEmployee_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) CreateEmployees(ctx context.Context, in *CreateEmployeesRequest, opts ...grpc.CallOption) (*CreateEmployeesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateEmployeesResponse)
+	err := c.cc.Invoke(ctx, EmployeeService_CreateEmployees_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) UpdateEmployee(ctx context.Context, in *UpdateEmployeeRequest, opts ...grpc.CallOption) (*Employee, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Employee)
+	err := c.cc.Invoke(ctx, EmployeeService_UpdateEmployee_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) DeleteEmployee(ctx context.Context, in *DeleteEmployeeRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, EmployeeService_DeleteEmployee_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) RestoreEmployee(ctx context.Context, in *RestoreEmployeeRequest, opts ...grpc.CallOption) (*Employee, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Employee)
+	err := c.cc.Invoke(ctx, EmployeeService_RestoreEmployee_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) PurgeEmployees(ctx context.Context, in *PurgeEmployeesRequest, opts ...grpc.CallOption) (*PurgeEmployeesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PurgeEmployeesResponse)
+	err := c.cc.Invoke(ctx, EmployeeService_PurgeEmployees_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) GetEmployeeHistory(ctx context.Context, in *GetEmployeeHistoryRequest, opts ...grpc.CallOption) (*GetEmployeeHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetEmployeeHistoryResponse)
+	err := c.cc.Invoke(ctx, EmployeeService_GetEmployeeHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) GetEmployeeTimeline(ctx context.Context, in *GetEmployeeTimelineRequest, opts ...grpc.CallOption) (*GetEmployeeTimelineResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetEmployeeTimelineResponse)
+	err := c.cc.Invoke(ctx, EmployeeService_GetEmployeeTimeline_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) FindDuplicateEmployees(ctx context.Context, in *FindDuplicateEmployeesRequest, opts ...grpc.CallOption) (*FindDuplicateEmployeesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FindDuplicateEmployeesResponse)
+	err := c.cc.Invoke(ctx, EmployeeService_FindDuplicateEmployees_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) GetReports(ctx context.Context, in *GetReportsRequest, opts ...grpc.CallOption) (*GetReportsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetReportsResponse)
+	err := c.cc.Invoke(ctx, EmployeeService_GetReports_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) GetManagementChain(ctx context.Context, in *GetManagementChainRequest, opts ...grpc.CallOption) (*GetManagementChainResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetManagementChainResponse)
+	err := c.cc.Invoke(ctx, EmployeeService_GetManagementChain_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) GetOrgChart(ctx context.Context, in *GetOrgChartRequest, opts ...grpc.CallOption) (*GetOrgChartResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetOrgChartResponse)
+	err := c.cc.Invoke(ctx, EmployeeService_GetOrgChart_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) SearchEmployees(ctx context.Context, in *SearchEmployeesRequest, opts ...grpc.CallOption) (*SearchEmployeesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchEmployeesResponse)
+	err := c.cc.Invoke(ctx, EmployeeService_SearchEmployees_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) ListDepartments(ctx context.Context, in *ListCatalogueEntriesRequest, opts ...grpc.CallOption) (*ListCatalogueEntriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCatalogueEntriesResponse)
+	err := c.cc.Invoke(ctx, EmployeeService_ListDepartments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) GetDepartment(ctx context.Context, in *GetCatalogueEntryRequest, opts ...grpc.CallOption) (*CatalogueEntry, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CatalogueEntry)
+	err := c.cc.Invoke(ctx, EmployeeService_GetDepartment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) CreateDepartment(ctx context.Context, in *CatalogueEntry, opts ...grpc.CallOption) (*CatalogueEntry, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CatalogueEntry)
+	err := c.cc.Invoke(ctx, EmployeeService_CreateDepartment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) UpdateDepartment(ctx context.Context, in *CatalogueEntry, opts ...grpc.CallOption) (*CatalogueEntry, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CatalogueEntry)
+	err := c.cc.Invoke(ctx, EmployeeService_UpdateDepartment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) DeleteDepartment(ctx context.Context, in *DeleteCatalogueEntryRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, EmployeeService_DeleteDepartment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) ListRoles(ctx context.Context, in *ListCatalogueEntriesRequest, opts ...grpc.CallOption) (*ListCatalogueEntriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCatalogueEntriesResponse)
+	err := c.cc.Invoke(ctx, EmployeeService_ListRoles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) GetRole(ctx context.Context, in *GetCatalogueEntryRequest, opts ...grpc.CallOption) (*CatalogueEntry, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CatalogueEntry)
+	err := c.cc.Invoke(ctx, EmployeeService_GetRole_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) CreateRole(ctx context.Context, in *CatalogueEntry, opts ...grpc.CallOption) (*CatalogueEntry, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CatalogueEntry)
+	err := c.cc.Invoke(ctx, EmployeeService_CreateRole_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) UpdateRole(ctx context.Context, in *CatalogueEntry, opts ...grpc.CallOption) (*CatalogueEntry, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CatalogueEntry)
+	err := c.cc.Invoke(ctx, EmployeeService_UpdateRole_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) DeleteRole(ctx context.Context, in *DeleteCatalogueEntryRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, EmployeeService_DeleteRole_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *employeeServiceClient) Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HealthResponse)
+	err := c.cc.Invoke(ctx, EmployeeService_Health_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// EmployeeServiceServer is the server API for EmployeeService service.
+// All implementations must embed UnimplementedEmployeeServiceServer
+// for forward compatibility
+//
+// EmployeeService has the operations of the employee repository.
+// The actor recorded in the audit trail is read from the x-actor metadata.
+type EmployeeServiceServer interface {
+	GetEmployee(context.Context, *GetEmployeeRequest) (*Employee, error)
+	ListEmployees(context.Context, *ListEmployeesRequest) (*ListEmployeesResponse, error)
+	CreateEmployee(context.Context, *CreateEmployeeRequest) (*Employee, error)
+	// CreateEmployees creates all the employees or none of them
+	CreateEmployees(context.Context, *CreateEmployeesRequest) (*CreateEmployeesResponse, error)
+	UpdateEmployee(context.Context, *UpdateEmployeeRequest) (*Employee, error)
+	// DeleteEmployee soft deletes an employee; it's kept until it's purged
+	DeleteEmployee(context.Context, *DeleteEmployeeRequest) (*emptypb.Empty, error)
+	RestoreEmployee(context.Context, *RestoreEmployeeRequest) (*Employee, error)
+	// PurgeEmployees requires the admin token as a bearer token in the authorization metadata
+	PurgeEmployees(context.Context, *PurgeEmployeesRequest) (*PurgeEmployeesResponse, error)
+	GetEmployeeHistory(context.Context, *GetEmployeeHistoryRequest) (*GetEmployeeHistoryResponse, error)
+	GetEmployeeTimeline(context.Context, *GetEmployeeTimelineRequest) (*GetEmployeeTimelineResponse, error)
+	FindDuplicateEmployees(context.Context, *FindDuplicateEmployeesRequest) (*FindDuplicateEmployeesResponse, error)
+	GetReports(context.Context, *GetReportsRequest) (*GetReportsResponse, error)
+	GetManagementChain(context.Context, *GetManagementChainRequest) (*GetManagementChainResponse, error)
+	GetOrgChart(context.Context, *GetOrgChartRequest) (*GetOrgChartResponse, error)
+	SearchEmployees(context.Context, *SearchEmployeesRequest) (*SearchEmployeesResponse, error)
+	ListDepartments(context.Context, *ListCatalogueEntriesRequest) (*ListCatalogueEntriesResponse, error)
+	GetDepartment(context.Context, *GetCatalogueEntryRequest) (*CatalogueEntry, error)
+	CreateDepartment(context.Context, *CatalogueEntry) (*CatalogueEntry, error)
+	UpdateDepartment(context.Context, *CatalogueEntry) (*CatalogueEntry, error)
+	DeleteDepartment(context.Context, *DeleteCatalogueEntryRequest) (*emptypb.Empty, error)
+	ListRoles(context.Context, *ListCatalogueEntriesRequest) (*ListCatalogueEntriesResponse, error)
+	GetRole(context.Context, *GetCatalogueEntryRequest) (*CatalogueEntry, error)
+	CreateRole(context.Context, *CatalogueEntry) (*CatalogueEntry, error)
+	UpdateRole(context.Context, *CatalogueEntry) (*CatalogueEntry, error)
+	DeleteRole(context.Context, *DeleteCatalogueEntryRequest) (*emptypb.Empty, error)
+	Health(context.Context, *HealthRequest) (*HealthResponse, error)
+	mustEmbedUnimplementedEmployeeServiceServer()
+}
+
+// UnimplementedEmployeeServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedEmployeeServiceServer struct {
+}
+
+func (UnimplementedEmployeeServiceServer) GetEmployee(context.Context, *GetEmployeeRequest) (*Employee, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEmployee not implemented")
+}
+func (UnimplementedEmployeeServiceServer) ListEmployees(context.Context, *ListEmployeesRequest) (*ListEmployeesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEmployees not implemented")
+}
+func (UnimplementedEmployeeServiceServer) CreateEmployee(context.Context, *CreateEmployeeRequest) (*Employee, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateEmployee not implemented")
+}
+func (UnimplementedEmployeeServiceServer) CreateEmployees(context.Context, *CreateEmployeesRequest) (*CreateEmployeesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateEmployees not implemented")
+}
+func (UnimplementedEmployeeServiceServer) UpdateEmployee(context.Context, *UpdateEmployeeRequest) (*Employee, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateEmployee not implemented")
+}
+func (UnimplementedEmployeeServiceServer) DeleteEmployee(context.Context, *DeleteEmployeeRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteEmployee not implemented")
+}
+func (UnimplementedEmployeeServiceServer) RestoreEmployee(context.Context, *RestoreEmployeeRequest) (*Employee, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreEmployee not implemented")
+}
+func (UnimplementedEmployeeServiceServer) PurgeEmployees(context.Context, *PurgeEmployeesRequest) (*PurgeEmployeesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeEmployees not implemented")
+}
+func (UnimplementedEmployeeServiceServer) GetEmployeeHistory(context.Context, *GetEmployeeHistoryRequest) (*GetEmployeeHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEmployeeHistory not implemented")
+}
+func (UnimplementedEmployeeServiceServer) GetEmployeeTimeline(context.Context, *GetEmployeeTimelineRequest) (*GetEmployeeTimelineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEmployeeTimeline not implemented")
+}
+func (UnimplementedEmployeeServiceServer) FindDuplicateEmployees(context.Context, *FindDuplicateEmployeesRequest) (*FindDuplicateEmployeesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindDuplicateEmployees not implemented")
+}
+func (UnimplementedEmployeeServiceServer) GetReports(context.Context, *GetReportsRequest) (*GetReportsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReports not implemented")
+}
+func (UnimplementedEmployeeServiceServer) GetManagementChain(context.Context, *GetManagementChainRequest) (*GetManagementChainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetManagementChain not implemented")
+}
+func (UnimplementedEmployeeServiceServer) GetOrgChart(context.Context, *GetOrgChartRequest) (*GetOrgChartResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrgChart not implemented")
+}
+func (UnimplementedEmployeeServiceServer) SearchEmployees(context.Context, *SearchEmployeesRequest) (*SearchEmployeesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchEmployees not implemented")
+}
+func (UnimplementedEmployeeServiceServer) ListDepartments(context.Context, *ListCatalogueEntriesRequest) (*ListCatalogueEntriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDepartments not implemented")
+}
+func (UnimplementedEmployeeServiceServer) GetDepartment(context.Context, *GetCatalogueEntryRequest) (*CatalogueEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDepartment not implemented")
+}
+func (UnimplementedEmployeeServiceServer) CreateDepartment(context.Context, *CatalogueEntry) (*CatalogueEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDepartment not implemented")
+}
+func (UnimplementedEmployeeServiceServer) UpdateDepartment(context.Context, *CatalogueEntry) (*CatalogueEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateDepartment not implemented")
+}
+func (UnimplementedEmployeeServiceServer) DeleteDepartment(context.Context, *DeleteCatalogueEntryRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteDepartment not implemented")
+}
+func (UnimplementedEmployeeServiceServer) ListRoles(context.Context, *ListCatalogueEntriesRequest) (*ListCatalogueEntriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRoles not implemented")
+}
+func (UnimplementedEmployeeServiceServer) GetRole(context.Context, *GetCatalogueEntryRequest) (*CatalogueEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRole not implemented")
+}
+func (UnimplementedEmployeeServiceServer) CreateRole(context.Context, *CatalogueEntry) (*CatalogueEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateRole not implemented")
+}
+func (UnimplementedEmployeeServiceServer) UpdateRole(context.Context, *CatalogueEntry) (*CatalogueEntry, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateRole not implemented")
+}
+func (UnimplementedEmployeeServiceServer) DeleteRole(context.Context, *DeleteCatalogueEntryRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRole not implemented")
+}
+func (UnimplementedEmployeeServiceServer) Health(context.Context, *HealthRequest) (*HealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Health not implemented")
+}
+func (UnimplementedEmployeeServiceServer) mustEmbedUnimplementedEmployeeServiceServer() {}
+
+// UnsafeEmployeeServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EmployeeServiceServer will
+// result in compilation errors.
+type UnsafeEmployeeServiceServer interface {
+	mustEmbedUnimplementedEmployeeServiceServer()
+}
+
+func RegisterEmployeeServiceServer(s grpc.ServiceRegistrar, srv EmployeeServiceServer) {
+	s.RegisterService(&EmployeeService_ServiceDesc, srv)
+}
+
+func _EmployeeService_GetEmployee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEmployeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).GetEmployee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_GetEmployee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).GetEmployee(ctx, req.(*GetEmployeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_ListEmployees_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEmployeesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).ListEmployees(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_ListEmployees_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).ListEmployees(ctx, req.(*ListEmployeesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_CreateEmployee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateEmployeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).CreateEmployee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_CreateEmployee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).CreateEmployee(ctx, req.(*CreateEmployeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_CreateEmployees_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateEmployeesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).CreateEmployees(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_CreateEmployees_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).CreateEmployees(ctx, req.(*CreateEmployeesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_UpdateEmployee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateEmployeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).UpdateEmployee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_UpdateEmployee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).UpdateEmployee(ctx, req.(*UpdateEmployeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_DeleteEmployee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteEmployeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).DeleteEmployee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_DeleteEmployee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).DeleteEmployee(ctx, req.(*DeleteEmployeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_RestoreEmployee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreEmployeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).RestoreEmployee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_RestoreEmployee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).RestoreEmployee(ctx, req.(*RestoreEmployeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_PurgeEmployees_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeEmployeesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).PurgeEmployees(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_PurgeEmployees_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).PurgeEmployees(ctx, req.(*PurgeEmployeesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_GetEmployeeHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEmployeeHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).GetEmployeeHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_GetEmployeeHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).GetEmployeeHistory(ctx, req.(*GetEmployeeHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_GetEmployeeTimeline_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEmployeeTimelineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).GetEmployeeTimeline(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_GetEmployeeTimeline_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).GetEmployeeTimeline(ctx, req.(*GetEmployeeTimelineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_FindDuplicateEmployees_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindDuplicateEmployeesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).FindDuplicateEmployees(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_FindDuplicateEmployees_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).FindDuplicateEmployees(ctx, req.(*FindDuplicateEmployeesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_GetReports_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReportsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).GetReports(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_GetReports_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).GetReports(ctx, req.(*GetReportsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_GetManagementChain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetManagementChainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).GetManagementChain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_GetManagementChain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).GetManagementChain(ctx, req.(*GetManagementChainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_GetOrgChart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrgChartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).GetOrgChart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_GetOrgChart_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).GetOrgChart(ctx, req.(*GetOrgChartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_SearchEmployees_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchEmployeesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).SearchEmployees(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_SearchEmployees_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).SearchEmployees(ctx, req.(*SearchEmployeesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_ListDepartments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCatalogueEntriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).ListDepartments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_ListDepartments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).ListDepartments(ctx, req.(*ListCatalogueEntriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_GetDepartment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCatalogueEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).GetDepartment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_GetDepartment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).GetDepartment(ctx, req.(*GetCatalogueEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_CreateDepartment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CatalogueEntry)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).CreateDepartment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_CreateDepartment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).CreateDepartment(ctx, req.(*CatalogueEntry))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_UpdateDepartment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CatalogueEntry)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).UpdateDepartment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_UpdateDepartment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).UpdateDepartment(ctx, req.(*CatalogueEntry))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_DeleteDepartment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCatalogueEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).DeleteDepartment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_DeleteDepartment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).DeleteDepartment(ctx, req.(*DeleteCatalogueEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_ListRoles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCatalogueEntriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).ListRoles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_ListRoles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).ListRoles(ctx, req.(*ListCatalogueEntriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_GetRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCatalogueEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).GetRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_GetRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).GetRole(ctx, req.(*GetCatalogueEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_CreateRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CatalogueEntry)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).CreateRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_CreateRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).CreateRole(ctx, req.(*CatalogueEntry))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_UpdateRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CatalogueEntry)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).UpdateRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_UpdateRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).UpdateRole(ctx, req.(*CatalogueEntry))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_DeleteRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCatalogueEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).DeleteRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_DeleteRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).DeleteRole(ctx, req.(*DeleteCatalogueEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmployeeService_Health_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmployeeServiceServer).Health(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EmployeeService_Health_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmployeeServiceServer).Health(ctx, req.(*HealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// EmployeeService_ServiceDesc is the grpc.ServiceDesc for EmployeeService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EmployeeService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "employee.v1.EmployeeService",
+	HandlerType: (*EmployeeServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetEmployee",
+			Handler:    _EmployeeService_GetEmployee_Handler,
+		},
+		{
+			MethodName: "ListEmployees",
+			Handler:    _EmployeeService_ListEmployees_Handler,
+		},
+		{
+			MethodName: "CreateEmployee",
+			Handler:    _EmployeeService_CreateEmployee_Handler,
+		},
+		{
+			MethodName: "CreateEmployees",
+			Handler:    _EmployeeService_CreateEmployees_Handler,
+		},
+		{
+			MethodName: "UpdateEmployee",
+			Handler:    _EmployeeService_UpdateEmployee_Handler,
+		},
+		{
+			MethodName: "DeleteEmployee",
+			Handler:    _EmployeeService_DeleteEmployee_Handler,
+		},
+		{
+			MethodName: "RestoreEmployee",
+			Handler:    _EmployeeService_RestoreEmployee_Handler,
+		},
+		{
+			MethodName: "PurgeEmployees",
+			Handler:    _EmployeeService_PurgeEmployees_Handler,
+		},
+		{
+			MethodName: "GetEmployeeHistory",
+			Handler:    _EmployeeService_GetEmployeeHistory_Handler,
+		},
+		{
+			MethodName: "GetEmployeeTimeline",
+			Handler:    _EmployeeService_GetEmployeeTimeline_Handler,
+		},
+		{
+			MethodName: "FindDuplicateEmployees",
+			Handler:    _EmployeeService_FindDuplicateEmployees_Handler,
+		},
+		{
+			MethodName: "GetReports",
+			Handler:    _EmployeeService_GetReports_Handler,
+		},
+		{
+			MethodName: "GetManagementChain",
+			Handler:    _EmployeeService_GetManagementChain_Handler,
+		},
+		{
+			MethodName: "GetOrgChart",
+			Handler:    _EmployeeService_GetOrgChart_Handler,
+		},
+		{
+			MethodName: "SearchEmployees",
+			Handler:    _EmployeeService_SearchEmployees_Handler,
+		},
+		{
+			MethodName: "ListDepartments",
+			Handler:    _EmployeeService_ListDepartments_Handler,
+		},
+		{
+			MethodName: "GetDepartment",
+			Handler:    _EmployeeService_GetDepartment_Handler,
+		},
+		{
+			MethodName: "CreateDepartment",
+			Handler:    _EmployeeService_CreateDepartment_Handler,
+		},
+		{
+			MethodName: "UpdateDepartment",
+			Handler:    _EmployeeService_UpdateDepartment_Handler,
+		},
+		{
+			MethodName: "DeleteDepartment",
+			Handler:    _EmployeeService_DeleteDepartment_Handler,
+		},
+		{
+			MethodName: "ListRoles",
+			Handler:    _EmployeeService_ListRoles_Handler,
+		},
+		{
+			MethodName: "GetRole",
+			Handler:    _EmployeeService_GetRole_Handler,
+		},
+		{
+			MethodName: "CreateRole",
+			Handler:    _EmployeeService_CreateRole_Handler,
+		},
+		{
+			MethodName: "UpdateRole",
+			Handler:    _EmployeeService_UpdateRole_Handler,
+		},
+		{
+			MethodName: "DeleteRole",
+			Handler:    _EmployeeService_DeleteRole_Handler,
+		},
+		{
+			MethodName: "Health",
+			Handler:    _EmployeeService_Health_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "employee/v1/employee.proto",
+}
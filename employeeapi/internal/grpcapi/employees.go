@@ -0,0 +1,390 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"employeeapi/internal/grpcapi/employeepb"
+	"employeeapi/internal/handlers"
+	"employeeapi/internal/repos"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// GetEmployee gets an employee by id
+func (s *Server) GetEmployee(ctx context.Context, req *employeepb.GetEmployeeRequest) (*employeepb.Employee, error) {
+	l := s.logger.With().Str("package", packageName).Str("func", "GetEmployee").Logger()
+
+	if req.GetId() == "" {
+		return nil, requiredError("id")
+	}
+
+	opts := repos.QueryOptions{IncludeDeleted: req.GetIncludeDeleted()}
+	if req.GetAsOf() != nil {
+		opts.AsOf = req.GetAsOf().AsTime()
+	}
+
+	emp, err := s.svc.EmpRepo.GetEmployee(repoContext(ctx), req.GetId(), opts)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to get employee")
+		return nil, toStatus(err, handlers.ErrorEmpNotFound)
+	}
+
+	return newEmployee(emp), nil
+}
+
+// ListEmployees gets all employees sorted by name
+func (s *Server) ListEmployees(ctx context.Context, req *employeepb.ListEmployeesRequest) (*employeepb.ListEmployeesResponse, error) {
+	l := s.logger.With().Str("package", packageName).Str("func", "ListEmployees").Logger()
+
+	opts := repos.QueryOptions{IncludeDeleted: req.GetIncludeDeleted()}
+	if req.GetAsOf() != nil {
+		opts.AsOf = req.GetAsOf().AsTime()
+	}
+
+	emps, err := s.svc.EmpRepo.GetEmployees(repoContext(ctx), opts)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to get employees")
+		return nil, toStatus(err)
+	}
+
+	return &employeepb.ListEmployeesResponse{Employees: newEmployees(emps)}, nil
+}
+
+// CreateEmployee creates an employee
+func (s *Server) CreateEmployee(ctx context.Context, req *employeepb.CreateEmployeeRequest) (*employeepb.Employee, error) {
+	l := s.logger.With().Str("package", packageName).Str("func", "CreateEmployee").Logger()
+
+	if req.GetEmployee() == nil {
+		return nil, requiredError("employee")
+	}
+
+	emp := newRequestEmployee(req.GetEmployee())
+	if err := s.validator.Struct(emp); err != nil {
+		l.Error().Err(err).Msg("failed to validate employee")
+		return nil, toStatus(err)
+	}
+
+	created, err := s.svc.EmpRepo.CreateEmployee(repoContext(ctx), handlers.NewRepoEmployee(emp))
+	if err != nil {
+		l.Error().Err(err).Msg("failed to create employee")
+		return nil, toStatus(err)
+	}
+
+	return newEmployee(created), nil
+}
+
+// CreateEmployees creates all the employees or none of them
+func (s *Server) CreateEmployees(ctx context.Context, req *employeepb.CreateEmployeesRequest) (*employeepb.CreateEmployeesResponse, error) {
+	l := s.logger.With().Str("package", packageName).Str("func", "CreateEmployees").Logger()
+
+	emps := make([]*repos.Employee, 0, len(req.GetEmployees()))
+	for i, msg := range req.GetEmployees() {
+		emp := newRequestEmployee(msg)
+		if err := s.validator.Struct(emp); err != nil {
+			l.Error().Err(err).Int("index", i).Msg("failed to validate employee")
+			return nil, toStatus(&repos.BulkError{Index: i, Err: err})
+		}
+
+		emps = append(emps, handlers.NewRepoEmployee(emp))
+	}
+
+	created, err := s.svc.EmpRepo.CreateEmployees(repoContext(ctx), emps)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to create employees")
+		return nil, toStatus(err)
+	}
+
+	return &employeepb.CreateEmployeesResponse{Employees: newEmployees(created)}, nil
+}
+
+// UpdateEmployee updates an employee
+// The department and role are kept if empty and the manager if not set, like the REST update
+func (s *Server) UpdateEmployee(ctx context.Context, req *employeepb.UpdateEmployeeRequest) (*employeepb.Employee, error) {
+	l := s.logger.With().Str("package", packageName).Str("func", "UpdateEmployee").Logger()
+
+	if req.GetEmployee() == nil {
+		return nil, requiredError("employee")
+	}
+
+	id := req.GetEmployee().GetId()
+	if id == "" {
+		return nil, requiredError("employee.id")
+	}
+
+	existing, err := s.svc.EmpRepo.GetEmployee(repoContext(ctx), id, repos.QueryOptions{})
+	if err != nil {
+		l.Error().Err(err).Str("id", id).Msg("failed to get employee")
+		return nil, toStatus(err, handlers.ErrorEmpNotFound)
+	}
+
+	emp := newRequestEmployee(req.GetEmployee())
+	if err := s.validator.Struct(emp); err != nil {
+		l.Error().Err(err).Msg("failed to validate employee")
+		return nil, toStatus(err)
+	}
+
+	repos.MergeEmployee(existing, handlers.NewEmployeeUpdate(emp))
+
+	updated, err := s.svc.EmpRepo.UpdateEmployee(repoContext(ctx), existing)
+	if err != nil {
+		l.Error().Err(err).Str("id", id).Msg("failed to update employee")
+		return nil, toStatus(err)
+	}
+
+	return newEmployee(updated), nil
+}
+
+// DeleteEmployee soft deletes an employee
+func (s *Server) DeleteEmployee(ctx context.Context, req *employeepb.DeleteEmployeeRequest) (*emptypb.Empty, error) {
+	l := s.logger.With().Str("package", packageName).Str("func", "DeleteEmployee").Logger()
+
+	if req.GetId() == "" {
+		return nil, requiredError("id")
+	}
+
+	if err := s.svc.EmpRepo.DeleteEmployee(repoContext(ctx), req.GetId()); err != nil {
+		l.Error().Err(err).Str("id", req.GetId()).Msg("failed to delete employee")
+		return nil, toStatus(err, handlers.ErrorEmpNotFound)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// RestoreEmployee restores a soft deleted employee
+func (s *Server) RestoreEmployee(ctx context.Context, req *employeepb.RestoreEmployeeRequest) (*employeepb.Employee, error) {
+	l := s.logger.With().Str("package", packageName).Str("func", "RestoreEmployee").Logger()
+
+	if req.GetId() == "" {
+		return nil, requiredError("id")
+	}
+
+	emp, err := s.svc.EmpRepo.RestoreEmployee(repoContext(ctx), req.GetId())
+	if err != nil {
+		l.Error().Err(err).Str("id", req.GetId()).Msg("failed to restore employee")
+		return nil, toStatus(err)
+	}
+
+	return newEmployee(emp), nil
+}
+
+// PurgeEmployees permanently removes the employees deleted for longer than the retention
+// It requires the admin token and the retention can't be shorter than handlers.MinPurgeRetention,
+// like the REST purge
+func (s *Server) PurgeEmployees(ctx context.Context, req *employeepb.PurgeEmployeesRequest) (*employeepb.PurgeEmployeesResponse, error) {
+	l := s.logger.With().Str("package", packageName).Str("func", "PurgeEmployees").Logger()
+
+	if err := s.adminOnly(ctx); err != nil {
+		return nil, err
+	}
+
+	retention := handlers.DefaultPurgeRetention
+	if req.GetRetention() != nil {
+		retention = req.GetRetention().AsDuration()
+		if err := req.GetRetention().CheckValid(); err != nil || retention <= 0 {
+			return nil, toStatus(&repos.ValidationError{Field: "retention", Err: errors.New("must be a positive duration")})
+		}
+		if retention < handlers.MinPurgeRetention {
+			return nil, toStatus(&repos.ValidationError{Field: "retention", Err: errors.New(handlers.ErrorRetentionTooShort)})
+		}
+	}
+
+	ids, err := s.svc.EmpRepo.PurgeEmployees(repoContext(ctx), time.Now().UTC().Add(-retention))
+	if err != nil {
+		l.Error().Err(err).Msg("failed to purge employees")
+		return nil, toStatus(err)
+	}
+
+	l.Info().Int("count", len(ids)).Dur("retention", retention).Msg("purged employees")
+
+	return &employeepb.PurgeEmployeesResponse{Ids: ids}, nil
+}
+
+// GetEmployeeHistory gets the changes made to an employee, oldest first
+func (s *Server) GetEmployeeHistory(ctx context.Context, req *employeepb.GetEmployeeHistoryRequest) (*employeepb.GetEmployeeHistoryResponse, error) {
+	l := s.logger.With().Str("package", packageName).Str("func", "GetEmployeeHistory").Logger()
+
+	if req.GetId() == "" {
+		return nil, requiredError("id")
+	}
+
+	entries, err := s.svc.EmpRepo.GetEmployeeHistory(repoContext(ctx), req.GetId())
+	if err != nil {
+		l.Error().Err(err).Str("id", req.GetId()).Msg("failed to get employee history")
+		return nil, toStatus(err, handlers.ErrorEmpNotFound)
+	}
+
+	msgs, err := newAuditEntries(entries)
+	if err != nil {
+		l.Error().Err(err).Str("id", req.GetId()).Msg("failed to convert employee history")
+		return nil, toStatus(err)
+	}
+
+	return &employeepb.GetEmployeeHistoryResponse{Entries: msgs}, nil
+}
+
+// GetEmployeeTimeline gets the department, role and active status periods of an employee, oldest first
+func (s *Server) GetEmployeeTimeline(ctx context.Context, req *employeepb.GetEmployeeTimelineRequest) (*employeepb.GetEmployeeTimelineResponse, error) {
+	l := s.logger.With().Str("package", packageName).Str("func", "GetEmployeeTimeline").Logger()
+
+	if req.GetId() == "" {
+		return nil, requiredError("id")
+	}
+
+	periods, err := s.svc.EmpRepo.GetEmployeeTimeline(repoContext(ctx), req.GetId())
+	if err != nil {
+		l.Error().Err(err).Str("id", req.GetId()).Msg("failed to get employee timeline")
+		return nil, toStatus(err, handlers.ErrorEmpNotFound)
+	}
+
+	return &employeepb.GetEmployeeTimelineResponse{Periods: newPeriods(periods)}, nil
+}
+
+// FindDuplicateEmployees reports the employees sharing the same name and date of birth
+func (s *Server) FindDuplicateEmployees(ctx context.Context, req *employeepb.FindDuplicateEmployeesRequest) (*employeepb.FindDuplicateEmployeesResponse, error) {
+	l := s.logger.With().Str("package", packageName).Str("func", "FindDuplicateEmployees").Logger()
+
+	groups, err := s.svc.EmpRepo.FindDuplicateEmployees(repoContext(ctx))
+	if err != nil {
+		l.Error().Err(err).Msg("failed to find duplicate employees")
+		return nil, toStatus(err)
+	}
+
+	resp := &employeepb.FindDuplicateEmployeesResponse{Groups: make([]*employeepb.DuplicateGroup, 0, len(groups))}
+	for _, group := range groups {
+		resp.Groups = append(resp.Groups, &employeepb.DuplicateGroup{
+			FirstName: group[0].FirstName,
+			LastName:  group[0].LastName,
+			Dob:       group[0].DateOfBirth,
+			Employees: newEmployees(group),
+		})
+	}
+
+	return resp, nil
+}
+
+// GetReports gets the employees reporting to an employee, directly and at any level below
+func (s *Server) GetReports(ctx context.Context, req *employeepb.GetReportsRequest) (*employeepb.GetReportsResponse, error) {
+	l := s.logger.With().Str("package", packageName).Str("func", "GetReports").Logger()
+
+	if req.GetId() == "" {
+		return nil, requiredError("id")
+	}
+
+	direct, transitive, err := s.svc.EmpRepo.GetReports(repoContext(ctx), req.GetId())
+	if err != nil {
+		l.Error().Err(err).Str("id", req.GetId()).Msg("failed to get reports")
+		return nil, toStatus(err, handlers.ErrorEmpNotFound)
+	}
+
+	return &employeepb.GetReportsResponse{
+		Direct:     newEmployees(direct),
+		Transitive: newEmployees(transitive),
+	}, nil
+}
+
+// GetManagementChain gets the managers of an employee up to the top of the organisation
+func (s *Server) GetManagementChain(ctx context.Context, req *employeepb.GetManagementChainRequest) (*employeepb.GetManagementChainResponse, error) {
+	l := s.logger.With().Str("package", packageName).Str("func", "GetManagementChain").Logger()
+
+	if req.GetId() == "" {
+		return nil, requiredError("id")
+	}
+
+	chain, err := s.svc.EmpRepo.GetManagementChain(repoContext(ctx), req.GetId())
+	if err != nil {
+		l.Error().Err(err).Str("id", req.GetId()).Msg("failed to get management chain")
+		return nil, toStatus(err, handlers.ErrorEmpNotFound)
+	}
+
+	return &employeepb.GetManagementChainResponse{Managers: newEmployees(chain)}, nil
+}
+
+// GetOrgChart gets the whole organisation as trees
+func (s *Server) GetOrgChart(ctx context.Context, req *employeepb.GetOrgChartRequest) (*employeepb.GetOrgChartResponse, error) {
+	l := s.logger.With().Str("package", packageName).Str("func", "GetOrgChart").Logger()
+
+	chart, err := s.svc.EmpRepo.GetOrgChart(repoContext(ctx))
+	if err != nil {
+		l.Error().Err(err).Msg("failed to get org chart")
+		return nil, toStatus(err)
+	}
+
+	resp := &employeepb.GetOrgChartResponse{Roots: make([]*employeepb.OrgNode, 0, len(chart))}
+	for _, node := range chart {
+		resp.Roots = append(resp.Roots, newOrgNode(node))
+	}
+
+	return resp, nil
+}
+
+// SearchEmployees finds employees by name, email, department or role, most relevant first
+// The email is only searched if it's selected by the fields metadata
+func (s *Server) SearchEmployees(ctx context.Context, req *employeepb.SearchEmployeesRequest) (*employeepb.SearchEmployeesResponse, error) {
+	l := s.logger.With().Str("package", packageName).Str("func", "SearchEmployees").Logger()
+
+	query := strings.TrimSpace(req.GetQ())
+	if query == "" {
+		return nil, requiredError("q")
+	}
+
+	limit := handlers.DefaultSearchLimit
+	if req.GetLimit() != 0 {
+		limit = int(req.GetLimit())
+		if limit < 1 || limit > handlers.MaxSearchLimit {
+			return nil, toStatus(&repos.ValidationError{Field: "limit", Err: errors.New("must be between 1 and 100")})
+		}
+	}
+
+	fields, err := projectionFields(ctx)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to parse metadata")
+		return nil, toStatus(err)
+	}
+
+	// The email terms aren't searched if the email isn't selected, like the REST search
+	opts := repos.SearchOptions{Limit: limit, ExcludePII: !selected(fields, "email")}
+
+	results, err := s.svc.EmpRepo.SearchEmployees(repoContext(ctx), query, opts)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to search employees")
+		return nil, toStatus(err)
+	}
+
+	resp := &employeepb.SearchEmployeesResponse{Results: make([]*employeepb.SearchResult, 0, len(results))}
+	for _, result := range results {
+		resp.Results = append(resp.Results, &employeepb.SearchResult{
+			Employee: newEmployee(result.Employee),
+			Score:    result.Score,
+		})
+	}
+
+	return resp, nil
+}
+
+// Health reports whether the server accepts calls: the repository can be used and it's not
+// shutting down
+func (s *Server) Health(ctx context.Context, req *employeepb.HealthRequest) (*employeepb.HealthResponse, error) {
+	l := s.logger.With().Str("package", packageName).Str("func", "Health").Logger()
+
+	check, err := s.health.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	if err != nil || check.GetStatus() != grpc_health_v1.HealthCheckResponse_SERVING {
+		l.Warn().Msg("server is draining")
+		return nil, status.Error(codes.Unavailable, handlers.HealthStatusDraining)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, handlers.HealthCheckTimeout)
+	defer cancel()
+
+	if err := s.svc.EmpRepo.Health(ctx); err != nil {
+		l.Error().Err(err).Msg("repository is unavailable")
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	return &employeepb.HealthResponse{Status: handlers.HealthStatusOK}, nil
+}
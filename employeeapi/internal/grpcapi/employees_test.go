@@ -0,0 +1,285 @@
+package grpcapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"employeeapi/internal/grpcapi/employeepb"
+	"employeeapi/internal/handlers"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Returns the fields of the BadRequest details of an error
+func fieldViolations(t *testing.T, err error) []string {
+	fields := make([]string, 0)
+	for _, detail := range status.Convert(err).Details() {
+		badRequest, ok := detail.(*errdetails.BadRequest)
+		require.True(t, ok, "unexpected detail %T", detail)
+
+		for _, violation := range badRequest.GetFieldViolations() {
+			fields = append(fields, violation.GetField())
+		}
+	}
+
+	return fields
+}
+
+// Test CreateEmployee applies the validation of the REST handlers
+func TestCreateEmployee(t *testing.T) {
+	testCases := []struct {
+		name     string
+		employee *employeepb.Employee
+		code     codes.Code
+		fields   []string
+	}{
+		{
+			name: "Successful - Create Employee",
+			employee: &employeepb.Employee{
+				FirstName: "Emily",
+				LastName:  "Williams",
+				Dob:       "1995-12-08",
+				Email:     "emily.williams@example.com",
+			},
+			code: codes.OK,
+		},
+		{
+			name: "Failed - Create Employee - invalid fields",
+			employee: &employeepb.Employee{
+				LastName: "Williams",
+				Dob:      "1995-13-08",
+				Email:    "emily.williams",
+			},
+			code:   codes.InvalidArgument,
+			fields: []string{"FirstName", "DateOfBirth", "Email"},
+		},
+		{
+			name: "Failed - Create Employee - unknown department",
+			employee: &employeepb.Employee{
+				FirstName:  "Emily",
+				LastName:   "Williams",
+				Dob:        "1995-12-08",
+				Email:      "emily.williams@example.com",
+				Department: "Nowhere",
+			},
+			code:   codes.InvalidArgument,
+			fields: []string{"Department"},
+		},
+		{
+			name: "Failed - Create Employee - email in use",
+			employee: &employeepb.Employee{
+				FirstName: "Jane",
+				LastName:  "Doe",
+				Dob:       "1995-12-08",
+				Email:     "JANE.SMITH@example.com",
+			},
+			code: codes.AlreadyExists,
+		},
+		{
+			name:   "Failed - Create Employee - no employee",
+			code:   codes.InvalidArgument,
+			fields: []string{"employee"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client, _ := newTestClient(t)
+
+			emp, err := client.CreateEmployee(context.Background(), &employeepb.CreateEmployeeRequest{Employee: tc.employee})
+			require.Equal(t, tc.code, status.Code(err), "unexpected error %v", err)
+
+			if tc.code != codes.OK {
+				if tc.fields != nil {
+					require.Equal(t, tc.fields, fieldViolations(t, err))
+				}
+				return
+			}
+
+			require.NotEmpty(t, emp.GetId())
+			require.Equal(t, "Unassigned", emp.GetDepartment())
+			require.Equal(t, "Unassigned", emp.GetRole())
+		})
+	}
+}
+
+// Test CreateEmployees reports the index of the invalid record
+func TestCreateEmployees(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	valid := &employeepb.Employee{FirstName: "Emily", LastName: "Williams", Dob: "1995-12-08", Email: "emily.williams@example.com"}
+	invalid := &employeepb.Employee{FirstName: "Robert", LastName: "Johnson", Dob: "1988-03-10", Email: "robert"}
+
+	_, err := client.CreateEmployees(context.Background(), &employeepb.CreateEmployeesRequest{Employees: []*employeepb.Employee{valid, invalid}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Equal(t, []string{"employees[1].Email"}, fieldViolations(t, err))
+
+	invalid.Email = "robert.johnson@example.com"
+
+	resp, err := client.CreateEmployees(context.Background(), &employeepb.CreateEmployeesRequest{Employees: []*employeepb.Employee{valid, invalid}})
+	require.NoError(t, err)
+	require.Len(t, resp.GetEmployees(), 2)
+
+	list, err := client.ListEmployees(context.Background(), &employeepb.ListEmployeesRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.GetEmployees(), 4)
+}
+
+// Test GetEmployee, UpdateEmployee and DeleteEmployee
+func TestUpdateEmployee(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+
+	_, err := client.GetEmployee(ctx, &employeepb.GetEmployeeRequest{Id: "unknown"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+	require.Equal(t, "employee not found", status.Convert(err).Message())
+
+	emp, err := client.GetEmployee(ctx, &employeepb.GetEmployeeRequest{Id: employeeId2})
+	require.NoError(t, err)
+	require.Equal(t, employeeId1, emp.GetManagerId())
+
+	// The department, role and manager are kept if not given
+	updated, err := client.UpdateEmployee(ctx, &employeepb.UpdateEmployeeRequest{Employee: &employeepb.Employee{
+		Id:        employeeId2,
+		FirstName: "Jane",
+		LastName:  "Doe",
+		Dob:       "1990-09-22",
+		Email:     "jane.doe@example.com",
+		IsActive:  true,
+	}})
+	require.NoError(t, err)
+	require.Equal(t, "Doe", updated.GetLastName())
+	require.Equal(t, "Marketing", updated.GetDepartment())
+	require.Equal(t, employeeId1, updated.GetManagerId())
+
+	// An empty manager id removes the manager
+	noManager := ""
+	updated, err = client.UpdateEmployee(ctx, &employeepb.UpdateEmployeeRequest{Employee: &employeepb.Employee{
+		Id:        employeeId2,
+		FirstName: "Jane",
+		LastName:  "Doe",
+		Dob:       "1990-09-22",
+		Email:     "jane.doe@example.com",
+		ManagerId: &noManager,
+	}})
+	require.NoError(t, err)
+	require.Nil(t, updated.ManagerId)
+
+	history, err := client.GetEmployeeHistory(ctx, &employeepb.GetEmployeeHistoryRequest{Id: employeeId2})
+	require.NoError(t, err)
+	require.Len(t, history.GetEntries(), 2)
+	require.Equal(t, "last_name", history.GetEntries()[0].GetChanges()[0].GetField())
+	require.Equal(t, "Smith", history.GetEntries()[0].GetChanges()[0].GetBefore().GetStringValue())
+
+	_, err = client.DeleteEmployee(ctx, &employeepb.DeleteEmployeeRequest{Id: employeeId2})
+	require.NoError(t, err)
+
+	_, err = client.GetEmployee(ctx, &employeepb.GetEmployeeRequest{Id: employeeId2})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	deleted, err := client.GetEmployee(ctx, &employeepb.GetEmployeeRequest{Id: employeeId2, IncludeDeleted: true})
+	require.NoError(t, err)
+	require.NotNil(t, deleted.GetDeletedAt())
+
+	// Restoring an employee that isn't deleted conflicts with its state
+	_, err = client.RestoreEmployee(ctx, &employeepb.RestoreEmployeeRequest{Id: employeeId1})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// Purging requires the admin token and at least the minimum retention
+	_, err = client.PurgeEmployees(ctx, &employeepb.PurgeEmployeesRequest{})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	adminCtx := metadata.AppendToOutgoingContext(ctx, MetadataAuthorization, "Bearer "+adminToken)
+
+	for _, retention := range []time.Duration{-1, 0, time.Hour} {
+		_, err = client.PurgeEmployees(adminCtx, &employeepb.PurgeEmployeesRequest{Retention: durationpb.New(retention)})
+		require.Equal(t, codes.InvalidArgument, status.Code(err), retention)
+	}
+
+	// The employee was just deleted so it's kept
+	purged, err := client.PurgeEmployees(adminCtx, &employeepb.PurgeEmployeesRequest{Retention: durationpb.New(handlers.MinPurgeRetention)})
+	require.NoError(t, err)
+	require.Empty(t, purged.GetIds())
+}
+
+// Test the reporting lines, search and duplicates
+func TestReadEmployees(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+
+	reports, err := client.GetReports(ctx, &employeepb.GetReportsRequest{Id: employeeId1})
+	require.NoError(t, err)
+	require.Len(t, reports.GetDirect(), 1)
+	require.Equal(t, employeeId2, reports.GetDirect()[0].GetId())
+
+	chain, err := client.GetManagementChain(ctx, &employeepb.GetManagementChainRequest{Id: employeeId2})
+	require.NoError(t, err)
+	require.Len(t, chain.GetManagers(), 1)
+
+	chart, err := client.GetOrgChart(ctx, &employeepb.GetOrgChartRequest{})
+	require.NoError(t, err)
+	require.Len(t, chart.GetRoots(), 1)
+	require.Equal(t, employeeId2, chart.GetRoots()[0].GetReports()[0].GetEmployee().GetId())
+
+	results, err := client.SearchEmployees(ctx, &employeepb.SearchEmployeesRequest{Q: "smiht"})
+	require.NoError(t, err)
+	require.Len(t, results.GetResults(), 1)
+	require.Equal(t, employeeId2, results.GetResults()[0].GetEmployee().GetId())
+
+	_, err = client.SearchEmployees(ctx, &employeepb.SearchEmployeesRequest{Q: "smith", Limit: 101})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.SearchEmployees(ctx, &employeepb.SearchEmployeesRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	duplicates, err := client.FindDuplicateEmployees(ctx, &employeepb.FindDuplicateEmployeesRequest{})
+	require.NoError(t, err)
+	require.Empty(t, duplicates.GetGroups())
+
+	health, err := client.Health(ctx, &employeepb.HealthRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "ok", health.GetStatus())
+}
+
+// Test GetEmployeeTimeline and the as_of queries
+func TestGetEmployeeTimeline(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+
+	before := timestamppb.Now()
+
+	_, err := client.UpdateEmployee(ctx, &employeepb.UpdateEmployeeRequest{Employee: &employeepb.Employee{
+		Id:         employeeId1,
+		FirstName:  "John",
+		LastName:   "Doe",
+		Dob:        "1985-05-15",
+		Email:      "johndoe@example.com",
+		Department: "Finance",
+	}})
+	require.NoError(t, err)
+
+	timeline, err := client.GetEmployeeTimeline(ctx, &employeepb.GetEmployeeTimelineRequest{Id: employeeId1})
+	require.NoError(t, err)
+	require.Len(t, timeline.GetPeriods(), 2)
+	require.Nil(t, timeline.GetPeriods()[0].GetFrom())
+	require.Equal(t, "Engineering", timeline.GetPeriods()[0].GetDepartment())
+	require.Nil(t, timeline.GetPeriods()[1].GetTo())
+
+	emp, err := client.GetEmployee(ctx, &employeepb.GetEmployeeRequest{Id: employeeId1, AsOf: before})
+	require.NoError(t, err)
+	require.Equal(t, "Engineering", emp.GetDepartment())
+
+	list, err := client.ListEmployees(ctx, &employeepb.ListEmployeesRequest{AsOf: before})
+	require.NoError(t, err)
+	require.Equal(t, "Engineering", list.GetEmployees()[0].GetDepartment())
+
+	_, err = client.GetEmployeeTimeline(ctx, &employeepb.GetEmployeeTimelineRequest{Id: "unknown"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
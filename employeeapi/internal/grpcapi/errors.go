@@ -0,0 +1,96 @@
+package grpcapi
+
+import (
+	"errors"
+	"fmt"
+
+	"employeeapi/internal/handlers"
+	"employeeapi/internal/repos"
+
+	"github.com/go-playground/validator/v10"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Maps an error to its status like the REST handlers map it to a problem:
+// validation errors and invalid requests are InvalidArgument with the invalid fields as BadRequest
+// details, missing records NotFound, duplicates AlreadyExists, other conflicts FailedPrecondition
+// and anything else Internal
+// The message is detail if given, the error otherwise
+func toStatus(err error, detail ...string) error {
+	var (
+		bulkErr         *repos.BulkError
+		validationErrs  validator.ValidationErrors
+		repoValidateErr *repos.ValidationError
+		conflictErr     *repos.ConflictError
+		requestErr      *handlers.RequestError
+	)
+
+	// The fields of the records of a bulk write are prefixed with their index
+	prefix := ""
+	if errors.As(err, &bulkErr) {
+		prefix = fmt.Sprintf("employees[%d].", bulkErr.Index)
+	}
+
+	code := codes.Internal
+	msg := err.Error()
+	var violations []*errdetails.BadRequest_FieldViolation
+
+	switch {
+	case errors.As(err, &validationErrs):
+		code = codes.InvalidArgument
+		msg = handlers.ErrorInvalidBody
+		for _, dtl := range handlers.ValidationErrorDetails(validationErrs) {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       prefix + dtl.Field,
+				Description: dtl.Message,
+			})
+		}
+	case errors.As(err, &repoValidateErr):
+		code = codes.InvalidArgument
+		msg = repoValidateErr.Err.Error()
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       prefix + repoValidateErr.Field,
+			Description: repoValidateErr.Err.Error(),
+		})
+	case errors.As(err, &requestErr):
+		code = codes.InvalidArgument
+		msg = requestErr.Detail
+		for _, dtl := range requestErr.Errors {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       prefix + dtl.Field,
+				Description: dtl.Message,
+			})
+		}
+	case errors.Is(err, repos.ErrValidation):
+		code = codes.InvalidArgument
+	case errors.Is(err, repos.ErrNotFound):
+		code = codes.NotFound
+	case errors.As(err, &conflictErr):
+		code = codes.AlreadyExists
+	case errors.Is(err, repos.ErrConflict):
+		code = codes.FailedPrecondition
+	}
+
+	if code == codes.Internal {
+		// Don't leak the internals to the caller
+		msg = codes.Internal.String()
+	} else if len(detail) > 0 && detail[0] != "" {
+		msg = detail[0]
+	}
+
+	st := status.New(code, msg)
+	if len(violations) > 0 {
+		if withDetails, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+			st = withDetails
+		}
+	}
+
+	return st.Err()
+}
+
+// Returns the InvalidArgument error of a request missing a required field
+func requiredError(field string) error {
+	return toStatus(&repos.ValidationError{Field: field, Err: errors.New("is required")})
+}
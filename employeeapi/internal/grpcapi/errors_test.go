@@ -0,0 +1,70 @@
+package grpcapi
+
+import (
+	"errors"
+	"testing"
+
+	"employeeapi/internal/repos"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Test the mapping of errors to statuses
+func TestToStatus(t *testing.T) {
+	testCases := []struct {
+		name    string
+		err     error
+		detail  string
+		code    codes.Code
+		message string
+	}{
+		{
+			name:    "Not found",
+			err:     repos.ErrNotFound,
+			detail:  "employee not found",
+			code:    codes.NotFound,
+			message: "employee not found",
+		},
+		{
+			name:    "Duplicate",
+			err:     &repos.ConflictError{Field: "email", Value: "a@example.com", ConflictID: "1"},
+			code:    codes.AlreadyExists,
+			message: "email already in use",
+		},
+		{
+			name:    "Conflict",
+			err:     repos.ErrManagerCycle,
+			code:    codes.FailedPrecondition,
+			message: repos.ManagerCycle,
+		},
+		{
+			name:    "Validation",
+			err:     &repos.BulkError{Index: 2, Err: &repos.ValidationError{Field: "manager_id", Err: repos.ErrManagerNotFound}},
+			code:    codes.InvalidArgument,
+			message: repos.ManagerNotFound,
+		},
+		{
+			name:    "Internal",
+			err:     errors.New("disk on fire"),
+			detail:  "employee not found",
+			code:    codes.Internal,
+			message: "Internal",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			st := status.Convert(toStatus(tc.err, tc.detail))
+
+			require.Equal(t, tc.code, st.Code())
+			require.Equal(t, tc.message, st.Message())
+		})
+	}
+
+	require.Equal(t, []string{"employees[2].manager_id"}, fieldViolations(t, toStatus(&repos.BulkError{
+		Index: 2,
+		Err:   &repos.ValidationError{Field: "manager_id", Err: repos.ErrManagerNotFound},
+	})))
+}
@@ -0,0 +1,134 @@
+package grpcapi
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"employeeapi/internal/grpcapi/employeepb"
+	"employeeapi/internal/handlers"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Fields of an employee, by proto name; they're the JSON names of the REST API
+var employeeFields = messageFieldNames(&employeepb.Employee{})
+
+// Messages holding fields of an employee: Employee, the name and date of birth of a duplicate group
+// and the department, role and active status of a timeline period
+var employeeMessages = map[protoreflect.FullName]bool{
+	messageName(&employeepb.Employee{}):       true,
+	messageName(&employeepb.DuplicateGroup{}): true,
+	messageName(&employeepb.Period{}):         true,
+}
+
+// The history only keeps the changes of the selected fields
+var fieldChangeName = messageName(&employeepb.FieldChange{})
+
+func messageName(msg proto.Message) protoreflect.FullName {
+	return msg.ProtoReflect().Descriptor().FullName()
+}
+
+// Returns the proto names of the fields of a message
+func messageFieldNames(msg proto.Message) map[string]bool {
+	names := make(map[string]bool)
+
+	fields := msg.ProtoReflect().Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		names[string(fields.Get(i).Name())] = true
+	}
+
+	return names
+}
+
+// Parses the fields metadata of a call
+// Returns nil if it's not given, i.e. every field is sent
+func projectionFields(ctx context.Context) ([]string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	return handlers.ParseFields(strings.Join(md.Get(MetadataFields), ","))
+}
+
+// Returns whether a field of the employees is selected by the fields metadata
+func selected(fields []string, field string) bool {
+	return fields == nil || slices.Contains(fields, field)
+}
+
+// Sends only the fields of the employees selected by the fields metadata, like the REST handlers
+// sending only the fields selected by the fields query parameter
+func (s *Server) projection(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	l := s.logger.With().Str("package", packageName).Str("func", "projection").Logger()
+
+	fields, err := projectionFields(ctx)
+	if err != nil {
+		l.Error().Err(err).Str("method", info.FullMethod).Msg("failed to parse metadata")
+		return nil, toStatus(err)
+	}
+
+	resp, err := handler(ctx, req)
+	if err != nil || fields == nil {
+		return resp, err
+	}
+
+	if msg, ok := resp.(proto.Message); ok {
+		keep := make(map[string]bool, len(fields))
+		for _, field := range fields {
+			keep[field] = true
+		}
+
+		projectMessage(msg.ProtoReflect(), keep)
+	}
+
+	return resp, nil
+}
+
+// Clears the employee fields that aren't kept from the messages of the employees in a message,
+// walking its fields; the messages wrapping them keep every field, e.g. the score of a search result
+func projectMessage(msg protoreflect.Message, keep map[string]bool) {
+	employee := employeeMessages[msg.Descriptor().FullName()]
+
+	msg.Range(func(fd protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		name := string(fd.Name())
+		if employee && employeeFields[name] && !keep[name] {
+			msg.Clear(fd)
+			return true
+		}
+
+		switch {
+		case fd.IsMap() || fd.Message() == nil:
+		case fd.IsList() && fd.Message().FullName() == fieldChangeName:
+			filterFieldChanges(value.List(), keep)
+		case fd.IsList():
+			list := value.List()
+			for i := 0; i < list.Len(); i++ {
+				projectMessage(list.Get(i).Message(), keep)
+			}
+		default:
+			projectMessage(value.Message(), keep)
+		}
+
+		return true
+	})
+}
+
+// Leaves out the changes of the fields that aren't kept
+func filterFieldChanges(changes protoreflect.List, keep map[string]bool) {
+	kept := 0
+	for i := 0; i < changes.Len(); i++ {
+		change := changes.Get(i)
+		if !keep[change.Message().Interface().(*employeepb.FieldChange).GetField()] {
+			continue
+		}
+
+		changes.Set(kept, change)
+		kept++
+	}
+
+	changes.Truncate(kept)
+}
@@ -0,0 +1,169 @@
+package grpcapi
+
+import (
+	"context"
+	"testing"
+
+	"employeeapi/internal/grpcapi/employeepb"
+	"employeeapi/internal/handlers"
+	"employeeapi/internal/repos"
+	"employeeapi/internal/services"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Test the fields metadata projects the employees of the responses
+func TestProjection(t *testing.T) {
+	testCases := []struct {
+		name   string
+		fields string
+		call   func(ctx context.Context, client employeepb.EmployeeServiceClient) (proto.Message, error)
+		want   proto.Message
+		code   codes.Code
+	}{
+		{
+			name:   "Successful - Projection - get employee",
+			fields: "id,first_name,last_name",
+			call: func(ctx context.Context, client employeepb.EmployeeServiceClient) (proto.Message, error) {
+				return client.GetEmployee(ctx, &employeepb.GetEmployeeRequest{Id: employeeId1})
+			},
+			want: &employeepb.Employee{Id: employeeId1, FirstName: "John", LastName: "Doe"},
+		},
+		{
+			name:   "Successful - Projection - without PII",
+			fields: "id,first_name,last_name,is_active,department,role,manager_id",
+			call: func(ctx context.Context, client employeepb.EmployeeServiceClient) (proto.Message, error) {
+				return client.ListEmployees(ctx, &employeepb.ListEmployeesRequest{})
+			},
+			want: &employeepb.ListEmployeesResponse{Employees: []*employeepb.Employee{
+				{Id: employeeId1, FirstName: "John", LastName: "Doe", Department: "Engineering", Role: "Software Developer"},
+				{Id: employeeId2, FirstName: "Jane", LastName: "Smith", IsActive: true, Department: "Marketing", Role: "Marketing Specialist", ManagerId: proto.String(employeeId1)},
+			}},
+		},
+		{
+			name:   "Successful - Projection - search results keep their score",
+			fields: "id",
+			call: func(ctx context.Context, client employeepb.EmployeeServiceClient) (proto.Message, error) {
+				return client.SearchEmployees(ctx, &employeepb.SearchEmployeesRequest{Q: "smith"})
+			},
+			want: &employeepb.SearchEmployeesResponse{Results: []*employeepb.SearchResult{
+				{Employee: &employeepb.Employee{Id: employeeId2}, Score: 3},
+			}},
+		},
+		{
+			name:   "Successful - Projection - search leaves out the email terms",
+			fields: "id",
+			call: func(ctx context.Context, client employeepb.EmployeeServiceClient) (proto.Message, error) {
+				return client.SearchEmployees(ctx, &employeepb.SearchEmployeesRequest{Q: "example"})
+			},
+			want: &employeepb.SearchEmployeesResponse{},
+		},
+		{
+			name:   "Successful - Projection - timeline",
+			fields: "department",
+			call: func(ctx context.Context, client employeepb.EmployeeServiceClient) (proto.Message, error) {
+				return client.GetEmployeeTimeline(ctx, &employeepb.GetEmployeeTimelineRequest{Id: employeeId1})
+			},
+			want: &employeepb.GetEmployeeTimelineResponse{Periods: []*employeepb.Period{{Department: "Engineering"}}},
+		},
+		{
+			name:   "Successful - Projection - history",
+			fields: "first_name",
+			call: func(ctx context.Context, client employeepb.EmployeeServiceClient) (proto.Message, error) {
+				history, err := client.GetEmployeeHistory(ctx, &employeepb.GetEmployeeHistoryRequest{Id: employeeId1})
+				if err != nil {
+					return nil, err
+				}
+
+				// Only the changes are compared
+				for _, entry := range history.GetEntries() {
+					entry.Actor, entry.Timestamp = "", nil
+				}
+
+				return history, nil
+			},
+			want: &employeepb.GetEmployeeHistoryResponse{Entries: []*employeepb.AuditEntry{
+				{Action: "update", Changes: []*employeepb.FieldChange{}},
+			}},
+		},
+		{
+			name:   "Successful - Projection - no metadata",
+			fields: "",
+			call: func(ctx context.Context, client employeepb.EmployeeServiceClient) (proto.Message, error) {
+				return client.GetEmployee(ctx, &employeepb.GetEmployeeRequest{Id: employeeId1})
+			},
+			want: &employeepb.Employee{
+				Id:         employeeId1,
+				FirstName:  "John",
+				LastName:   "Doe",
+				Dob:        "1985-05-15",
+				Email:      "john.doe@example.com",
+				Department: "Engineering",
+				Role:       "Software Developer",
+			},
+		},
+		{
+			name:   "Failed - Projection - unknown field",
+			fields: "id,salary",
+			call: func(ctx context.Context, client employeepb.EmployeeServiceClient) (proto.Message, error) {
+				return client.GetEmployee(ctx, &employeepb.GetEmployeeRequest{Id: employeeId1})
+			},
+			code: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client, _ := newTestClient(t)
+
+			// The history has a change of the email to leave out
+			_, err := client.UpdateEmployee(context.Background(), &employeepb.UpdateEmployeeRequest{Employee: &employeepb.Employee{
+				Id:        employeeId1,
+				FirstName: "John",
+				LastName:  "Doe",
+				Dob:       "1985-05-15",
+				Email:     "john.doe@example.com",
+			}})
+			require.NoError(t, err)
+
+			ctx := context.Background()
+			if tc.fields != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, MetadataFields, tc.fields)
+			}
+
+			got, err := tc.call(ctx, client)
+
+			require.Equal(t, tc.code, status.Code(err))
+			if tc.code != codes.OK {
+				require.Equal(t, []string{handlers.QueryFields}, fieldViolations(t, err))
+				return
+			}
+
+			require.True(t, proto.Equal(tc.want, got), "got %v", got)
+		})
+	}
+}
+
+// Test Health is unavailable once the health service is NOT_SERVING, i.e. the server is draining
+func TestHealthDraining(t *testing.T) {
+	data := map[string]*repos.Employee{}
+
+	svc := services.NewService(logger, false)
+	svc.EmpRepo = repos.NewEmployeeRepo(logger, &data)
+
+	srv := NewServer(logger, svc)
+
+	health, err := srv.Health(context.Background(), &employeepb.HealthRequest{})
+	require.NoError(t, err)
+	require.Equal(t, handlers.HealthStatusOK, health.GetStatus())
+
+	srv.health.Shutdown()
+
+	_, err = srv.Health(context.Background(), &employeepb.HealthRequest{})
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	require.Equal(t, handlers.HealthStatusDraining, status.Convert(err).Message())
+}
@@ -0,0 +1,170 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+	"time"
+
+	"employeeapi/internal/grpcapi/employeepb"
+	"employeeapi/internal/handlers"
+	"employeeapi/internal/repos"
+	"employeeapi/internal/services"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+//go:generate buf generate
+
+const (
+	packageName = "grpcapi"
+
+	// MetadataActor identifies who is making the change; recorded in the audit trail like the X-Actor header
+	MetadataActor = "x-actor"
+
+	// MetadataAuthorization carries the admin token of the admin methods as a bearer token like
+	// the Authorization header
+	MetadataAuthorization = "authorization"
+
+	// MetadataFields selects the fields of the employees in a response like the fields query
+	// parameter, e.g. x-fields: id,first_name,last_name
+	// Callers that mustn't see PII leave out handlers.PIIFields
+	MetadataFields = "x-fields"
+)
+
+// Server implements the EmployeeService on top of the same service as the REST handlers
+type Server struct {
+	employeepb.UnimplementedEmployeeServiceServer
+
+	logger    zerolog.Logger
+	svc       services.Service
+	validator *validator.Validate
+	// adminToken guards the admin methods; see adminOnly
+	adminToken string
+	// health is the status of the standard health service; Health is unavailable once it's
+	// NOT_SERVING
+	health *health.Server
+}
+
+// NewServer creates the EmployeeService server
+// Requests are validated with the rules of the REST handlers
+func NewServer(logger zerolog.Logger, svc services.Service) *Server {
+	return &Server{
+		logger:    logger,
+		svc:       svc,
+		validator: handlers.NewValidator(svc),
+		health:    health.NewServer(),
+	}
+}
+
+// WithAdminToken returns the server with its admin methods accepting the token as a bearer token;
+// they're disabled while no token is set, like the REST admin endpoints
+func (s *Server) WithAdminToken(token string) *Server {
+	s.adminToken = token
+	return s
+}
+
+// NewGRPCServer returns a gRPC server serving the EmployeeService, logging the calls and recovering
+// from panics; reflection is enabled so tools like grpcurl can list the methods
+// The admin methods require the admin token, see WithAdminToken
+// It also serves the standard health service, whose status is returned so it can be set to
+// NOT_SERVING on shutdown like the readiness of the REST handlers
+func NewGRPCServer(logger zerolog.Logger, svc services.Service, adminToken string) (*grpc.Server, *health.Server) {
+	srv := NewServer(logger, svc).WithAdminToken(adminToken)
+
+	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(srv.accessLog, srv.recovery, srv.projection))
+	employeepb.RegisterEmployeeServiceServer(grpcServer, srv)
+	grpc_health_v1.RegisterHealthServer(grpcServer, srv.health)
+	reflection.Register(grpcServer)
+
+	return grpcServer, srv.health
+}
+
+// Logs every call once it's served like the access log of the REST handlers
+func (s *Server) accessLog(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+
+	resp, err := handler(ctx, req)
+
+	code := status.Code(err)
+
+	event := s.logger.Info()
+	if code == codes.Internal || code == codes.Unknown || code == codes.Unavailable {
+		event = s.logger.Error()
+	}
+
+	event.Str("package", packageName).
+		Str("method", info.FullMethod).
+		Str("code", code.String()).
+		Dur("latency", time.Since(start)).
+		Str("actor", actor(ctx)).
+		Msg("call served")
+
+	return resp, err
+}
+
+// Turns panics into Internal errors so a failing call doesn't bring the server down
+func (s *Server) recovery(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			l := s.logger.With().Str("package", packageName).Str("func", "recovery").Logger()
+			l.Error().Interface("panic", r).Str("method", info.FullMethod).Msg("recovered from panic")
+
+			err = status.Error(codes.Internal, codes.Internal.String())
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// Checks that a call to an admin method carries the admin token in its metadata like the
+// AdminOnly middleware of the REST handlers
+func (s *Server) adminOnly(ctx context.Context) error {
+	l := s.logger.With().Str("package", packageName).Str("func", "adminOnly").Logger()
+
+	if s.adminToken == "" {
+		l.Warn().Msg("admin method called while disabled")
+		return status.Error(codes.PermissionDenied, handlers.ErrorAdminDisabled)
+	}
+
+	token := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(MetadataAuthorization); len(values) > 0 {
+			token, ok = strings.CutPrefix(values[0], "Bearer ")
+			if !ok {
+				token = ""
+			}
+		}
+	}
+
+	if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) != 1 {
+		l.Warn().Msg("invalid admin token")
+		return status.Error(codes.Unauthenticated, handlers.ErrorAdminUnauthorized)
+	}
+
+	return nil
+}
+
+// Returns the actor of a call from its metadata
+func actor(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(MetadataActor); len(values) > 0 && values[0] != "" {
+			return values[0]
+		}
+	}
+
+	return handlers.ActorAnonymous
+}
+
+// Builds the context passed to the repository, carrying the actor of the call
+func repoContext(ctx context.Context) context.Context {
+	return repos.ContextWithActor(ctx, actor(ctx))
+}
@@ -0,0 +1,97 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+
+	"employeeapi/internal/grpcapi/employeepb"
+	"employeeapi/internal/repos"
+	"employeeapi/internal/services"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var logger = zerolog.New(os.Stdout)
+
+const (
+	employeeId1 = "1d4b1a5e-7c2f-4b8e-9f3a-2c6d8e0f1a2b"
+	employeeId2 = "6a9c3e1f-5b7d-4f2a-8e0c-3b5d7f9a1c4e"
+
+	adminToken = "test-admin-token"
+)
+
+// Returns a client of a server backed by a repository holding John Doe and Jane Smith, whose admin
+// token is adminToken
+// The server is stopped when the test ends
+func newTestClient(t *testing.T) (employeepb.EmployeeServiceClient, repos.EmployeeRepo) {
+	data := map[string]*repos.Employee{
+		employeeId1: {
+			ID:          employeeId1,
+			FirstName:   "John",
+			LastName:    "Doe",
+			DateOfBirth: "1985-05-15",
+			Email:       "johndoe@example.com",
+			Department:  "Engineering",
+			Role:        "Software Developer",
+		},
+		employeeId2: {
+			ID:          employeeId2,
+			FirstName:   "Jane",
+			LastName:    "Smith",
+			DateOfBirth: "1990-09-22",
+			Email:       "jane.smith@example.com",
+			IsActive:    true,
+			Department:  "Marketing",
+			Role:        "Marketing Specialist",
+			ManagerID:   employeeId1,
+		},
+	}
+
+	svc := services.NewService(logger, false)
+	svc.EmpRepo = repos.NewEmployeeRepo(logger, &data)
+
+	listener := bufconn.Listen(1 << 20)
+	srv, _ := NewGRPCServer(logger, svc, adminToken)
+	go srv.Serve(listener)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err, "failed to connect")
+	t.Cleanup(func() { conn.Close() })
+
+	return employeepb.NewEmployeeServiceClient(conn), svc.EmpRepo
+}
+
+// Test that the actor is read from the metadata
+func TestActor(t *testing.T) {
+	client, repo := newTestClient(t)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), MetadataActor, "payroll-sync")
+
+	_, err := client.DeleteEmployee(ctx, &employeepb.DeleteEmployeeRequest{Id: employeeId2})
+	require.NoError(t, err)
+
+	history, err := repo.GetEmployeeHistory(context.Background(), employeeId2)
+	require.NoError(t, err)
+	require.Equal(t, "payroll-sync", history[len(history)-1].Actor)
+
+	// Calls without an actor are anonymous
+	_, err = client.RestoreEmployee(context.Background(), &employeepb.RestoreEmployeeRequest{Id: employeeId2})
+	require.NoError(t, err)
+
+	history, err = repo.GetEmployeeHistory(context.Background(), employeeId2)
+	require.NoError(t, err)
+	require.Equal(t, "anonymous", history[len(history)-1].Actor)
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ErrorAdminDisabled     = "admin endpoints are disabled"
+	ErrorAdminUnauthorized = "admin token is missing or invalid"
+)
+
+// WithAdminToken returns a copy of the handler whose admin endpoints accept the token as a bearer
+// token; they're disabled while no token is set
+func (h Handler) WithAdminToken(token string) Handler {
+	h.adminToken = token
+	return h
+}
+
+// AdminOnly is the middleware of the admin endpoints: requests must carry the admin token in
+// the Authorization header
+func (h Handler) AdminOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		l := h.requestLogger(c).With().Str("package", packageName).Str("func", "AdminOnly").Logger()
+
+		if h.adminToken == "" {
+			l.Warn().Msg("admin endpoint called while disabled")
+			h.abortWithError(c, &RequestError{Status: http.StatusForbidden, Detail: ErrorAdminDisabled})
+			return
+		}
+
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) != 1 {
+			l.Warn().Msg("invalid admin token")
+			c.Header("WWW-Authenticate", "Bearer")
+			h.abortWithError(c, &RequestError{Status: http.StatusUnauthorized, Detail: ErrorAdminUnauthorized})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"employeeapi/internal/repos"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	BatchOpCreate = repos.BatchOpCreate
+	BatchOpUpdate = repos.BatchOpUpdate
+	BatchOpDelete = repos.BatchOpDelete
+
+	BatchOpCreated = "created"
+	BatchOpUpdated = "updated"
+	BatchOpDeleted = "deleted"
+	BatchOpFailed  = "failed"
+	BatchOpSkipped = "skipped"
+
+	ErrorNoOperations    = "no operations to apply"
+	ErrorUnknownBatchOp  = "unknown operation"
+	ErrorEmployeeMissing = "employee is required"
+)
+
+// Status of each kind of operation once applied
+var batchOpStatuses = map[string]string{
+	BatchOpCreate: BatchOpCreated,
+	BatchOpUpdate: BatchOpUpdated,
+	BatchOpDelete: BatchOpDeleted,
+}
+
+type BatchOperation struct {
+	// Op is create, update or delete
+	Op string `json:"op"`
+	// ID of the employee to update or delete
+	ID string `json:"id,omitempty"`
+	// Employee to create, or the new fields of the employee to update
+	Employee *Employee `json:"employee,omitempty"`
+}
+
+type BatchResult struct {
+	DryRun bool `json:"dry_run"`
+	// Applied is whether the operations were written; never with a dry run
+	Applied    bool                   `json:"applied"`
+	Total      int                    `json:"total"`
+	Operations []BatchOperationResult `json:"operations"`
+}
+
+type BatchOperationResult struct {
+	Index  int    `json:"index"`
+	Op     string `json:"op"`
+	Status string `json:"status"`
+	ID     string `json:"id,omitempty"`
+	// Employee as written, or as it would be with a dry run
+	Employee *Employee            `json:"employee,omitempty"`
+	Errors   []ValidationErrorDtl `json:"errors,omitempty"`
+}
+
+// BatchEmployees applies a JSON array of create, update and delete operations, all of them or none
+// Operations are applied in order and see the writes of the ones before them
+// With dry_run=true every operation is checked the same way but nothing is written
+func (h Handler) BatchEmployees(c *gin.Context) {
+	l := h.requestLogger(c).With().Str("package", packageName).Str("func", "BatchEmployees").Logger()
+
+	dryRun := false
+	if value := c.Query("dry_run"); value != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			l.Error().Err(err).Msg("failed to parse query parameters")
+			h.abortWithError(c, &RequestError{Detail: ErrorInvalidQueryParam, Err: err})
+			return
+		}
+	}
+
+	fields, err := projectionFields(c)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to parse query parameters")
+		h.abortWithError(c, err)
+		return
+	}
+
+	var ops []BatchOperation
+	if err := json.NewDecoder(c.Request.Body).Decode(&ops); err != nil {
+		l.Error().Err(err).Msg("failed to decode batch")
+		h.abortWithError(c, &RequestError{Detail: ErrorInvalidBody, Err: err})
+		return
+	}
+
+	if len(ops) == 0 {
+		l.Error().Msg("batch is empty")
+		h.abortWithError(c, &RequestError{Detail: ErrorNoOperations})
+		return
+	}
+
+	result := BatchResult{
+		DryRun:     dryRun,
+		Total:      len(ops),
+		Operations: make([]BatchOperationResult, len(ops)),
+	}
+
+	repoOps, failed := h.batchOperations(ops, &result)
+	if failed {
+		c.JSON(http.StatusUnprocessableEntity, result)
+		return
+	}
+
+	written, err := h.svc.EmpRepo.ApplyBatch(h.repoContext(c), repoOps, dryRun)
+	if err != nil {
+		var bulkErr *repos.BulkError
+
+		if errors.As(err, &bulkErr) {
+			if opErr, ok := batchOpError(bulkErr.Err); ok {
+				l.Error().Err(err).Msg("failed to apply batch")
+
+				result.Operations[bulkErr.Index].Status = BatchOpFailed
+				result.Operations[bulkErr.Index].Errors = []ValidationErrorDtl{opErr}
+				c.JSON(http.StatusUnprocessableEntity, result)
+				return
+			}
+		}
+
+		l.Error().Err(err).Msg("failed to apply batch")
+		h.abortWithError(c, err)
+		return
+	}
+
+	for i, emp := range written {
+		op := &result.Operations[i]
+		op.Status = batchOpStatuses[op.Op]
+
+		resp := newEmployee(emp)
+		op.Employee = &resp
+
+		// Employees created by a dry run don't exist so their ids mean nothing
+		if dryRun && op.Op == BatchOpCreate {
+			op.Employee.ID = ""
+		} else {
+			op.ID = emp.ID
+		}
+	}
+
+	result.Applied = !dryRun
+
+	l.Info().Bool("dryRun", dryRun).Int("total", result.Total).Msg("applied batch")
+
+	h.projectedJSON(c, http.StatusOK, result, fields)
+}
+
+// Validates the operations with the same rules as their own endpoints and maps them to
+// the repository operations
+// Returns whether an operation is invalid, in which case its result reports why
+// and the others are skipped
+// Updates are merged into the employees by the repository under its write lock, so an employee
+// that doesn't exist is only reported once the batch is applied
+func (h Handler) batchOperations(ops []BatchOperation, result *BatchResult) ([]*repos.BatchOperation, bool) {
+	repoOps := make([]*repos.BatchOperation, len(ops))
+	failed := false
+
+	for i, op := range ops {
+		result.Operations[i] = BatchOperationResult{Index: i, Op: op.Op, ID: op.ID, Status: BatchOpSkipped}
+
+		repoOp, errs := h.batchOperation(op)
+		if len(errs) > 0 {
+			result.Operations[i].Status = BatchOpFailed
+			result.Operations[i].Errors = errs
+			failed = true
+			continue
+		}
+
+		repoOps[i] = repoOp
+	}
+
+	return repoOps, failed
+}
+
+func (h Handler) batchOperation(op BatchOperation) (*repos.BatchOperation, []ValidationErrorDtl) {
+	if _, ok := batchOpStatuses[op.Op]; !ok {
+		return nil, []ValidationErrorDtl{{Field: "Op", Message: ErrorUnknownBatchOp}}
+	}
+
+	if op.Op != BatchOpCreate && op.ID == "" {
+		return nil, []ValidationErrorDtl{{Field: "ID", Message: ErrorIDRequired}}
+	}
+
+	if op.Op == BatchOpDelete {
+		return &repos.BatchOperation{Op: op.Op, ID: op.ID}, nil
+	}
+
+	if op.Employee == nil {
+		return nil, []ValidationErrorDtl{{Field: "Employee", Message: ErrorEmployeeMissing}}
+	}
+
+	if err := h.jsonValidator.Struct(op.Employee); err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+			return nil, ValidationErrorDetails(validationErrors)
+		}
+		return nil, []ValidationErrorDtl{{Message: err.Error()}}
+	}
+
+	if op.Op == BatchOpCreate {
+		return &repos.BatchOperation{Op: op.Op, Employee: NewRepoEmployee(*op.Employee)}, nil
+	}
+
+	return &repos.BatchOperation{Op: op.Op, ID: op.ID, Update: NewEmployeeUpdate(*op.Employee)}, nil
+}
+
+// Converts a repository error caused by an operation to the error reported for the operation
+func batchOpError(err error) (ValidationErrorDtl, bool) {
+	switch {
+	case errors.Is(err, repos.ErrNotFound):
+		return ValidationErrorDtl{Field: "ID", Message: ErrorEmpNotFound}, true
+	case errors.Is(err, repos.ErrManagerCycle):
+		return ValidationErrorDtl{Field: "ManagerID", Message: ErrorManagerCycle}, true
+	}
+
+	return importRowError(err)
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"employeeapi/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// Test BatchEmployees handler
+func TestBatchEmployees(t *testing.T) {
+	create := `{"op":"create","employee":{"first_name":"Emily","last_name":"Williams","dob":"1995-12-08","email":"emily.williams@example.com"}}`
+	update := `{"op":"update","id":"` + employeeId1 + `","employee":{"first_name":"John","last_name":"Doe","dob":"1985-05-15","email":"johndoe@example.com","is_active":true,"department":"Finance"}}`
+	remove := `{"op":"delete","id":"` + employeeId2 + `"}`
+
+	testCases := []struct {
+		name       string
+		query      string
+		body       string
+		httpStatus int
+		applied    bool
+		statuses   []string
+		employees  int
+	}{
+		{
+			name:       "Successful - Batch Employees",
+			body:       "[" + create + "," + update + "," + remove + "]",
+			httpStatus: http.StatusOK,
+			applied:    true,
+			statuses:   []string{BatchOpCreated, BatchOpUpdated, BatchOpDeleted},
+			employees:  2,
+		},
+		{
+			name:       "Successful - Batch Employees - dry run",
+			query:      "?dry_run=true",
+			body:       "[" + create + "," + update + "," + remove + "]",
+			httpStatus: http.StatusOK,
+			statuses:   []string{BatchOpCreated, BatchOpUpdated, BatchOpDeleted},
+			employees:  2,
+		},
+		{
+			name: "Successful - Batch Employees - same employee updated twice",
+			body: "[" + update + "," +
+				`{"op":"update","id":"` + employeeId1 + `","employee":{"first_name":"Johnny","last_name":"Doe","dob":"1985-05-15","email":"johndoe@example.com","is_active":true}}]`,
+			httpStatus: http.StatusOK,
+			applied:    true,
+			statuses:   []string{BatchOpUpdated, BatchOpUpdated},
+			employees:  2,
+		},
+		{
+			name:       "Failed - Batch Employees - invalid operation",
+			body:       "[" + create + `,{"op":"create","employee":{"first_name":"Mark"}},{"op":"upsert"},{"op":"delete"}]`,
+			httpStatus: http.StatusUnprocessableEntity,
+			statuses:   []string{BatchOpSkipped, BatchOpFailed, BatchOpFailed, BatchOpFailed},
+			employees:  2,
+		},
+		{
+			name:       "Failed - Batch Employees - employee deleted earlier in the batch",
+			body:       "[" + create + "," + remove + "," + remove + "]",
+			httpStatus: http.StatusUnprocessableEntity,
+			statuses:   []string{BatchOpSkipped, BatchOpSkipped, BatchOpFailed},
+			employees:  2,
+		},
+		{
+			name: "Failed - Batch Employees - employee not found",
+			body: "[" + create + "," +
+				`{"op":"update","id":"` + uuid.New().String() + `","employee":{"first_name":"John","last_name":"Doe","dob":"1985-05-15","email":"johndoe@example.com"}}]`,
+			httpStatus: http.StatusUnprocessableEntity,
+			statuses:   []string{BatchOpSkipped, BatchOpFailed},
+			employees:  2,
+		},
+		{
+			name:       "Failed - Batch Employees - empty",
+			body:       `[]`,
+			httpStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Failed - Batch Employees - invalid dry run",
+			query:      "?dry_run=maybe",
+			body:       "[" + remove + "]",
+			httpStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, data := mockRepo("mult")
+
+			svc := services.NewService(logger, false)
+			svc.EmpRepo = repo
+
+			h := NewHandler(logger, svc)
+
+			req, err := http.NewRequest("POST", "/employees:batch"+tc.query, strings.NewReader(tc.body))
+			require.NoError(t, err, "failed to create request")
+
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+
+			_, r := gin.CreateTestContext(rr)
+
+			// Set up routes
+			h.SetupRoutes(r)
+
+			// Call the handler
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.httpStatus, rr.Code)
+
+			if tc.statuses == nil {
+				return
+			}
+
+			result := BatchResult{}
+			err = json.Unmarshal(rr.Body.Bytes(), &result)
+			require.NoError(t, err, "failed to unmarshal response body")
+
+			require.Equal(t, tc.applied, result.Applied)
+			require.Equal(t, len(tc.statuses), result.Total)
+
+			statuses := make([]string, 0)
+			for _, op := range result.Operations {
+				statuses = append(statuses, op.Status)
+
+				if op.Status == BatchOpFailed {
+					require.NotEmpty(t, op.Errors)
+				}
+			}
+			require.Equal(t, tc.statuses, statuses)
+
+			if !tc.applied {
+				require.Len(t, *data, tc.employees)
+				require.Nil(t, (*data)[employeeId2].DeletedAt)
+				require.Equal(t, "Engineering", (*data)[employeeId1].Department)
+				return
+			}
+
+			// The create adds an employee
+			if tc.statuses[0] == BatchOpCreated {
+				require.Len(t, *data, tc.employees+1)
+				require.NotEmpty(t, result.Operations[0].ID)
+				require.NotNil(t, (*data)[employeeId2].DeletedAt)
+			}
+
+			require.Equal(t, "Finance", (*data)[employeeId1].Department)
+			require.True(t, (*data)[employeeId1].IsActive)
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"employeeapi/internal/repos"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type CatalogueEntry struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"max=500"`
+}
+
+// catalogueOps are the repository operations of one kind of catalogue entry (departments or roles)
+type catalogueOps struct {
+	kind   string
+	list   func(ctx context.Context) ([]*repos.CatalogueEntry, error)
+	get    func(ctx context.Context, name string) (*repos.CatalogueEntry, error)
+	create func(ctx context.Context, entry *repos.CatalogueEntry) (*repos.CatalogueEntry, error)
+	update func(ctx context.Context, entry *repos.CatalogueEntry) (*repos.CatalogueEntry, error)
+	delete func(ctx context.Context, name string) error
+}
+
+// Sets up the CRUD routes of a catalogue under the given path
+func (h Handler) setupCatalogueRoutes(gin *gin.Engine, path string, ops catalogueOps) {
+	gin.GET(path, h.getCatalogueEntries(ops))
+	gin.GET(path+"/:name", h.getCatalogueEntry(ops))
+	gin.POST(path, h.createCatalogueEntry(ops))
+	gin.PUT(path+"/:name", h.updateCatalogueEntry(ops))
+	gin.DELETE(path+"/:name", h.deleteCatalogueEntry(ops))
+}
+
+func (h Handler) getCatalogueEntries(ops catalogueOps) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		l := h.requestLogger(c).With().Str("package", packageName).Str("func", "getCatalogueEntries").Str("kind", ops.kind).Logger()
+
+		entries, err := ops.list(h.repoContext(c))
+		if err != nil {
+			l.Error().Err(err).Msg("failed to get catalogue entries")
+			h.abortWithError(c, err)
+			return
+		}
+
+		resp := make([]CatalogueEntry, 0)
+		for _, entry := range entries {
+			resp = append(resp, CatalogueEntry{
+				Name:        entry.Name,
+				Description: entry.Description,
+			})
+		}
+
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+func (h Handler) getCatalogueEntry(ops catalogueOps) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		l := h.requestLogger(c).With().Str("package", packageName).Str("func", "getCatalogueEntry").Str("kind", ops.kind).Logger()
+
+		name := c.Param("name")
+
+		entry, err := ops.get(h.repoContext(c), name)
+		if err != nil {
+			l.Error().Err(err).Str("name", name).Msg("failed to get catalogue entry")
+
+			h.abortWithError(c, err, ops.kind+" not found")
+			return
+		}
+
+		c.JSON(http.StatusOK, CatalogueEntry{
+			Name:        entry.Name,
+			Description: entry.Description,
+		})
+	}
+}
+
+func (h Handler) createCatalogueEntry(ops catalogueOps) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		l := h.requestLogger(c).With().Str("package", packageName).Str("func", "createCatalogueEntry").Str("kind", ops.kind).Logger()
+
+		var entry CatalogueEntry
+		if err := c.ShouldBindJSON(&entry); err != nil {
+			l.Error().Err(err).Msg("failed to bind json")
+			h.abortWithError(c, &RequestError{Detail: ErrorInvalidBody, Err: err})
+			return
+		}
+
+		if err := h.jsonValidator.Struct(entry); err != nil {
+			l.Error().Err(err).Msg("failed to validate json")
+
+			h.abortWithError(c, err)
+			return
+		}
+
+		_, err := ops.create(h.repoContext(c), &repos.CatalogueEntry{
+			Name:        entry.Name,
+			Description: entry.Description,
+		})
+		if err != nil {
+			l.Error().Err(err).Msg("failed to create catalogue entry")
+
+			h.abortWithError(c, err, fmt.Sprintf("%s already exists", ops.kind))
+			return
+		}
+
+		c.JSON(http.StatusCreated, entry)
+	}
+}
+
+func (h Handler) updateCatalogueEntry(ops catalogueOps) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		l := h.requestLogger(c).With().Str("package", packageName).Str("func", "updateCatalogueEntry").Str("kind", ops.kind).Logger()
+
+		var entry CatalogueEntry
+		if err := c.ShouldBindJSON(&entry); err != nil {
+			l.Error().Err(err).Msg("failed to bind json")
+			h.abortWithError(c, &RequestError{Detail: ErrorInvalidBody, Err: err})
+			return
+		}
+
+		// The name identifies the entry so it's taken from the path
+		entry.Name = c.Param("name")
+
+		if err := h.jsonValidator.Struct(entry); err != nil {
+			l.Error().Err(err).Msg("failed to validate json")
+
+			h.abortWithError(c, err)
+			return
+		}
+
+		updated, err := ops.update(h.repoContext(c), &repos.CatalogueEntry{
+			Name:        entry.Name,
+			Description: entry.Description,
+		})
+		if err != nil {
+			l.Error().Err(err).Str("name", entry.Name).Msg("failed to update catalogue entry")
+
+			h.abortWithError(c, err, ops.kind+" not found")
+			return
+		}
+
+		c.JSON(http.StatusOK, CatalogueEntry{
+			Name:        updated.Name,
+			Description: updated.Description,
+		})
+	}
+}
+
+func (h Handler) deleteCatalogueEntry(ops catalogueOps) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		l := h.requestLogger(c).With().Str("package", packageName).Str("func", "deleteCatalogueEntry").Str("kind", ops.kind).Logger()
+
+		name := c.Param("name")
+
+		if err := ops.delete(h.repoContext(c), name); err != nil {
+			l.Error().Err(err).Str("name", name).Msg("failed to delete catalogue entry")
+
+			if errors.Is(err, repos.ErrInUse) {
+				h.abortWithError(c, err, ops.kind+" is still in use")
+			} else {
+				h.abortWithError(c, err, ops.kind+" not found")
+			}
+			return
+		}
+
+		c.Status(http.StatusOK)
+	}
+}
+
+// Custom validation that checks the value exists in a catalogue
+func catalogueEntryExists(get func(ctx context.Context, name string) (*repos.CatalogueEntry, error)) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		name, ok := fl.Field().Interface().(string)
+		if !ok {
+			return false
+		}
+
+		_, err := get(context.Background(), name)
+		return err == nil
+	}
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"employeeapi/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// Test the department and role catalogue handlers
+func TestCatalogue(t *testing.T) {
+	testCases := []struct {
+		name       string
+		method     string
+		path       string
+		body       any
+		httpStatus int
+	}{
+		{
+			name:       "Successful - Get Departments",
+			method:     "GET",
+			path:       "/departments",
+			httpStatus: http.StatusOK,
+		},
+		{
+			name:       "Successful - Get Role",
+			method:     "GET",
+			path:       "/roles/Software%20Developer",
+			httpStatus: http.StatusOK,
+		},
+		{
+			name:       "Failed - Get Role - not found",
+			method:     "GET",
+			path:       "/roles/Astronaut",
+			httpStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Successful - Create Department",
+			method:     "POST",
+			path:       "/departments",
+			body:       CatalogueEntry{Name: "Legal"},
+			httpStatus: http.StatusCreated,
+		},
+		{
+			name:       "Failed - Create Department - already exists",
+			method:     "POST",
+			path:       "/departments",
+			body:       CatalogueEntry{Name: "Engineering"},
+			httpStatus: http.StatusConflict,
+		},
+		{
+			name:       "Failed - Create Role - name required",
+			method:     "POST",
+			path:       "/roles",
+			body:       CatalogueEntry{},
+			httpStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Successful - Update Role",
+			method:     "PUT",
+			path:       "/roles/HR%20Specialist",
+			body:       CatalogueEntry{Description: "People operations"},
+			httpStatus: http.StatusOK,
+		},
+		{
+			name:       "Successful - Delete Department",
+			method:     "DELETE",
+			path:       "/departments/Finance",
+			httpStatus: http.StatusOK,
+		},
+		{
+			name:       "Failed - Delete Department - in use",
+			method:     "DELETE",
+			path:       "/departments/Engineering",
+			httpStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, _ := mockRepo("single")
+
+			svc := services.NewService(logger, false)
+			svc.EmpRepo = repo
+
+			h := NewHandler(logger, svc)
+
+			var body []byte
+			if tc.body != nil {
+				var err error
+				body, err = json.Marshal(tc.body)
+				require.NoError(t, err, "failed to marshal JSON")
+			}
+
+			// Create a request to pass to our handler
+			req, err := http.NewRequest(tc.method, tc.path, bytes.NewBuffer(body))
+			require.NoError(t, err, "failed to create request")
+
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+
+			_, r := gin.CreateTestContext(rr)
+
+			// Set up routes
+			h.SetupRoutes(r)
+
+			// Call the handler
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.httpStatus, rr.Code)
+		})
+	}
+}
+
+// Test that employees are validated against the catalogue at request time
+func TestCreateEmployeeWithCatalogue(t *testing.T) {
+	repo, _ := mockRepo()
+
+	svc := services.NewService(logger, false)
+	svc.EmpRepo = repo
+
+	h := NewHandler(logger, svc)
+
+	rr := httptest.NewRecorder()
+
+	_, r := gin.CreateTestContext(rr)
+
+	// Set up routes
+	h.SetupRoutes(r)
+
+	employee := Employee{
+		FirstName:   "John",
+		LastName:    "Doe",
+		DateOfBirth: "1985-05-15",
+		Email:       "johndoe@example.com",
+		Department:  "Legal",
+	}
+
+	body, err := json.Marshal(employee)
+	require.NoError(t, err, "failed to marshal JSON")
+
+	// Unknown department
+	req, err := http.NewRequest("POST", "/employees", bytes.NewBuffer(body))
+	require.NoError(t, err, "failed to create request")
+	req.Header.Set("Content-Type", "application/json")
+
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	// Add the department to the catalogue
+	deptBody, err := json.Marshal(CatalogueEntry{Name: "Legal"})
+	require.NoError(t, err, "failed to marshal JSON")
+
+	req, err = http.NewRequest("POST", "/departments", bytes.NewBuffer(deptBody))
+	require.NoError(t, err, "failed to create request")
+	req.Header.Set("Content-Type", "application/json")
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusCreated, rr.Code)
+
+	// Same request is now accepted
+	req, err = http.NewRequest("POST", "/employees", bytes.NewBuffer(body))
+	require.NoError(t, err, "failed to create request")
+	req.Header.Set("Content-Type", "application/json")
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusCreated, rr.Code)
+
+	empResp := Employee{}
+	err = json.Unmarshal(rr.Body.Bytes(), &empResp)
+	require.NoError(t, err, "failed to unmarshal response body")
+
+	require.Equal(t, "Legal", empResp.Department)
+	require.Equal(t, RoleUnassigned, empResp.Role)
+}
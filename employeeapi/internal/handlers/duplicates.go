@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DuplicateGroup struct {
+	FirstName   string     `json:"first_name"`
+	LastName    string     `json:"last_name"`
+	DateOfBirth string     `json:"dob"`
+	Employees   []Employee `json:"employees"`
+}
+
+// GetDuplicateEmployees reports employees that are likely duplicates,
+// i.e. that share the same name and date of birth
+func (h Handler) GetDuplicateEmployees(c *gin.Context) {
+	l := h.requestLogger(c).With().Str("package", packageName).Str("func", "GetDuplicateEmployees").Logger()
+
+	fields, err := projectionFields(c)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to parse query parameters")
+		h.abortWithError(c, err)
+		return
+	}
+
+	groups, err := h.svc.EmpRepo.FindDuplicateEmployees(h.repoContext(c))
+	if err != nil {
+		l.Error().Err(err).Msg("failed to find duplicate employees")
+		h.abortWithError(c, err)
+		return
+	}
+
+	resp := make([]DuplicateGroup, 0)
+	for _, group := range groups {
+		resp = append(resp, DuplicateGroup{
+			FirstName:   group[0].FirstName,
+			LastName:    group[0].LastName,
+			DateOfBirth: group[0].DateOfBirth,
+			Employees:   newEmployees(group),
+		})
+	}
+
+	h.projectedJSON(c, http.StatusOK, resp, fields)
+}
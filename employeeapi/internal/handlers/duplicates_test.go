@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"employeeapi/internal/repos"
+	"employeeapi/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// Test that creating and updating with an email in use returns a conflict
+func TestEmailConflict(t *testing.T) {
+	_, data := mockRepo("mult")
+	emp1 := (*data)[employeeId1]
+	emp2 := (*data)[employeeId2]
+
+	testCases := []struct {
+		name       string
+		method     string
+		path       string
+		employee   Employee
+		httpStatus int
+	}{
+		{
+			name:   "Failed - Create Employee - email in use",
+			method: "POST",
+			path:   "/employees",
+			employee: Employee{
+				FirstName:   "Johnny",
+				LastName:    emp1.LastName,
+				DateOfBirth: emp1.DateOfBirth,
+				Email:       strings.ToUpper(emp1.Email),
+			},
+			httpStatus: http.StatusConflict,
+		},
+		{
+			name:   "Failed - Update Employee - email in use",
+			method: "PUT",
+			path:   "/employees/" + employeeId1,
+			employee: Employee{
+				FirstName:   emp1.FirstName,
+				LastName:    emp1.LastName,
+				DateOfBirth: emp1.DateOfBirth,
+				Email:       emp2.Email,
+			},
+			httpStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, _ := mockRepo("mult")
+
+			svc := services.NewService(logger, false)
+			svc.EmpRepo = repo
+
+			h := NewHandler(logger, svc)
+
+			body, err := json.Marshal(tc.employee)
+			require.NoError(t, err, "failed to marshal JSON")
+
+			// Create a request to pass to our handler
+			req, err := http.NewRequest(tc.method, tc.path, bytes.NewBuffer(body))
+			require.NoError(t, err, "failed to create request")
+
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+
+			_, r := gin.CreateTestContext(rr)
+
+			// Set up routes
+			h.SetupRoutes(r)
+
+			// Call the handler
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.httpStatus, rr.Code)
+
+			errResp := Problem{}
+			err = json.Unmarshal(rr.Body.Bytes(), &errResp)
+			require.NoError(t, err, "failed to unmarshal response body")
+
+			require.Equal(t, ErrorEmailInUse, errResp.Detail)
+		})
+	}
+}
+
+// Test GetDuplicateEmployees handler
+func TestGetDuplicateEmployees(t *testing.T) {
+	repo, data := mockRepo("mult")
+
+	// Same person registered twice with a different email
+	(*data)["duplicate"] = &repos.Employee{
+		ID:          "duplicate",
+		FirstName:   "JOHN",
+		LastName:    "doe",
+		DateOfBirth: "1985-05-15",
+		Email:       "john.doe@example.org",
+	}
+
+	svc := services.NewService(logger, false)
+	svc.EmpRepo = repo
+
+	h := NewHandler(logger, svc)
+
+	// Create a request to pass to our handler
+	req, err := http.NewRequest("GET", "/employees/duplicates", nil)
+	require.NoError(t, err, "failed to create request")
+
+	rr := httptest.NewRecorder()
+
+	_, r := gin.CreateTestContext(rr)
+
+	// Set up routes
+	h.SetupRoutes(r)
+
+	// Call the handler
+	r.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	groups := []DuplicateGroup{}
+	err = json.Unmarshal(rr.Body.Bytes(), &groups)
+	require.NoError(t, err, "failed to unmarshal response body")
+
+	require.Len(t, groups, 1)
+	require.Len(t, groups[0].Employees, 2)
+	require.Equal(t, "1985-05-15", groups[0].DateOfBirth)
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"employeeapi/internal/repos"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	// ContentTypeProblem is the content type of error responses
+	ContentTypeProblem = "application/problem+json"
+
+	// ProblemTypeDefault means the problem has no semantics beyond its status code
+	ProblemTypeDefault = "about:blank"
+
+	ErrorInvalidBody = "invalid request body"
+)
+
+// Problem is the RFC 7807 body of error responses
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Errors lists the invalid fields of a request
+	Errors []ValidationErrorDtl `json:"errors,omitempty"`
+}
+
+// RequestError is an error in the request itself, e.g. a malformed body or query parameter
+// It's responded with Status, 400 if not set
+type RequestError struct {
+	Status int
+	Detail string
+	Err    error
+	// Errors lists the invalid fields of the request, if known
+	Errors []ValidationErrorDtl
+}
+
+func (r *RequestError) Error() string {
+	if r.Err != nil {
+		return r.Detail + ": " + r.Err.Error()
+	}
+	return r.Detail
+}
+
+func (r *RequestError) Unwrap() error {
+	return r.Err
+}
+
+// ErrorHandler is the middleware sending the problem response of the error a handler
+// aborted with; see abortWithError
+func (h Handler) ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		ginErr := c.Errors.Last()
+
+		problem := newProblem(ginErr.Err)
+		problem.Instance = c.Request.URL.Path
+
+		// Only client errors are detailed; server errors could leak internals
+		if detail, ok := ginErr.Meta.(string); ok && detail != "" && problem.Status < http.StatusInternalServerError {
+			problem.Detail = detail
+		}
+
+		c.Header("Content-Type", ContentTypeProblem)
+		c.JSON(problem.Status, problem)
+	}
+}
+
+// RouteNotFound sends the problem response of requests to unknown routes
+func (h Handler) RouteNotFound(c *gin.Context) {
+	h.abortWithError(c, &RequestError{Status: http.StatusNotFound, Detail: ErrorRouteNotFound})
+}
+
+// Aborts the request with an error; the error middleware maps it to the status code
+// and sends the problem response
+// detail describes a client error in the response instead of the error message
+func (h Handler) abortWithError(c *gin.Context, err error, detail ...string) {
+	ginErr := c.Error(err)
+	if len(detail) > 0 {
+		ginErr.SetMeta(detail[0])
+	}
+
+	c.Abort()
+}
+
+// Maps an error to its problem:
+// request and validation errors are 400, missing records 404, conflicts 409, bodies over the cap 413
+// and anything else 500
+func newProblem(err error) Problem {
+	var (
+		requestErr      *RequestError
+		validationErrs  validator.ValidationErrors
+		repoValidateErr *repos.ValidationError
+		maxBytesErr     *http.MaxBytesError
+	)
+
+	status := http.StatusInternalServerError
+	detail := ""
+	var errDtls []ValidationErrorDtl
+
+	switch {
+	// Checked first since reading past the cap fails a request like a malformed body
+	case errors.As(err, &maxBytesErr):
+		status = http.StatusRequestEntityTooLarge
+		detail = ErrorBodyTooLarge
+	case errors.As(err, &requestErr):
+		status = requestErr.Status
+		if status == 0 {
+			status = http.StatusBadRequest
+		}
+		detail = requestErr.Error()
+		errDtls = requestErr.Errors
+	case errors.As(err, &validationErrs):
+		status = http.StatusBadRequest
+		errDtls = ValidationErrorDetails(validationErrs)
+	case errors.As(err, &repoValidateErr):
+		status = http.StatusBadRequest
+		detail = repoValidateErr.Err.Error()
+		errDtls = []ValidationErrorDtl{{Field: repoValidateErr.Field, Message: repoValidateErr.Err.Error()}}
+	case errors.Is(err, repos.ErrValidation):
+		status = http.StatusBadRequest
+		detail = err.Error()
+	case errors.Is(err, repos.ErrNotFound):
+		status = http.StatusNotFound
+		detail = err.Error()
+	case errors.Is(err, repos.ErrConflict):
+		status = http.StatusConflict
+		detail = err.Error()
+	}
+
+	return Problem{
+		Type:   ProblemTypeDefault,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Errors: errDtls,
+	}
+}
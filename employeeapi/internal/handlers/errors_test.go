@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"employeeapi/internal/repos"
+	"employeeapi/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// Test that errors are sent as problem responses by the error middleware
+func TestErrorHandler(t *testing.T) {
+	testCases := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		httpStatus int
+		detail     string
+		errors     []ValidationErrorDtl
+	}{
+		{
+			name:       "Failed - Create Employee - malformed body",
+			method:     "POST",
+			path:       "/employees",
+			body:       `{"first_name": "John",`,
+			httpStatus: http.StatusBadRequest,
+			detail:     ErrorInvalidBody,
+			errors:     []ValidationErrorDtl{{Message: "unexpected EOF"}},
+		},
+		{
+			name:       "Failed - Update Employee - wrong type",
+			method:     "PUT",
+			path:       "/employees/" + employeeId1,
+			body:       `{"first_name": 1}`,
+			httpStatus: http.StatusBadRequest,
+			detail:     ErrorInvalidBody,
+			errors: []ValidationErrorDtl{
+				{Field: "first_name", Message: "value must be a string"},
+				{Field: "last_name", Message: `property "last_name" is missing`},
+				{Field: "dob", Message: `property "dob" is missing`},
+				{Field: "email", Message: `property "email" is missing`},
+			},
+		},
+		{
+			name:       "Failed - Create Employee - manager not found",
+			method:     "POST",
+			path:       "/employees",
+			body:       `{"first_name": "John", "last_name": "Smith", "dob": "1990-01-01", "email": "john.smith@example.com", "manager_id": "missing"}`,
+			httpStatus: http.StatusBadRequest,
+			detail:     ErrorManagerNotFound,
+			errors:     []ValidationErrorDtl{{Field: "manager_id", Message: repos.ManagerNotFound}},
+		},
+		{
+			name:       "Failed - Get Employee - not found",
+			method:     "GET",
+			path:       "/employees/" + uuid.New().String(),
+			httpStatus: http.StatusNotFound,
+			detail:     ErrorEmpNotFound,
+		},
+		{
+			name:       "Failed - Create Employee - email in use",
+			method:     "POST",
+			path:       "/employees",
+			body:       `{"first_name": "John", "last_name": "Smith", "dob": "1990-01-01", "email": "johndoe@example.com"}`,
+			httpStatus: http.StatusConflict,
+			detail:     ErrorEmailInUse,
+		},
+		{
+			name:       "Failed - Unknown route",
+			method:     "GET",
+			path:       "/managers",
+			httpStatus: http.StatusNotFound,
+			detail:     ErrorRouteNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, _ := mockRepo("single")
+
+			svc := services.NewService(logger, false)
+			svc.EmpRepo = repo
+
+			h := NewHandler(logger, svc)
+
+			// Create a request to pass to our handler
+			req, err := http.NewRequest(tc.method, tc.path, bytes.NewBufferString(tc.body))
+			require.NoError(t, err, "failed to create request")
+
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+
+			_, r := gin.CreateTestContext(rr)
+
+			// Set up routes
+			h.SetupRoutes(r)
+
+			// Call the handler
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.httpStatus, rr.Code)
+			require.Equal(t, ContentTypeProblem, rr.Header().Get("Content-Type"))
+
+			problem := Problem{}
+			err = json.Unmarshal(rr.Body.Bytes(), &problem)
+			require.NoError(t, err, "failed to unmarshal response body")
+
+			require.Equal(t, Problem{
+				Type:     ProblemTypeDefault,
+				Title:    http.StatusText(tc.httpStatus),
+				Status:   tc.httpStatus,
+				Detail:   tc.detail,
+				Instance: tc.path,
+				Errors:   tc.errors,
+			}, problem)
+		})
+	}
+}
+
+// Test that errors are mapped to the status code of their kind
+func TestNewProblem(t *testing.T) {
+	testCases := []struct {
+		name       string
+		err        error
+		httpStatus int
+	}{
+		{
+			name:       "Request error",
+			err:        &RequestError{Detail: ErrorIDRequired},
+			httpStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Request error - with status",
+			err:        &RequestError{Status: http.StatusUnsupportedMediaType},
+			httpStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:       "Not found",
+			err:        repos.ErrNotFound,
+			httpStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Conflict - wrapped",
+			err:        &repos.BulkError{Index: 1, Err: &repos.ConflictError{Field: "email"}},
+			httpStatus: http.StatusConflict,
+		},
+		{
+			name:       "Conflict - in use",
+			err:        repos.ErrInUse,
+			httpStatus: http.StatusConflict,
+		},
+		{
+			name:       "Validation",
+			err:        &repos.ValidationError{Field: "manager_id", Err: repos.ErrManagerNotFound},
+			httpStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Internal",
+			err:        errors.New("disk full"),
+			httpStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			problem := newProblem(tc.err)
+
+			require.Equal(t, tc.httpStatus, problem.Status)
+			require.Equal(t, http.StatusText(tc.httpStatus), problem.Title)
+
+			// Server errors don't leak details
+			if tc.httpStatus == http.StatusInternalServerError {
+				require.Empty(t, problem.Detail)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"employeeapi/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ContentTypeEventStream = "text/event-stream"
+
+	// HeaderLastEventID is sent by clients reconnecting to the change stream with the id of the
+	// last event they got
+	HeaderLastEventID = "Last-Event-ID"
+
+	// EventStreamReset is sent first when some events after Last-Event-ID were missed:
+	// the client has to reload the employees rather than rely on the events
+	EventStreamReset = "reset"
+
+	ErrorInvalidLastEventID = "invalid Last-Event-ID"
+)
+
+// Interval of the comments sent to keep idle streams open through proxies
+var eventStreamHeartbeat = 15 * time.Second
+
+type EmployeeEvent struct {
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Actor      string    `json:"actor"`
+	Employee   Employee  `json:"employee"`
+}
+
+// StreamEmployeeEvents streams the employee events as Server-Sent Events until the client disconnects
+// A client reconnecting with Last-Event-ID first gets the events it missed; if some were
+// already forgotten a reset event tells it to reload the employees
+// The stream ends when the client falls too far behind so it can resume from where it was
+func (h Handler) StreamEmployeeEvents(c *gin.Context) {
+	l := h.requestLogger(c).With().Str("package", packageName).Str("func", "StreamEmployeeEvents").Logger()
+
+	fields, err := projectionFields(c)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to parse query parameters")
+		h.abortWithError(c, err)
+		return
+	}
+
+	var lastID uint64
+	resume := false
+
+	if value := c.GetHeader(HeaderLastEventID); value != "" {
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			l.Error().Err(err).Str("lastEventID", value).Msg("invalid last event id")
+			h.abortWithError(c, &RequestError{Detail: ErrorInvalidLastEventID, Err: err})
+			return
+		}
+
+		lastID, resume = id, true
+	}
+
+	sub, missed, complete := h.svc.Events.Subscribe(lastID, resume)
+	defer h.svc.Events.Unsubscribe(sub)
+
+	c.Header("Content-Type", ContentTypeEventStream)
+	c.Header("Cache-Control", "no-cache")
+	// Proxies mustn't buffer the stream
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	if !complete {
+		l.Warn().Uint64("lastEventID", lastID).Msg("events were missed since the last event id")
+
+		if _, err := fmt.Fprintf(c.Writer, "event: %s\ndata: {}\n\n", EventStreamReset); err != nil {
+			return
+		}
+	}
+
+	for _, event := range missed {
+		if err := writeStreamEvent(c, event, fields); err != nil {
+			return
+		}
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(eventStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-sub.Events:
+			if !ok {
+				l.Info().Msg("event stream closed")
+				return
+			}
+
+			if err := writeStreamEvent(c, event, fields); err != nil {
+				l.Error().Err(err).Msg("failed to write event")
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+
+		c.Writer.Flush()
+	}
+}
+
+// Writes an event in the text/event-stream format with the given fields of its employee;
+// its JSON is a single data line
+func writeStreamEvent(c *gin.Context, event *services.StreamEvent, fields []string) error {
+	resp, err := project(EmployeeEvent{
+		Type:       event.Type,
+		OccurredAt: event.OccurredAt,
+		Actor:      event.Actor,
+		Employee:   newEmployee(event.Employee),
+	}, fields)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
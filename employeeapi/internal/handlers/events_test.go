@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"employeeapi/internal/repos"
+	"employeeapi/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// Reads the next event of a text/event-stream, skipping comments
+func readStreamEvent(t *testing.T, r *bufio.Reader) map[string]string {
+	event := make(map[string]string)
+
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err, "failed to read event")
+
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "" && len(event) > 0:
+			return event
+		case line == "" || strings.HasPrefix(line, ":"):
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ": ")
+		event[field] = value
+	}
+}
+
+// Test StreamEmployeeEvents handler
+func TestStreamEmployeeEvents(t *testing.T) {
+	testCases := []struct {
+		name        string
+		lastEventID string
+		httpStatus  int
+		// Events expected before the one of the employee created once connected
+		events []string
+		reset  bool
+	}{
+		{
+			name:       "Successful - Stream Employee Events",
+			httpStatus: http.StatusOK,
+		},
+		{
+			name:        "Successful - Stream Employee Events - resumed",
+			lastEventID: "1",
+			httpStatus:  http.StatusOK,
+			events:      []string{"2"},
+		},
+		{
+			name:        "Successful - Stream Employee Events - unknown last event id",
+			lastEventID: "10",
+			httpStatus:  http.StatusOK,
+			reset:       true,
+		},
+		{
+			name:        "Failed - Stream Employee Events - invalid last event id",
+			lastEventID: "abc",
+			httpStatus:  http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, _ := mockRepo("mult")
+
+			svc := services.NewService(logger, false)
+			svc.EmpRepo = repo
+			svc.Events.Start(repo)
+
+			h := NewHandler(logger, svc)
+
+			_, r := gin.CreateTestContext(httptest.NewRecorder())
+
+			// Set up routes
+			h.SetupRoutes(r)
+
+			srv := httptest.NewServer(r)
+			defer srv.Close()
+
+			ctx := context.Background()
+
+			// Two events happen before connecting
+			emp, err := repo.GetEmployee(ctx, employeeId1, repos.QueryOptions{})
+			require.NoError(t, err)
+			emp.IsActive = true
+			_, err = repo.UpdateEmployee(ctx, emp)
+			require.NoError(t, err)
+			require.NoError(t, repo.DeleteEmployee(ctx, employeeId2))
+
+			reqCtx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			req, err := http.NewRequestWithContext(reqCtx, "GET", srv.URL+"/employees/events", nil)
+			require.NoError(t, err, "failed to create request")
+
+			if tc.lastEventID != "" {
+				req.Header.Set(HeaderLastEventID, tc.lastEventID)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			require.Equal(t, tc.httpStatus, resp.StatusCode)
+
+			if resp.StatusCode != http.StatusOK {
+				return
+			}
+
+			require.Equal(t, ContentTypeEventStream, resp.Header.Get("Content-Type"))
+
+			stream := bufio.NewReader(resp.Body)
+
+			if tc.reset {
+				require.Equal(t, EventStreamReset, readStreamEvent(t, stream)["event"])
+			}
+
+			for _, id := range tc.events {
+				require.Equal(t, id, readStreamEvent(t, stream)["id"])
+			}
+
+			// Events are streamed as they happen
+			_, err = repo.CreateEmployee(ctx, &repos.Employee{FirstName: "Emily", LastName: "Williams", Email: "emily@example.com"})
+			require.NoError(t, err)
+
+			event := readStreamEvent(t, stream)
+			require.Equal(t, "3", event["id"])
+			require.Equal(t, services.EventEmployeeCreated, event["event"])
+
+			data := EmployeeEvent{}
+			require.NoError(t, json.Unmarshal([]byte(event["data"]), &data))
+			require.Equal(t, services.EventEmployeeCreated, data.Type)
+			require.Equal(t, "Emily", data.Employee.FirstName)
+		})
+	}
+}
@@ -1,7 +1,11 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"employeeapi/internal/repos"
@@ -15,34 +19,52 @@ import (
 const (
 	packageName = "handlers"
 
-	ErrorIDRequired  = "id is required"
-	ErrorEmpNotFound = "employee not found"
-
-	RoleUnassigned       = "Unassigned"
-	DepartmentUnassigned = "Unassigned"
+	ErrorIDRequired        = "id is required"
+	ErrorEmpNotFound       = "employee not found"
+	ErrorEmpNotDeleted     = "employee is not deleted"
+	ErrorInvalidQueryParam = "invalid query parameter"
+	ErrorEmailInUse        = "email is already in use"
+	ErrorManagerNotFound   = "manager not found"
+	ErrorManagerCycle      = "manager assignment would create a cycle"
+	ErrorUnknownMethod     = "unknown method"
+	ErrorRouteNotFound     = "route not found"
+
+	// HeaderActor identifies who is making the change; recorded in the audit trail
+	HeaderActor    = "X-Actor"
+	ActorAnonymous = "anonymous"
+
+	RoleUnassigned       = repos.RoleUnassigned
+	DepartmentUnassigned = repos.DepartmentUnassigned
 )
 
 type Handler struct {
 	logger        zerolog.Logger
 	svc           services.Service
 	jsonValidator *validator.Validate
+	// openapi is generated once the routes are set up; see SetupRoutes
+	openapi *openAPI
+	// health is shared by the copies of the handler; see SetReady
+	health *healthState
+	limits Limits
+	// idempotencyTTL is how long responses are kept for their Idempotency-Key; see Idempotency
+	idempotencyTTL time.Duration
+	// adminToken guards the admin endpoints; see AdminOnly
+	adminToken string
 }
 
 type Employee struct {
-	ID          string `json:"id"`
-	FirstName   string `json:"first_name" validate:"required,max=100"`
-	LastName    string `json:"last_name" validate:"required,max=100"`
-	DateOfBirth string `json:"dob" validate:"required,dob"`
-	Email       string `json:"email" validate:"required,email"`
-	IsActive    bool   `json:"is_active" validate:"omitempty"`
-	Department  string `json:"department" validate:"omitempty,oneof=Engineering Marketing Finance 'Human Resources' Unassigned"`
-	Role        string `json:"role" validate:"omitempty,oneof='Software Developer' 'Marketing Specialist' 'Financial Analyst' 'HR Specialist' Unassigned"`
-}
-
-type ValidationError struct {
-	Code   int                  `json:"code"`
-	Title  string               `json:"title"`
-	Errors []ValidationErrorDtl `json:"errors,omitempty"`
+	ID          string  `json:"id"`
+	FirstName   string  `json:"first_name" validate:"required,max=100,name"`
+	LastName    string  `json:"last_name" validate:"required,max=100,name"`
+	DateOfBirth string  `json:"dob" validate:"required,dob,age"`
+	Email       string  `json:"email" validate:"required,email,company_email"`
+	IsActive    bool    `json:"is_active" validate:"omitempty"`
+	Department  string  `json:"department" validate:"omitempty,department"`
+	Role        string  `json:"role" validate:"omitempty,role"`
+	ManagerID   *string `json:"manager_id"`
+	// HireDate is kept on update if empty; work anniversaries count from it
+	HireDate  string     `json:"hire_date" validate:"omitempty,hire_date"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 type ValidationErrorDtl struct {
@@ -51,136 +73,206 @@ type ValidationErrorDtl struct {
 }
 
 func NewHandler(logger zerolog.Logger, svc services.Service) Handler {
-	// Set up custom validator and register custom validation
-	validator := validator.New()
-	validator.RegisterValidation("dob", dateOfBirthFormat)
-
 	return Handler{
-		logger:        logger,
-		svc:           svc,
-		jsonValidator: validator,
+		logger:         logger,
+		svc:            svc,
+		jsonValidator:  NewValidator(svc),
+		openapi:        &openAPI{},
+		health:         newHealthState(),
+		limits:         DefaultLimits,
+		idempotencyTTL: DefaultIdempotencyTTL,
 	}
 }
 
+// NewValidator returns the validator of the request bodies with the custom validations registered
+// It's shared with the other APIs so they apply the same rules
+func NewValidator(svc services.Service) *validator.Validate {
+	validator := validator.New()
+	validator.RegisterValidation("dob", pastDate)
+	validator.RegisterValidation("hire_date", pastDate)
+
+	// Departments and roles are checked against the catalogue at request time; it's only a fast
+	// pre-check, the repository checks them again under its write lock
+	validator.RegisterValidation("department", catalogueEntryExists(svc.EmpRepo.GetDepartment))
+	validator.RegisterValidation("role", catalogueEntryExists(svc.EmpRepo.GetRole))
+	validator.RegisterValidation("webhook_event", webhookEvent)
+	validator.RegisterValidation("employee_field", employeeField)
+
+	// Ages, email domains and name characters follow the policy of the service
+	registerPolicy(validator, svc.Policy)
+
+	return validator
+}
+
 // SetupRoutes sets up the routes for the handler
 func (h Handler) SetupRoutes(gin *gin.Engine) {
+	// Must come first so every route below is logged and sends its errors as problem responses
+	// Panics are recovered inside the error middleware so they're sent as problem responses too
+	gin.Use(h.RequestID())
+	gin.Use(h.AccessLog())
+	gin.Use(h.ErrorHandler())
+	gin.Use(h.Recovery())
+	gin.Use(h.RateLimit())
+	gin.Use(h.BodyLimit())
+	gin.Use(h.Idempotency())
+	gin.Use(h.RequestValidator())
+	gin.NoRoute(h.RouteNotFound)
+
 	gin.GET("/employees", h.GetEmployees)
+	gin.GET("/employees/duplicates", h.GetDuplicateEmployees)
+	gin.GET("/employees/search", h.SearchEmployees)
+	gin.GET("/employees/events", h.StreamEmployeeEvents)
 	gin.GET("/employees/:id", h.GetEmployee)
 	gin.POST("/employees", h.CreateEmployee)
 	gin.PUT("/employees/:id", h.UpdateEmployee)
 	gin.DELETE("/employees/:id", h.DeleteEmployee)
+	gin.GET("/employees/:id/history", h.GetEmployeeHistory)
+	gin.GET("/employees/:id/timeline", h.GetEmployeeTimeline)
+	gin.GET("/employees/:id/reports", h.GetReports)
+	gin.GET("/employees/:id/chain", h.GetManagementChain)
+	gin.GET("/orgchart", h.GetOrgChart)
+	gin.GET("/reports/headcount", h.GetHeadcountReport)
+
+	// gin has no support for literal colons in paths so "/employees:<method>"
+	// is routed as a wildcard right after "/employees" and dispatched by method name
+	gin.GET("/employees:method", h.employeesMethod(methodHandlers{
+		"export": h.ExportEmployees,
+	}))
+	gin.POST("/employees:method", h.employeesMethod(methodHandlers{
+		"import": h.ImportEmployees,
+		"batch":  h.BatchEmployees,
+	}))
+	gin.POST("/employees/:id/restore", h.RestoreEmployee)
+	gin.POST("/admin/employees/purge", h.AdminOnly(), h.PurgeEmployees)
+
+	h.setupCatalogueRoutes(gin, "/departments", catalogueOps{
+		kind:   "department",
+		list:   h.svc.EmpRepo.GetDepartments,
+		get:    h.svc.EmpRepo.GetDepartment,
+		create: h.svc.EmpRepo.CreateDepartment,
+		update: h.svc.EmpRepo.UpdateDepartment,
+		delete: h.svc.EmpRepo.DeleteDepartment,
+	})
+	h.setupCatalogueRoutes(gin, "/roles", catalogueOps{
+		kind:   "role",
+		list:   h.svc.EmpRepo.GetRoles,
+		get:    h.svc.EmpRepo.GetRole,
+		create: h.svc.EmpRepo.CreateRole,
+		update: h.svc.EmpRepo.UpdateRole,
+		delete: h.svc.EmpRepo.DeleteRole,
+	})
+
+	h.setupWebhookRoutes(gin)
+
+	gin.GET("/healthz", h.Healthz)
+	gin.GET("/readyz", h.Readyz)
+	gin.GET("/metrics", h.Metrics)
+	gin.GET("/openapi.json", h.OpenAPI)
+	gin.GET("/docs", h.SwaggerUI)
+
+	// Must come last so every route above is documented
+	api, err := newOpenAPI(gin.Routes())
+	if err != nil {
+		h.logger.Error().Err(err).Str("package", packageName).Str("func", "SetupRoutes").Msg("failed to generate openapi document")
+		return
+	}
+	*h.openapi = *api
 }
 
 // GetEmployee gets an employee by id
 func (h Handler) GetEmployee(c *gin.Context) {
-	l := h.logger.With().Str("package", packageName).Str("func", "GetEmployee").Logger()
+	l := h.requestLogger(c).With().Str("package", packageName).Str("func", "GetEmployee").Logger()
 
 	id := c.Param("id")
 
 	if id == "" {
 		l.Error().Msg("id is empty")
-		h.sendErrorResponse(c, http.StatusBadRequest, ErrorIDRequired)
+		h.abortWithError(c, &RequestError{Detail: ErrorIDRequired})
 		return
 	}
 
-	emp, err := h.svc.EmpRepo.GetEmployee(id)
+	opts, err := h.queryOptions(c)
 	if err != nil {
-		l.Error().Err(err).Msg("failed to get employee")
-
-		if err.Error() == repos.RecordNotFound {
-			h.sendErrorResponse(c, http.StatusNotFound, ErrorEmpNotFound)
+		l.Error().Err(err).Msg("failed to parse query parameters")
+		h.abortWithError(c, &RequestError{Detail: ErrorInvalidQueryParam, Err: err})
+		return
+	}
 
-		} else {
-			h.sendErrorResponse(c, http.StatusInternalServerError)
-		}
+	fields, err := projectionFields(c)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to parse query parameters")
+		h.abortWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, Employee{
-		ID:          emp.ID,
-		FirstName:   emp.FirstName,
-		LastName:    emp.LastName,
-		DateOfBirth: emp.DateOfBirth,
-		Email:       emp.Email,
-		IsActive:    emp.IsActive,
-		Department:  emp.Department,
-		Role:        emp.Role,
-	})
+	emp, err := h.svc.EmpRepo.GetEmployee(h.repoContext(c), id, opts)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to get employee")
 
+		h.abortWithError(c, err, ErrorEmpNotFound)
+		return
+	}
+
+	h.projectedJSON(c, http.StatusOK, newEmployee(emp), fields)
 }
 
 // GetEmployees returns all employees
 func (h Handler) GetEmployees(c *gin.Context) {
-	l := h.logger.With().Str("package", packageName).Str("func", "GetEmployees").Logger()
+	l := h.requestLogger(c).With().Str("package", packageName).Str("func", "GetEmployees").Logger()
 
-	employees, err := h.svc.EmpRepo.GetEmployees()
+	opts, err := h.queryOptions(c)
 	if err != nil {
-		l.Error().Err(err).Msg("failed to get employees")
-		h.sendErrorResponse(c, http.StatusInternalServerError)
+		l.Error().Err(err).Msg("failed to parse query parameters")
+		h.abortWithError(c, &RequestError{Detail: ErrorInvalidQueryParam, Err: err})
 		return
 	}
 
-	resp := make([]Employee, 0)
-	for _, emp := range employees {
-		resp = append(resp, Employee{
-			ID:          emp.ID,
-			FirstName:   emp.FirstName,
-			LastName:    emp.LastName,
-			DateOfBirth: emp.DateOfBirth,
-			Email:       emp.Email,
-			IsActive:    emp.IsActive,
-			Department:  emp.Department,
-			Role:        emp.Role,
-		})
+	fields, err := projectionFields(c)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to parse query parameters")
+		h.abortWithError(c, err)
+		return
+	}
+
+	employees, err := h.svc.EmpRepo.GetEmployees(h.repoContext(c), opts)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to get employees")
+		h.abortWithError(c, err)
+		return
 	}
 
-	c.JSON(http.StatusOK, resp)
+	h.projectedJSON(c, http.StatusOK, newEmployees(employees), fields)
 }
 
 // CreateEmployee creates an employee
 func (h Handler) CreateEmployee(c *gin.Context) {
-	l := h.logger.With().Str("package", packageName).Str("func", "CreateEmployee").Logger()
+	l := h.requestLogger(c).With().Str("package", packageName).Str("func", "CreateEmployee").Logger()
+
+	fields, err := projectionFields(c)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to parse query parameters")
+		h.abortWithError(c, err)
+		return
+	}
 
 	var emp Employee
 	if err := c.ShouldBindJSON(&emp); err != nil {
 		l.Error().Err(err).Msg("failed to bind json")
-		h.sendErrorResponse(c, http.StatusInternalServerError)
+		h.abortWithError(c, &RequestError{Detail: ErrorInvalidBody, Err: err})
 		return
 	}
 
-	err := h.jsonValidator.Struct(emp)
-	if err != nil {
+	if err := h.jsonValidator.Struct(emp); err != nil {
 		l.Error().Err(err).Msg("failed to validate json")
 
-		if validationErrors, ok := err.(validator.ValidationErrors); ok {
-			h.handleValidationErrors(c, validationErrors)
-		} else {
-			h.sendErrorResponse(c, http.StatusInternalServerError)
-		}
+		h.abortWithError(c, err)
 		return
 	}
 
-	empToCreate := &repos.Employee{
-		FirstName:   emp.FirstName,
-		LastName:    emp.LastName,
-		DateOfBirth: emp.DateOfBirth,
-		Email:       emp.Email,
-		IsActive:    emp.IsActive,
-	}
-
-	// Assign default values if not provided
-	if emp.Department == "" {
-		empToCreate.Department = DepartmentUnassigned
-	}
-
-	if emp.Role == "" {
-		empToCreate.Role = RoleUnassigned
-	}
-
-	empRec, err := h.svc.EmpRepo.CreateEmployee(empToCreate)
+	empRec, err := h.svc.EmpRepo.CreateEmployee(h.repoContext(c), NewRepoEmployee(emp))
 	if err != nil {
 		l.Error().Err(err).Msg("failed to create employee")
-		h.sendErrorResponse(c, http.StatusInternalServerError)
+		h.abortWithError(c, err, writeErrorDetail(err))
 		return
 	}
 
@@ -189,70 +281,55 @@ func (h Handler) CreateEmployee(c *gin.Context) {
 	emp.Role = empRec.Role
 	emp.Department = empRec.Department
 
-	c.JSON(http.StatusCreated, emp)
+	h.projectedJSON(c, http.StatusCreated, emp, fields)
 }
 
 // UpdateEmployee updates an employee
 func (h Handler) UpdateEmployee(c *gin.Context) {
-	l := h.logger.With().Str("package", packageName).Str("func", "UpdateEmployee").Logger()
+	l := h.requestLogger(c).With().Str("package", packageName).Str("func", "UpdateEmployee").Logger()
 
 	id := c.Param("id")
 	if id == "" {
 		l.Error().Msg("id is empty")
-		h.sendErrorResponse(c, http.StatusBadRequest, ErrorIDRequired)
+		h.abortWithError(c, &RequestError{Detail: ErrorIDRequired})
 		return
 	}
 
-	existingEmpRec, err := h.svc.EmpRepo.GetEmployee(id)
+	fields, err := projectionFields(c)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to parse query parameters")
+		h.abortWithError(c, err)
+		return
+	}
+
+	existingEmpRec, err := h.svc.EmpRepo.GetEmployee(h.repoContext(c), id, repos.QueryOptions{})
 	if err != nil {
 		l.Error().Err(err).Str("id", id).Msg("failed to get employee")
 
-		if err.Error() == repos.RecordNotFound {
-			h.sendErrorResponse(c, http.StatusNotFound, ErrorEmpNotFound)
-		} else {
-			h.sendErrorResponse(c, http.StatusInternalServerError)
-		}
+		h.abortWithError(c, err, ErrorEmpNotFound)
 		return
 	}
 
 	var emp Employee
 	if err := c.ShouldBindJSON(&emp); err != nil {
 		l.Error().Err(err).Msg("failed to bind json")
-		h.sendErrorResponse(c, http.StatusInternalServerError)
+		h.abortWithError(c, &RequestError{Detail: ErrorInvalidBody, Err: err})
 		return
 	}
 
 	if err := h.jsonValidator.Struct(emp); err != nil {
 		l.Error().Err(err).Msg("failed to validate json")
 
-		if validationErrors, ok := err.(validator.ValidationErrors); ok {
-			h.handleValidationErrors(c, validationErrors)
-		} else {
-			h.sendErrorResponse(c, http.StatusInternalServerError)
-
-		}
+		h.abortWithError(c, err)
 		return
 	}
 
-	existingEmpRec.FirstName = emp.FirstName
-	existingEmpRec.LastName = emp.LastName
-	existingEmpRec.DateOfBirth = emp.DateOfBirth
-	existingEmpRec.Email = emp.Email
-	existingEmpRec.IsActive = emp.IsActive
-
-	// We don't want to wipe these fields if they are not passed in the request body
-	if emp.Department != "" {
-		existingEmpRec.Department = emp.Department
-	}
-
-	if emp.Role != "" {
-		existingEmpRec.Role = emp.Role
-	}
+	repos.MergeEmployee(existingEmpRec, NewEmployeeUpdate(emp))
 
-	empRec, err := h.svc.EmpRepo.UpdateEmployee(existingEmpRec)
+	empRec, err := h.svc.EmpRepo.UpdateEmployee(h.repoContext(c), existingEmpRec)
 	if err != nil {
 		l.Error().Err(err).Str("id", id).Msg("failed to update employee")
-		h.sendErrorResponse(c, http.StatusInternalServerError)
+		h.abortWithError(c, err, writeErrorDetail(err))
 		return
 	}
 
@@ -260,78 +337,221 @@ func (h Handler) UpdateEmployee(c *gin.Context) {
 	emp.ID = empRec.ID
 	emp.Role = empRec.Role
 	emp.Department = empRec.Department
+	emp.ManagerID = managerID(empRec.ManagerID)
 
-	c.JSON(http.StatusOK, emp)
+	h.projectedJSON(c, http.StatusOK, emp, fields)
 }
 
 // DeleteEmployee deletes an employee
 func (h Handler) DeleteEmployee(c *gin.Context) {
-	l := h.logger.With().Str("package", packageName).Str("func", "DeleteEmployee").Logger()
+	l := h.requestLogger(c).With().Str("package", packageName).Str("func", "DeleteEmployee").Logger()
 
 	// Check if id is provided
 	id := c.Param("id")
 	if id == "" {
 		l.Error().Msg("id is empty")
-		h.sendErrorResponse(c, http.StatusBadRequest, ErrorIDRequired)
+		h.abortWithError(c, &RequestError{Detail: ErrorIDRequired})
 		return
 	}
 
 	// Check if employee exists
-	_, err := h.svc.EmpRepo.GetEmployee(id)
+	_, err := h.svc.EmpRepo.GetEmployee(h.repoContext(c), id, repos.QueryOptions{})
 	if err != nil {
 		l.Error().Err(err).Str("id", id).Msg("failed to get employee")
 
-		if err.Error() == repos.RecordNotFound {
-			h.sendErrorResponse(c, http.StatusNotFound, ErrorEmpNotFound)
-		} else {
-			h.sendErrorResponse(c, http.StatusInternalServerError)
-		}
+		h.abortWithError(c, err, ErrorEmpNotFound)
 		return
 	}
 
 	// Delete employee
-	if err := h.svc.EmpRepo.DeleteEmployee(id); err != nil {
+	if err := h.svc.EmpRepo.DeleteEmployee(h.repoContext(c), id); err != nil {
 		l.Error().Err(err).Msg("failed to delete employee")
-		h.sendErrorResponse(c, http.StatusInternalServerError)
+		h.abortWithError(c, err)
 		return
 	}
 
 	c.Status(http.StatusOK)
 }
 
-func (h Handler) handleValidationErrors(c *gin.Context, err validator.ValidationErrors) {
-	errDtls := make([]ValidationErrorDtl, 0)
-	for _, e := range err {
-		errDtls = append(errDtls, ValidationErrorDtl{
-			Field:   e.Field(),
-			Message: e.Error(),
-		})
+// Handlers of "/employees:<method>" keyed by method name
+type methodHandlers map[string]gin.HandlerFunc
+
+// Dispatches "/employees:<method>" requests to the handler of the method
+func (h Handler) employeesMethod(methods methodHandlers) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// The wildcard value includes the leading colon
+		method := strings.TrimPrefix(c.Param("method"), ":")
+
+		handler, ok := methods[method]
+		if !ok || !strings.HasPrefix(c.Param("method"), ":") {
+			h.abortWithError(c, &RequestError{Status: http.StatusNotFound, Detail: ErrorUnknownMethod})
+			return
+		}
+
+		handler(c)
 	}
+}
 
-	errResponse := &ValidationError{
-		Code:   http.StatusBadRequest,
-		Title:  http.StatusText(http.StatusBadRequest),
-		Errors: errDtls,
+// NewEmployeeUpdate maps a request body to the update of an existing employee
+// The department, role and hire date are kept if empty and the manager if not passed
+func NewEmployeeUpdate(emp Employee) repos.EmployeeUpdate {
+	return repos.EmployeeUpdate{
+		FirstName:   emp.FirstName,
+		LastName:    emp.LastName,
+		DateOfBirth: emp.DateOfBirth,
+		Email:       emp.Email,
+		IsActive:    emp.IsActive,
+		Department:  emp.Department,
+		Role:        emp.Role,
+		HireDate:    emp.HireDate,
+		ManagerID:   emp.ManagerID,
 	}
+}
 
-	c.JSON(http.StatusBadRequest, errResponse)
+// Maps a repository employee record to the response body
+func newEmployee(emp *repos.Employee) Employee {
+	return Employee{
+		ID:          emp.ID,
+		FirstName:   emp.FirstName,
+		LastName:    emp.LastName,
+		DateOfBirth: emp.DateOfBirth,
+		Email:       emp.Email,
+		IsActive:    emp.IsActive,
+		Department:  emp.Department,
+		Role:        emp.Role,
+		ManagerID:   managerID(emp.ManagerID),
+		HireDate:    emp.HireDate,
+		DeletedAt:   emp.DeletedAt,
+	}
 }
 
-// Send error response based on http status code and title if any
-func (h Handler) sendErrorResponse(c *gin.Context, code int, title ...string) {
+// Maps repository employee records to the response body
+func newEmployees(emps []*repos.Employee) []Employee {
+	resp := make([]Employee, 0)
+	for _, emp := range emps {
+		resp = append(resp, newEmployee(emp))
+	}
 
-	errorTitle := http.StatusText(code)
-	if len(title) > 0 {
-		errorTitle = title[0]
+	return resp
+}
+
+// Returns the manager id for the response body; null if the employee has no manager
+func managerID(id string) *string {
+	if id == "" {
+		return nil
 	}
-	c.JSON(code, &ValidationError{
-		Code:  code,
-		Title: errorTitle,
-	})
+	return &id
+}
+
+// NewRepoEmployee maps a request body to the repository employee record to create
+func NewRepoEmployee(emp Employee) *repos.Employee {
+	empToCreate := &repos.Employee{
+		FirstName:   emp.FirstName,
+		LastName:    emp.LastName,
+		DateOfBirth: emp.DateOfBirth,
+		Email:       emp.Email,
+		IsActive:    emp.IsActive,
+		Department:  emp.Department,
+		Role:        emp.Role,
+		HireDate:    emp.HireDate,
+	}
+
+	if emp.ManagerID != nil {
+		empToCreate.ManagerID = *emp.ManagerID
+	}
+
+	// Assign default values if not provided
+	if emp.Department == "" {
+		empToCreate.Department = DepartmentUnassigned
+	}
+
+	if emp.Role == "" {
+		empToCreate.Role = RoleUnassigned
+	}
+
+	return empToCreate
+}
+
+// Parses the query parameters shared by the read endpoints
+func (h Handler) queryOptions(c *gin.Context) (repos.QueryOptions, error) {
+	var opts repos.QueryOptions
+
+	if includeDeleted := c.Query("include_deleted"); includeDeleted != "" {
+		value, err := strconv.ParseBool(includeDeleted)
+		if err != nil {
+			return opts, err
+		}
+		opts.IncludeDeleted = value
+	}
+
+	if asOf := c.Query("as_of"); asOf != "" {
+		value, err := ParseAsOf(asOf)
+		if err != nil {
+			return opts, err
+		}
+		opts.AsOf = value
+	}
+
+	return opts, nil
+}
+
+// ParseAsOf parses the time of an as_of query: a RFC 3339 time, or a date meaning the end of that day in UTC
+func ParseAsOf(value string) (time.Time, error) {
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
+
+// Builds the context passed to the repository, carrying the actor of the request and its id for
+// the repository logs
+func (h Handler) repoContext(c *gin.Context) context.Context {
+	actor := c.GetHeader(HeaderActor)
+	if actor == "" {
+		actor = ActorAnonymous
+	}
+
+	ctx := repos.ContextWithActor(c.Request.Context(), actor)
+	if id := requestID(c); id != "" {
+		ctx = repos.ContextWithRequestID(ctx, id)
+	}
+
+	return ctx
+}
+
+// Returns the detail of the error response of a failed employee create or update
+func writeErrorDetail(err error) string {
+	switch {
+	case errors.Is(err, repos.ErrNotFound):
+		return ErrorEmpNotFound
+	case errors.Is(err, repos.ErrManagerNotFound):
+		return ErrorManagerNotFound
+	case errors.Is(err, repos.ErrManagerCycle):
+		return ErrorManagerCycle
+	case errors.Is(err, repos.ErrConflict):
+		return ErrorEmailInUse
+	default:
+		return ""
+	}
+}
+
+// ValidationErrorDetails converts validation errors to the details returned in the response
+func ValidationErrorDetails(err validator.ValidationErrors) []ValidationErrorDtl {
+	errDtls := make([]ValidationErrorDtl, 0)
+	for _, e := range err {
+		errDtls = append(errDtls, ValidationErrorDtl{
+			Field:   e.Field(),
+			Message: validationMessage(e),
+		})
+	}
+
+	return errDtls
 }
 
-// Custom validation for date of birth
-func dateOfBirthFormat(fl validator.FieldLevel) bool {
+// Custom validation for dates formatted as YYYY-MM-DD that can't be in the future,
+// i.e. dates of birth and hire dates
+func pastDate(fl validator.FieldLevel) bool {
 	dateStr, ok := fl.Field().Interface().(string)
 	if !ok {
 		return false
@@ -343,5 +563,5 @@ func dateOfBirthFormat(fl validator.FieldLevel) bool {
 	}
 	str := date.Format(layout)
 	parsed, _ := time.Parse(layout, str)
-	return parsed == date
+	return parsed == date && !date.After(time.Now().UTC())
 }
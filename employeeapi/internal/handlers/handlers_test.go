@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"employeeapi/internal/repos"
 	"employeeapi/internal/services"
@@ -245,8 +246,9 @@ func TestDeleteEmployee(t *testing.T) {
 			require.Equal(t, tc.httpStatus, status)
 
 			if status == http.StatusOK {
-				_, ok := (*data)[employeeId1]
-				require.False(t, ok, "employee not deleted")
+				e, ok := (*data)[employeeId1]
+				require.True(t, ok, "employee not found in data")
+				require.NotNil(t, e.DeletedAt, "employee not deleted")
 			}
 		})
 	}
@@ -263,7 +265,7 @@ func TestCreateEmployee(t *testing.T) {
 		employee   Employee
 		httpStatus int
 		errorTitle string
-		error      ValidationError
+		error      Problem
 	}{
 		{
 			name: "Successful - Create Employee",
@@ -288,13 +290,45 @@ func TestCreateEmployee(t *testing.T) {
 			},
 			httpStatus: http.StatusCreated,
 		},
+		{
+			name: "Successful - Create Employee - hire date",
+			employee: Employee{
+				FirstName:   employee.FirstName,
+				LastName:    employee.LastName,
+				DateOfBirth: employee.DateOfBirth,
+				Email:       employee.Email,
+				HireDate:    "2020-01-06",
+			},
+			httpStatus: http.StatusCreated,
+		},
+		{
+			name: "Failed - Create Employee - hire date in the future",
+			employee: Employee{
+				FirstName:   employee.FirstName,
+				LastName:    employee.LastName,
+				DateOfBirth: employee.DateOfBirth,
+				Email:       employee.Email,
+				HireDate:    time.Now().AddDate(1, 0, 0).Format(time.DateOnly),
+			},
+			httpStatus: http.StatusBadRequest,
+			error: Problem{
+				Status: http.StatusBadRequest,
+				Title:  http.StatusText(http.StatusBadRequest),
+				Errors: []ValidationErrorDtl{
+					{
+						Field:   "HireDate",
+						Message: "must be a date formatted as YYYY-MM-DD and not in the future",
+					},
+				},
+			},
+		},
 		{
 			name:       "Failed - Create Employee - empty body",
 			httpStatus: http.StatusBadRequest,
 			employee:   Employee{},
-			error: ValidationError{
-				Code:  http.StatusBadRequest,
-				Title: http.StatusText(http.StatusBadRequest),
+			error: Problem{
+				Status: http.StatusBadRequest,
+				Title:  http.StatusText(http.StatusBadRequest),
 				Errors: []ValidationErrorDtl{
 					{
 						Field:   "FirstName",
@@ -378,13 +412,13 @@ func TestCreateEmployee(t *testing.T) {
 
 			if status == http.StatusBadRequest {
 
-				errResp := ValidationError{}
+				errResp := Problem{}
 				err = json.Unmarshal([]byte(rrBody), &errResp)
 				require.NoError(t, err, "failed to unmarshal response body")
 
 				require.NotNil(t, errResp, "error response is nil")
 
-				require.Equal(t, tc.error.Code, errResp.Code)
+				require.Equal(t, tc.error.Status, errResp.Status)
 				require.Equal(t, tc.error.Title, errResp.Title)
 
 				for i, errDtl := range errResp.Errors {
@@ -409,7 +443,7 @@ func TestUpdateEmployee(t *testing.T) {
 		opts       string
 		httpStatus int
 		errorTitle string
-		error      ValidationError
+		error      Problem
 	}{
 		{
 			name: "Successful - Update Employee",
@@ -522,13 +556,13 @@ func TestUpdateEmployee(t *testing.T) {
 
 			if status == http.StatusBadRequest {
 
-				errResp := ValidationError{}
+				errResp := Problem{}
 				err = json.Unmarshal([]byte(rrBody), &errResp)
 				require.NoError(t, err, "failed to unmarshal response body")
 
 				require.NotNil(t, errResp, "error response is nil")
 
-				require.Equal(t, tc.error.Code, errResp.Code)
+				require.Equal(t, tc.error.Status, errResp.Status)
 				require.Equal(t, tc.error.Title, errResp.Title)
 
 				for i, errDtl := range errResp.Errors {
@@ -543,7 +577,6 @@ func TestUpdateEmployee(t *testing.T) {
 func mockRepo(opts ...string) (repos.EmployeeRepo, *map[string]*repos.Employee) {
 
 	data := make(map[string]*repos.Employee, 0)
-	repo := repos.NewEmployeeRepo(logger, &data)
 
 	emp1 := &repos.Employee{
 		ID:          employeeId1,
@@ -577,5 +610,8 @@ func mockRepo(opts ...string) (repos.EmployeeRepo, *map[string]*repos.Employee)
 		}
 	}
 
+	// Create the repo once the data is set so its indexes are built from it
+	repo := repos.NewEmployeeRepo(logger, &data)
+
 	return repo, &data
 }
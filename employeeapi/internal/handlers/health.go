@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	HealthStatusOK          = "ok"
+	HealthStatusUnavailable = "unavailable"
+	HealthStatusDraining    = "draining"
+
+	// HealthCheckTimeout bounds how long the checks of a health request may take
+	HealthCheckTimeout = 2 * time.Second
+
+	HealthCheckRepository = "repository"
+	HealthCheckTraffic    = "traffic"
+)
+
+// BuildInfo identifies the running build; the version, commit and build time are set at link time
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildTime string `json:"build_time,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+type HealthStatus struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+	Build  BuildInfo         `json:"build"`
+}
+
+// healthState is shared by the copies of a handler so readiness can be changed once it's serving
+type healthState struct {
+	ready atomic.Bool
+	mu    sync.RWMutex
+	build BuildInfo
+}
+
+func newHealthState() *healthState {
+	health := &healthState{
+		build: NewBuildInfo("dev", "", ""),
+	}
+	health.ready.Store(true)
+
+	return health
+}
+
+// NewBuildInfo returns the info of the running build
+// The commit is taken from the VCS info embedded by the go tool if not given
+func NewBuildInfo(version, commit, buildTime string) BuildInfo {
+	info := BuildInfo{
+		Version:   version,
+		Commit:    commit,
+		BuildTime: buildTime,
+		GoVersion: runtime.Version(),
+	}
+
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range buildInfo.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.BuildTime == "":
+				info.BuildTime = setting.Value
+			}
+		}
+	}
+
+	return info
+}
+
+// SetBuildInfo sets the build info reported by the health endpoints
+func (h Handler) SetBuildInfo(info BuildInfo) {
+	h.health.mu.Lock()
+	defer h.health.mu.Unlock()
+
+	h.health.build = info
+}
+
+// SetReady sets whether the server accepts traffic; it's set to false on shutdown so
+// load balancers stop sending requests before the server stops
+func (h Handler) SetReady(ready bool) {
+	h.health.ready.Store(ready)
+}
+
+// Healthz reports whether the server is alive, i.e. the repository can be used
+func (h Handler) Healthz(c *gin.Context) {
+	h.sendHealth(c, false)
+}
+
+// Readyz reports whether the server accepts traffic: it's alive and not shutting down
+func (h Handler) Readyz(c *gin.Context) {
+	h.sendHealth(c, true)
+}
+
+// Runs the health checks and sends their status; 503 if any of them fails
+func (h Handler) sendHealth(c *gin.Context, readiness bool) {
+	l := h.requestLogger(c).With().Str("package", packageName).Str("func", "sendHealth").Logger()
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), HealthCheckTimeout)
+	defer cancel()
+
+	status := HealthStatus{
+		Status: HealthStatusOK,
+		Checks: map[string]string{HealthCheckRepository: HealthStatusOK},
+	}
+
+	if err := h.svc.EmpRepo.Health(ctx); err != nil {
+		l.Error().Err(err).Msg("repository is unavailable")
+
+		status.Status = HealthStatusUnavailable
+		status.Checks[HealthCheckRepository] = err.Error()
+	}
+
+	if readiness {
+		status.Checks[HealthCheckTraffic] = HealthStatusOK
+		if !h.health.ready.Load() {
+			status.Status = HealthStatusUnavailable
+			status.Checks[HealthCheckTraffic] = HealthStatusDraining
+		}
+	}
+
+	h.health.mu.RLock()
+	status.Build = h.health.build
+	h.health.mu.RUnlock()
+
+	code := http.StatusOK
+	if status.Status != HealthStatusOK {
+		code = http.StatusServiceUnavailable
+	}
+
+	c.JSON(code, status)
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"employeeapi/internal/repos"
+	"employeeapi/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// unavailableRepo is a repository failing its health check
+type unavailableRepo struct {
+	repos.EmployeeRepo
+}
+
+func (r unavailableRepo) Health(ctx context.Context) error {
+	return repos.ErrUnavailable
+}
+
+// Test Healthz and Readyz handlers
+func TestHealth(t *testing.T) {
+	testCases := []struct {
+		name        string
+		path        string
+		unavailable bool
+		draining    bool
+		httpStatus  int
+		checks      map[string]string
+	}{
+		{
+			name:       "Successful - Healthz",
+			path:       "/healthz",
+			httpStatus: http.StatusOK,
+			checks:     map[string]string{HealthCheckRepository: HealthStatusOK},
+		},
+		{
+			name:        "Failed - Healthz - repository unavailable",
+			path:        "/healthz",
+			unavailable: true,
+			httpStatus:  http.StatusServiceUnavailable,
+			checks:      map[string]string{HealthCheckRepository: repos.ErrUnavailable.Error()},
+		},
+		{
+			name:       "Successful - Healthz - alive while draining",
+			path:       "/healthz",
+			draining:   true,
+			httpStatus: http.StatusOK,
+			checks:     map[string]string{HealthCheckRepository: HealthStatusOK},
+		},
+		{
+			name:       "Successful - Readyz",
+			path:       "/readyz",
+			httpStatus: http.StatusOK,
+			checks:     map[string]string{HealthCheckRepository: HealthStatusOK, HealthCheckTraffic: HealthStatusOK},
+		},
+		{
+			name:        "Failed - Readyz - repository unavailable",
+			path:        "/readyz",
+			unavailable: true,
+			httpStatus:  http.StatusServiceUnavailable,
+			checks:      map[string]string{HealthCheckRepository: repos.ErrUnavailable.Error(), HealthCheckTraffic: HealthStatusOK},
+		},
+		{
+			name:       "Failed - Readyz - draining",
+			path:       "/readyz",
+			draining:   true,
+			httpStatus: http.StatusServiceUnavailable,
+			checks:     map[string]string{HealthCheckRepository: HealthStatusOK, HealthCheckTraffic: HealthStatusDraining},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, _ := mockRepo("single")
+
+			svc := services.NewService(logger, false)
+			svc.EmpRepo = repo
+			if tc.unavailable {
+				svc.EmpRepo = unavailableRepo{repo}
+			}
+
+			h := NewHandler(logger, svc)
+			h.SetBuildInfo(BuildInfo{Version: "1.2.0", Commit: "abc123", GoVersion: "go1.21.5"})
+
+			if tc.draining {
+				h.SetReady(false)
+			}
+
+			req, err := http.NewRequest("GET", tc.path, nil)
+			require.NoError(t, err, "failed to create request")
+
+			rr := httptest.NewRecorder()
+
+			_, r := gin.CreateTestContext(rr)
+
+			// Set up routes
+			h.SetupRoutes(r)
+
+			// Call the handler
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.httpStatus, rr.Code)
+
+			status := HealthStatus{}
+			err = json.Unmarshal(rr.Body.Bytes(), &status)
+			require.NoError(t, err, "failed to unmarshal response body")
+
+			expectedStatus := HealthStatusOK
+			if tc.httpStatus != http.StatusOK {
+				expectedStatus = HealthStatusUnavailable
+			}
+
+			require.Equal(t, expectedStatus, status.Status)
+			require.Equal(t, tc.checks, status.Checks)
+			require.Equal(t, BuildInfo{Version: "1.2.0", Commit: "abc123", GoVersion: "go1.21.5"}, status.Build)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditEntry struct {
+	Action    string        `json:"action"`
+	Actor     string        `json:"actor"`
+	Timestamp time.Time     `json:"timestamp"`
+	Changes   []FieldChange `json:"changes"`
+}
+
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// GetEmployeeHistory returns the chronological list of changes made to an employee
+func (h Handler) GetEmployeeHistory(c *gin.Context) {
+	l := h.requestLogger(c).With().Str("package", packageName).Str("func", "GetEmployeeHistory").Logger()
+
+	id := c.Param("id")
+	if id == "" {
+		l.Error().Msg("id is empty")
+		h.abortWithError(c, &RequestError{Detail: ErrorIDRequired})
+		return
+	}
+
+	fields, err := projectionFields(c)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to parse query parameters")
+		h.abortWithError(c, err)
+		return
+	}
+
+	entries, err := h.svc.EmpRepo.GetEmployeeHistory(h.repoContext(c), id)
+	if err != nil {
+		l.Error().Err(err).Str("id", id).Msg("failed to get employee history")
+
+		h.abortWithError(c, err, ErrorEmpNotFound)
+		return
+	}
+
+	resp := make([]AuditEntry, 0)
+	for _, entry := range entries {
+		changes := make([]FieldChange, 0)
+		for _, change := range entry.Changes {
+			// The changes of the fields that aren't selected are left out
+			if !selected(fields, change.Field) {
+				continue
+			}
+
+			changes = append(changes, FieldChange{
+				Field:  change.Field,
+				Before: change.Before,
+				After:  change.After,
+			})
+		}
+
+		resp = append(resp, AuditEntry{
+			Action:    entry.Action,
+			Actor:     entry.Actor,
+			Timestamp: entry.Timestamp,
+			Changes:   changes,
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
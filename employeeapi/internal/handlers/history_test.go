@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"employeeapi/internal/repos"
+	"employeeapi/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// Test GetEmployeeHistory handler
+func TestGetEmployeeHistory(t *testing.T) {
+	testCases := []struct {
+		name       string
+		id         string
+		actor      string
+		query      string
+		httpStatus int
+		changes    []FieldChange
+	}{
+		{
+			name:       "Successful - Get Employee History",
+			id:         employeeId1,
+			actor:      "hr.admin",
+			httpStatus: http.StatusOK,
+			changes:    []FieldChange{{Field: "department", Before: "Engineering", After: "Finance"}},
+		},
+		{
+			name:       "Successful - Get Employee History - anonymous actor",
+			id:         employeeId1,
+			httpStatus: http.StatusOK,
+			changes:    []FieldChange{{Field: "department", Before: "Engineering", After: "Finance"}},
+		},
+		{
+			name:       "Successful - Get Employee History - changes of unselected fields left out",
+			id:         employeeId1,
+			query:      "?fields=id,email",
+			httpStatus: http.StatusOK,
+			changes:    []FieldChange{},
+		},
+		{
+			name:       "Failed - Get Employee History - not found",
+			id:         uuid.New().String(),
+			httpStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, data := mockRepo("single")
+
+			svc := services.NewService(logger, false)
+			svc.EmpRepo = repo
+
+			h := NewHandler(logger, svc)
+
+			rr := httptest.NewRecorder()
+
+			_, r := gin.CreateTestContext(rr)
+
+			// Set up routes
+			h.SetupRoutes(r)
+
+			// Update the employee so it has a history
+			emp := (*data)[employeeId1]
+			body, err := json.Marshal(Employee{
+				FirstName:   emp.FirstName,
+				LastName:    emp.LastName,
+				DateOfBirth: emp.DateOfBirth,
+				Email:       emp.Email,
+				Department:  "Finance",
+			})
+			require.NoError(t, err, "failed to marshal JSON")
+
+			req, err := http.NewRequest("PUT", "/employees/"+employeeId1, bytes.NewBuffer(body))
+			require.NoError(t, err, "failed to create request")
+
+			req.Header.Set("Content-Type", "application/json")
+			if tc.actor != "" {
+				req.Header.Set(HeaderActor, tc.actor)
+			}
+
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			// Create a request to pass to our handler
+			req, err = http.NewRequest("GET", "/employees/"+tc.id+"/history"+tc.query, nil)
+			require.NoError(t, err, "failed to create request")
+
+			// Call the handler
+			r.ServeHTTP(rr, req)
+
+			status := rr.Code
+
+			require.Equal(t, tc.httpStatus, status)
+
+			// Check the response body is what we expect
+			if status == http.StatusOK {
+				history := []AuditEntry{}
+
+				err = json.Unmarshal(rr.Body.Bytes(), &history)
+				require.NoError(t, err, "failed to unmarshal response body")
+
+				require.Len(t, history, 1)
+				require.Equal(t, repos.AuditActionUpdate, history[0].Action)
+
+				expectedActor := tc.actor
+				if expectedActor == "" {
+					expectedActor = ActorAnonymous
+				}
+				require.Equal(t, expectedActor, history[0].Actor)
+
+				require.Equal(t, tc.changes, history[0].Changes)
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// HeaderIdempotencyKey makes a POST request safe to retry: the first response is stored
+	// and replayed for the requests sent again with the same key
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed is set on the responses replayed for a repeated key
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	// DefaultIdempotencyTTL is how long responses are kept for their key unless set with WithIdempotencyTTL
+	DefaultIdempotencyTTL = 24 * time.Hour
+
+	maxIdempotencyKeyLength = 255
+
+	ErrorInvalidIdempotencyKey = "idempotency key must be 1 to 255 characters"
+	ErrorIdempotencyKeyReused  = "idempotency key was already used with a different request"
+	ErrorIdempotencyInProgress = "a request with this idempotency key is in progress"
+
+	// Expired responses are looked for at this interval
+	idempotencySweepInterval = time.Minute
+)
+
+var (
+	errIdempotencyKeyReused  = errors.New(ErrorIdempotencyKeyReused)
+	errIdempotencyInProgress = errors.New(ErrorIdempotencyInProgress)
+)
+
+// Headers of a response that are stored and replayed with its body
+var idempotentHeaders = []string{"Content-Type", "Content-Disposition", "Location"}
+
+var idempotencyKeyParam = &openapi3.ParameterRef{
+	Value: openapi3.NewHeaderParameter(HeaderIdempotencyKey).
+		WithDescription("Makes the request safe to retry: the first response is replayed for the requests " +
+			"sent again with the same key, and a request with a different body is 422").
+		WithSchema(openapi3.NewStringSchema().WithMinLength(1).WithMaxLength(maxIdempotencyKeyLength)),
+}
+
+// WithIdempotencyTTL returns a copy of the handler keeping responses for their idempotency key
+// for the given duration; 0 disables idempotency keys
+// Must be called before the routes are set up
+func (h Handler) WithIdempotencyTTL(ttl time.Duration) Handler {
+	h.idempotencyTTL = ttl
+	return h
+}
+
+// Idempotency is the middleware replaying the response of POST requests sent again with
+// the same Idempotency-Key header
+// Keys are scoped to the client, identified as for rate limiting, and the request is
+// fingerprinted by its path and body: the same key with another request is 422.
+// A request sent again while the first one is still being handled is 409.
+// Only the responses written by the handlers are stored, not errors or server failures,
+// so those requests can be retried
+func (h Handler) Idempotency() gin.HandlerFunc {
+	store := newIdempotencyStore(h.idempotencyTTL, time.Now)
+
+	return func(c *gin.Context) {
+		key := c.GetHeader(HeaderIdempotencyKey)
+		if h.idempotencyTTL <= 0 || c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+
+		l := h.requestLogger(c).With().Str("package", packageName).Str("func", "Idempotency").Logger()
+
+		if len(key) > maxIdempotencyKeyLength {
+			l.Error().Int("length", len(key)).Msg("invalid idempotency key")
+			h.abortWithError(c, &RequestError{Detail: ErrorInvalidIdempotencyKey})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			l.Error().Err(err).Msg("failed to read body")
+			h.abortWithError(c, &RequestError{Detail: ErrorInvalidBody, Err: err})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		fingerprint := sha256.New()
+		io.WriteString(fingerprint, c.Request.URL.RequestURI()+"\n")
+		fingerprint.Write(body)
+
+		storeKey := h.clientKey(c) + "\n" + key
+
+		stored, err := store.begin(storeKey, fingerprint.Sum(nil))
+		if err != nil {
+			l.Error().Err(err).Str("idempotencyKey", key).Msg("idempotency key can't be used")
+
+			status := http.StatusUnprocessableEntity
+			if errors.Is(err, errIdempotencyInProgress) {
+				status = http.StatusConflict
+			}
+
+			h.abortWithError(c, &RequestError{Status: status, Detail: err.Error()})
+			return
+		}
+
+		if stored != nil {
+			l.Info().Str("idempotencyKey", key).Int("status", stored.status).Msg("replaying stored response")
+
+			for name, value := range stored.headers {
+				c.Header(name, value)
+			}
+			c.Header(HeaderIdempotentReplayed, "true")
+			c.Status(stored.status)
+			c.Writer.Write(stored.body)
+			c.Abort()
+			return
+		}
+
+		// The key is released if the handler panics too
+		finished := false
+		defer func() {
+			if !finished {
+				store.release(storeKey)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		c.Next()
+
+		c.Writer = recorder.ResponseWriter
+
+		// Errors are written by ErrorHandler once this returns so they aren't stored
+		if !recorder.Written() || recorder.Status() >= http.StatusInternalServerError {
+			return
+		}
+
+		headers := make(map[string]string)
+		for _, name := range idempotentHeaders {
+			if value := recorder.Header().Get(name); value != "" {
+				headers[name] = value
+			}
+		}
+
+		store.finish(storeKey, &storedResponse{
+			status:  recorder.Status(),
+			headers: headers,
+			body:    recorder.body.Bytes(),
+		})
+		finished = true
+	}
+}
+
+// responseRecorder keeps a copy of the body written to the response
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+type storedResponse struct {
+	status  int
+	headers map[string]string
+	body    []byte
+}
+
+// idempotencyEntry is the request of a key and its response once it's handled
+type idempotencyEntry struct {
+	fingerprint []byte
+	// response is nil while the request is being handled
+	response *storedResponse
+	expires  time.Time
+}
+
+// idempotencyStore keeps the responses by key until they expire
+type idempotencyStore struct {
+	ttl       time.Duration
+	now       func() time.Time
+	mu        sync.Mutex
+	entries   map[string]*idempotencyEntry
+	lastSweep time.Time
+}
+
+func newIdempotencyStore(ttl time.Duration, now func() time.Time) *idempotencyStore {
+	return &idempotencyStore{
+		ttl:       ttl,
+		now:       now,
+		entries:   make(map[string]*idempotencyEntry),
+		lastSweep: now(),
+	}
+}
+
+// Starts handling the request of a key
+// Returns the stored response if the key was already used for the same request, nil if it's
+// a new key, which must then be finished or released, and an error if the key was used for
+// another request or its request is still being handled
+func (s *idempotencyStore) begin(key string, fingerprint []byte) (*storedResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	if entry, ok := s.entries[key]; ok && now.Before(entry.expires) {
+		switch {
+		case !bytes.Equal(entry.fingerprint, fingerprint):
+			return nil, errIdempotencyKeyReused
+		case entry.response == nil:
+			return nil, errIdempotencyInProgress
+		default:
+			return entry.response, nil
+		}
+	}
+
+	s.entries[key] = &idempotencyEntry{fingerprint: fingerprint, expires: now.Add(s.ttl)}
+
+	return nil, nil
+}
+
+// Stores the response of a key, kept for the TTL from now
+func (s *idempotencyStore) finish(key string, response *storedResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, ok := s.entries[key]; ok {
+		entry.response = response
+		entry.expires = s.now().Add(s.ttl)
+	}
+}
+
+// Forgets a key whose request didn't get a response worth replaying
+func (s *idempotencyStore) release(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+}
+
+// Forgets the expired keys
+func (s *idempotencyStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < idempotencySweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, entry := range s.entries {
+		if !now.Before(entry.expires) {
+			delete(s.entries, key)
+		}
+	}
+}
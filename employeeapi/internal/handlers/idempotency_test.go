@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"employeeapi/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// Test that the store replays the response of a key until it expires
+func TestIdempotencyStore(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	store := newIdempotencyStore(time.Hour, func() time.Time { return now })
+
+	stored, err := store.begin("a", []byte("request"))
+	require.NoError(t, err)
+	require.Nil(t, stored)
+
+	// The request is still being handled
+	_, err = store.begin("a", []byte("request"))
+	require.ErrorIs(t, err, errIdempotencyInProgress)
+
+	response := &storedResponse{status: http.StatusCreated, body: []byte("{}")}
+	store.finish("a", response)
+
+	stored, err = store.begin("a", []byte("request"))
+	require.NoError(t, err)
+	require.Equal(t, response, stored)
+
+	_, err = store.begin("a", []byte("another request"))
+	require.ErrorIs(t, err, errIdempotencyKeyReused)
+
+	// Released keys can be used again for any request
+	_, err = store.begin("b", []byte("request"))
+	require.NoError(t, err)
+	store.release("b")
+	_, err = store.begin("b", []byte("another request"))
+	require.NoError(t, err)
+
+	// Expired keys are new again and forgotten
+	now = now.Add(time.Hour)
+
+	stored, err = store.begin("a", []byte("another request"))
+	require.NoError(t, err)
+	require.Nil(t, stored)
+	require.Len(t, store.entries, 1)
+}
+
+// Test Idempotency middleware
+func TestIdempotency(t *testing.T) {
+	create := `{"first_name":"Emily","last_name":"Williams","dob":"1995-12-08","email":"emily.williams@example.com"}`
+
+	testCases := []struct {
+		name string
+		// Requests sent in turn with the key, the first one is always the create
+		bodies     []string
+		key        string
+		httpStatus int
+		replayed   bool
+		employees  int
+	}{
+		{
+			name:       "Successful - Idempotency - replayed",
+			bodies:     []string{create, create},
+			key:        "sync-1",
+			httpStatus: http.StatusCreated,
+			replayed:   true,
+			employees:  2,
+		},
+		{
+			name:       "Successful - Idempotency - no key",
+			bodies:     []string{create, strings.Replace(create, "emily.williams", "emily.w", 1)},
+			httpStatus: http.StatusCreated,
+			employees:  3,
+		},
+		{
+			name:       "Failed - Idempotency - key reused with another body",
+			bodies:     []string{create, strings.Replace(create, "Emily", "Emilia", 1)},
+			key:        "sync-1",
+			httpStatus: http.StatusUnprocessableEntity,
+			employees:  2,
+		},
+		{
+			name:       "Successful - Idempotency - errors aren't stored",
+			bodies:     []string{`{"first_name":"Emily"}`, create},
+			key:        "sync-1",
+			httpStatus: http.StatusCreated,
+			employees:  2,
+		},
+		{
+			name:       "Failed - Idempotency - key too long",
+			bodies:     []string{create},
+			key:        strings.Repeat("k", maxIdempotencyKeyLength+1),
+			httpStatus: http.StatusBadRequest,
+			employees:  1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, data := mockRepo("single")
+
+			svc := services.NewService(logger, false)
+			svc.EmpRepo = repo
+
+			h := NewHandler(logger, svc)
+
+			_, r := gin.CreateTestContext(httptest.NewRecorder())
+
+			// Set up routes
+			h.SetupRoutes(r)
+
+			responses := make([]*httptest.ResponseRecorder, 0)
+			for _, body := range tc.bodies {
+				req, err := http.NewRequest("POST", "/employees", strings.NewReader(body))
+				require.NoError(t, err, "failed to create request")
+
+				req.Header.Set("Content-Type", "application/json")
+				if tc.key != "" {
+					req.Header.Set(HeaderIdempotencyKey, tc.key)
+				}
+
+				rr := httptest.NewRecorder()
+				r.ServeHTTP(rr, req)
+
+				responses = append(responses, rr)
+			}
+
+			last := responses[len(responses)-1]
+
+			require.Equal(t, tc.httpStatus, last.Code)
+			require.Len(t, *data, tc.employees)
+
+			if !tc.replayed {
+				require.Empty(t, last.Header().Get(HeaderIdempotentReplayed))
+				return
+			}
+
+			require.Equal(t, "true", last.Header().Get(HeaderIdempotentReplayed))
+			require.Equal(t, responses[0].Header().Get("Content-Type"), last.Header().Get("Content-Type"))
+
+			var first, replayed Employee
+			require.NoError(t, json.Unmarshal(responses[0].Body.Bytes(), &first))
+			require.NoError(t, json.Unmarshal(last.Body.Bytes(), &replayed))
+			require.Equal(t, first.ID, replayed.ID)
+		})
+	}
+}
@@ -0,0 +1,491 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"employeeapi/internal/repos"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	ImportModeAllOrNothing = "all_or_nothing"
+	ImportModeBestEffort   = "best_effort"
+
+	ImportRowCreated = "created"
+	ImportRowFailed  = "failed"
+	ImportRowSkipped = "skipped"
+
+	ExportFormatJSON = "json"
+	ExportFormatCSV  = "csv"
+
+	ErrorNoEmployees            = "no employees to import"
+	ErrorInvalidImportFile      = "invalid import file"
+	ErrorUnsupportedFormat      = "unsupported format"
+	ErrorUnsupportedContentType = "unsupported content type"
+)
+
+// Columns of the CSV export; the import accepts the same columns
+var csvColumns = []string{"id", "first_name", "last_name", "dob", "email", "is_active", "department", "role", "manager_id", "hire_date"}
+
+type ImportResult struct {
+	Mode    string            `json:"mode"`
+	Total   int               `json:"total"`
+	Created int               `json:"created"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
+
+type ImportRowResult struct {
+	Row    int                  `json:"row"`
+	Status string               `json:"status"`
+	ID     string               `json:"id,omitempty"`
+	Errors []ValidationErrorDtl `json:"errors,omitempty"`
+}
+
+// A parsed row of the import with the errors found while parsing it
+type importRow struct {
+	emp    Employee
+	errors []ValidationErrorDtl
+}
+
+// ImportEmployees creates employees from a CSV file or a JSON array
+// In all_or_nothing mode (default) nothing is created if any row fails,
+// in best_effort mode the valid rows are created and the others reported
+func (h Handler) ImportEmployees(c *gin.Context) {
+	l := h.requestLogger(c).With().Str("package", packageName).Str("func", "ImportEmployees").Logger()
+
+	mode := c.DefaultQuery("mode", ImportModeAllOrNothing)
+	if mode != ImportModeAllOrNothing && mode != ImportModeBestEffort {
+		l.Error().Str("mode", mode).Msg("invalid import mode")
+		h.abortWithError(c, &RequestError{Detail: ErrorInvalidQueryParam})
+		return
+	}
+
+	var rows []importRow
+	var err error
+
+	switch c.ContentType() {
+	case "text/csv":
+		rows, err = parseCSVImport(c.Request.Body)
+	case "application/json", "":
+		rows, err = parseJSONImport(c.Request.Body)
+	default:
+		l.Error().Str("contentType", c.ContentType()).Msg("unsupported content type")
+		h.abortWithError(c, &RequestError{Status: http.StatusUnsupportedMediaType, Detail: ErrorUnsupportedContentType})
+		return
+	}
+
+	if err != nil {
+		l.Error().Err(err).Msg("failed to parse import file")
+		h.abortWithError(c, &RequestError{Detail: ErrorInvalidImportFile, Err: err})
+		return
+	}
+
+	if len(rows) == 0 {
+		l.Error().Msg("import file is empty")
+		h.abortWithError(c, &RequestError{Detail: ErrorNoEmployees})
+		return
+	}
+
+	// Validate every row with the same rules as CreateEmployee
+	for i := range rows {
+		if len(rows[i].errors) > 0 {
+			continue
+		}
+
+		if err := h.jsonValidator.Struct(rows[i].emp); err != nil {
+			if validationErrors, ok := err.(validator.ValidationErrors); ok {
+				rows[i].errors = ValidationErrorDetails(validationErrors)
+			} else {
+				rows[i].errors = []ValidationErrorDtl{{Message: err.Error()}}
+			}
+		}
+	}
+
+	result := ImportResult{
+		Mode:  mode,
+		Total: len(rows),
+		Rows:  make([]ImportRowResult, len(rows)),
+	}
+
+	for i, row := range rows {
+		result.Rows[i] = ImportRowResult{Row: i + 1, Errors: row.errors}
+	}
+
+	status := http.StatusOK
+	if mode == ImportModeAllOrNothing {
+		status = h.importAllOrNothing(c, rows, &result)
+	} else {
+		h.importBestEffort(c, rows, &result)
+	}
+
+	for _, row := range result.Rows {
+		switch row.Status {
+		case ImportRowCreated:
+			result.Created++
+		case ImportRowFailed:
+			result.Failed++
+		}
+	}
+
+	l.Info().Str("mode", mode).Int("total", result.Total).Int("created", result.Created).Int("failed", result.Failed).Msg("imported employees")
+
+	c.JSON(status, result)
+}
+
+// Creates the rows in a single repository write if they're all valid
+// Returns the http status of the response
+func (h Handler) importAllOrNothing(c *gin.Context, rows []importRow, result *ImportResult) int {
+	failed := false
+	for i, row := range rows {
+		if len(row.errors) > 0 {
+			result.Rows[i].Status = ImportRowFailed
+			failed = true
+		} else {
+			result.Rows[i].Status = ImportRowSkipped
+		}
+	}
+
+	if failed {
+		return http.StatusUnprocessableEntity
+	}
+
+	emps := make([]*repos.Employee, 0, len(rows))
+	for _, row := range rows {
+		emps = append(emps, NewRepoEmployee(row.emp))
+	}
+
+	created, err := h.svc.EmpRepo.CreateEmployees(h.repoContext(c), emps)
+	if err != nil {
+		var bulkErr *repos.BulkError
+
+		if errors.As(err, &bulkErr) {
+			if rowErr, ok := importRowError(bulkErr.Err); ok {
+				result.Rows[bulkErr.Index].Status = ImportRowFailed
+				result.Rows[bulkErr.Index].Errors = []ValidationErrorDtl{rowErr}
+				return http.StatusUnprocessableEntity
+			}
+		}
+
+		for i := range result.Rows {
+			result.Rows[i].Status = ImportRowFailed
+			result.Rows[i].Errors = []ValidationErrorDtl{{Message: http.StatusText(http.StatusInternalServerError)}}
+		}
+		return http.StatusInternalServerError
+	}
+
+	for i, emp := range created {
+		result.Rows[i].Status = ImportRowCreated
+		result.Rows[i].ID = emp.ID
+	}
+
+	return http.StatusCreated
+}
+
+// Creates the valid rows one by one and reports the ones that fail
+func (h Handler) importBestEffort(c *gin.Context, rows []importRow, result *ImportResult) {
+	for i, row := range rows {
+		if len(row.errors) > 0 {
+			result.Rows[i].Status = ImportRowFailed
+			continue
+		}
+
+		emp, err := h.svc.EmpRepo.CreateEmployee(h.repoContext(c), NewRepoEmployee(row.emp))
+		if err != nil {
+			result.Rows[i].Status = ImportRowFailed
+
+			if rowErr, ok := importRowError(err); ok {
+				result.Rows[i].Errors = []ValidationErrorDtl{rowErr}
+			} else {
+				result.Rows[i].Errors = []ValidationErrorDtl{{Message: http.StatusText(http.StatusInternalServerError)}}
+			}
+			continue
+		}
+
+		result.Rows[i].Status = ImportRowCreated
+		result.Rows[i].ID = emp.ID
+	}
+}
+
+// Converts a repository error caused by the content of a row to the error reported for the row
+func importRowError(err error) (ValidationErrorDtl, bool) {
+	switch {
+	case errors.Is(err, repos.ErrConflict):
+		return ValidationErrorDtl{Field: "Email", Message: ErrorEmailInUse}, true
+	case errors.Is(err, repos.ErrManagerNotFound):
+		return ValidationErrorDtl{Field: "ManagerID", Message: ErrorManagerNotFound}, true
+	case errors.Is(err, repos.ErrDepartmentNotFound):
+		return ValidationErrorDtl{Field: "Department", Message: repos.DepartmentNotFound}, true
+	case errors.Is(err, repos.ErrRoleNotFound):
+		return ValidationErrorDtl{Field: "Role", Message: repos.RoleNotFound}, true
+	}
+
+	return ValidationErrorDtl{}, false
+}
+
+// ExportEmployees streams all employees as JSON (default) or CSV
+func (h Handler) ExportEmployees(c *gin.Context) {
+	l := h.requestLogger(c).With().Str("package", packageName).Str("func", "ExportEmployees").Logger()
+
+	format := c.DefaultQuery("format", ExportFormatJSON)
+	if format != ExportFormatJSON && format != ExportFormatCSV {
+		l.Error().Str("format", format).Msg("unsupported export format")
+		h.abortWithError(c, &RequestError{Detail: ErrorUnsupportedFormat})
+		return
+	}
+
+	fields, err := projectionFields(c)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to parse query parameters")
+		h.abortWithError(c, err)
+		return
+	}
+
+	employees, err := h.svc.EmpRepo.GetEmployees(h.repoContext(c), repos.QueryOptions{})
+	if err != nil {
+		l.Error().Err(err).Msg("failed to get employees")
+		h.abortWithError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="employees.%s"`, format))
+
+	if format == ExportFormatCSV {
+		err = writeCSVExport(c, employees, fields)
+	} else {
+		err = writeJSONExport(c, employees, fields)
+	}
+
+	// The status is already sent at this point so the error can only be logged
+	if err != nil {
+		l.Error().Err(err).Msg("failed to write export")
+	}
+}
+
+// Writes the CSV export with the columns of the given fields only, all of them if fields is nil
+func writeCSVExport(c *gin.Context, employees []*repos.Employee, fields []string) error {
+	c.Header("Content-Type", "text/csv")
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+
+	// Indexes of the columns sent, in the order of csvColumns
+	columns := make([]int, 0, len(csvColumns))
+	for i, column := range csvColumns {
+		if selected(fields, column) {
+			columns = append(columns, i)
+		}
+	}
+
+	if err := w.Write(pickColumns(csvColumns, columns)); err != nil {
+		return err
+	}
+
+	for _, emp := range employees {
+		if err := w.Write(pickColumns([]string{
+			emp.ID,
+			csvSafe(emp.FirstName),
+			csvSafe(emp.LastName),
+			emp.DateOfBirth,
+			csvSafe(emp.Email),
+			strconv.FormatBool(emp.IsActive),
+			csvSafe(emp.Department),
+			csvSafe(emp.Role),
+			emp.ManagerID,
+			emp.HireDate,
+		}, columns)); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// Returns the values of a row at the given column indexes
+func pickColumns(row []string, columns []int) []string {
+	picked := make([]string, 0, len(columns))
+	for _, i := range columns {
+		picked = append(picked, row[i])
+	}
+
+	return picked
+}
+
+// Characters that make spreadsheets read a cell as a formula
+const csvFormulaChars = "=+-@\t\r"
+
+// Prefixes the text cells starting like a formula with an apostrophe so spreadsheets show them as text
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune(csvFormulaChars, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// Removes the apostrophe added by csvSafe so exports can be imported back
+func csvUnescape(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(csvFormulaChars, rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+// Writes the JSON export with the given fields of the employees only, all of them if fields is nil
+func writeJSONExport(c *gin.Context, employees []*repos.Employee, fields []string) error {
+	c.Header("Content-Type", "application/json")
+	c.Status(http.StatusOK)
+
+	if _, err := io.WriteString(c.Writer, "["); err != nil {
+		return err
+	}
+
+	for i, emp := range employees {
+		if i > 0 {
+			if _, err := io.WriteString(c.Writer, ","); err != nil {
+				return err
+			}
+		}
+
+		resp, err := project(newEmployee(emp), fields)
+		if err != nil {
+			return err
+		}
+
+		body, err := json.Marshal(resp)
+		if err != nil {
+			return err
+		}
+
+		if _, err := c.Writer.Write(body); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(c.Writer, "]")
+	return err
+}
+
+// Parses a CSV import; the first line is the header with the column names
+func parseCSVImport(r io.Reader) ([]importRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+
+		known := false
+		for _, column := range csvColumns {
+			if column == name {
+				known = true
+				break
+			}
+		}
+
+		if !known {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+
+		columns[name] = i
+	}
+
+	// Every row gets the same number of fields as the header
+	reader.FieldsPerRecord = len(header)
+
+	rows := make([]importRow, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			if errors.Is(err, csv.ErrFieldCount) {
+				rows = append(rows, importRow{errors: []ValidationErrorDtl{{Message: err.Error()}}})
+				continue
+			}
+			return nil, err
+		}
+
+		value := func(column string) string {
+			if i, ok := columns[column]; ok {
+				return csvUnescape(strings.TrimSpace(record[i]))
+			}
+			return ""
+		}
+
+		row := importRow{
+			emp: Employee{
+				FirstName:   value("first_name"),
+				LastName:    value("last_name"),
+				DateOfBirth: value("dob"),
+				Email:       value("email"),
+				Department:  value("department"),
+				Role:        value("role"),
+				HireDate:    value("hire_date"),
+			},
+		}
+
+		if managerID := value("manager_id"); managerID != "" {
+			row.emp.ManagerID = &managerID
+		}
+
+		if isActive := value("is_active"); isActive != "" {
+			parsed, err := strconv.ParseBool(isActive)
+			if err != nil {
+				row.errors = append(row.errors, ValidationErrorDtl{
+					Field:   "IsActive",
+					Message: fmt.Sprintf("invalid boolean %q", isActive),
+				})
+			}
+			row.emp.IsActive = parsed
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// Parses a JSON array import; each element is decoded on its own so
+// a malformed element only fails its row
+func parseJSONImport(r io.Reader) ([]importRow, error) {
+	var elements []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&elements); err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	rows := make([]importRow, 0, len(elements))
+	for _, element := range elements {
+		var row importRow
+
+		if err := json.Unmarshal(element, &row.emp); err != nil {
+			row.errors = []ValidationErrorDtl{{Message: err.Error()}}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"employeeapi/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// Test ImportEmployees handler
+func TestImportEmployees(t *testing.T) {
+	validCSV := "first_name,last_name,dob,email,is_active,department\n" +
+		"Jane,Smith,1990-09-22,jane.smith@example.com,true,Marketing\n" +
+		"Emily,Williams,1995-12-08,emily.williams@example.com,false,\n"
+
+	invalidCSV := validCSV +
+		"Robert,Johnson,1988-02-30,robert.johnson@example.com,true,Finance\n"
+
+	testCases := []struct {
+		name        string
+		query       string
+		contentType string
+		body        string
+		httpStatus  int
+		created     int
+		failed      int
+		statuses    []string
+	}{
+		{
+			name:        "Successful - Import Employees - csv",
+			contentType: "text/csv",
+			body:        validCSV,
+			httpStatus:  http.StatusCreated,
+			created:     2,
+			statuses:    []string{ImportRowCreated, ImportRowCreated},
+		},
+		{
+			name:        "Successful - Import Employees - json",
+			contentType: "application/json",
+			body:        `[{"first_name":"Jane","last_name":"Smith","dob":"1990-09-22","email":"jane.smith@example.com"}]`,
+			httpStatus:  http.StatusCreated,
+			created:     1,
+			statuses:    []string{ImportRowCreated},
+		},
+		{
+			name:        "Failed - Import Employees - all or nothing with invalid row",
+			contentType: "text/csv",
+			body:        invalidCSV,
+			httpStatus:  http.StatusUnprocessableEntity,
+			failed:      1,
+			statuses:    []string{ImportRowSkipped, ImportRowSkipped, ImportRowFailed},
+		},
+		{
+			name:        "Failed - Import Employees - all or nothing with email in use",
+			contentType: "application/json",
+			body:        `[{"first_name":"Jane","last_name":"Smith","dob":"1990-09-22","email":"jane.smith@example.com"},{"first_name":"John","last_name":"Doe","dob":"1985-05-15","email":"johndoe@example.com"}]`,
+			httpStatus:  http.StatusUnprocessableEntity,
+			failed:      1,
+			statuses:    []string{ImportRowSkipped, ImportRowFailed},
+		},
+		{
+			name:        "Successful - Import Employees - best effort with invalid rows",
+			query:       "?mode=" + ImportModeBestEffort,
+			contentType: "application/json",
+			body:        `[{"first_name":"Jane","last_name":"Smith","dob":"1990-09-22","email":"jane.smith@example.com"},{"first_name":"John","dob":"1985-05-15","email":"not-an-email"},{"first_name":1}]`,
+			httpStatus:  http.StatusOK,
+			created:     1,
+			failed:      2,
+			statuses:    []string{ImportRowCreated, ImportRowFailed, ImportRowFailed},
+		},
+		{
+			name:        "Failed - Import Employees - unknown column",
+			contentType: "text/csv",
+			body:        "first_name,salary\nJane,100\n",
+			httpStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "Failed - Import Employees - empty",
+			contentType: "application/json",
+			body:        `[]`,
+			httpStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "Failed - Import Employees - invalid mode",
+			query:       "?mode=sometimes",
+			contentType: "application/json",
+			body:        `[]`,
+			httpStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "Failed - Import Employees - unsupported content type",
+			contentType: "application/xml",
+			body:        `<employees/>`,
+			httpStatus:  http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, data := mockRepo("single")
+
+			svc := services.NewService(logger, false)
+			svc.EmpRepo = repo
+
+			h := NewHandler(logger, svc)
+
+			// Create a request to pass to our handler
+			req, err := http.NewRequest("POST", "/employees:import"+tc.query, strings.NewReader(tc.body))
+			require.NoError(t, err, "failed to create request")
+
+			req.Header.Set("Content-Type", tc.contentType)
+
+			rr := httptest.NewRecorder()
+
+			_, r := gin.CreateTestContext(rr)
+
+			// Set up routes
+			h.SetupRoutes(r)
+
+			// Call the handler
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.httpStatus, rr.Code)
+
+			if len(tc.statuses) > 0 {
+				result := ImportResult{}
+				err = json.Unmarshal(rr.Body.Bytes(), &result)
+				require.NoError(t, err, "failed to unmarshal response body")
+
+				require.Equal(t, tc.created, result.Created)
+				require.Equal(t, tc.failed, result.Failed)
+				require.Len(t, *data, 1+tc.created)
+
+				for i, row := range result.Rows {
+					require.Equal(t, i+1, row.Row)
+					require.Equal(t, tc.statuses[i], row.Status)
+
+					if row.Status == ImportRowFailed {
+						require.NotEmpty(t, row.Errors)
+					}
+					if row.Status == ImportRowCreated {
+						require.Contains(t, *data, row.ID)
+					}
+				}
+			}
+		})
+	}
+}
+
+// Test ExportEmployees handler
+func TestExportEmployees(t *testing.T) {
+	testCases := []struct {
+		name       string
+		path       string
+		httpStatus int
+	}{
+		{
+			name:       "Successful - Export Employees - json",
+			path:       "/employees:export",
+			httpStatus: http.StatusOK,
+		},
+		{
+			name:       "Successful - Export Employees - csv",
+			path:       "/employees:export?format=csv",
+			httpStatus: http.StatusOK,
+		},
+		{
+			name:       "Failed - Export Employees - unsupported format",
+			path:       "/employees:export?format=xml",
+			httpStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Failed - Unknown method",
+			path:       "/employees:archive",
+			httpStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, data := mockRepo("mult")
+
+			svc := services.NewService(logger, false)
+			svc.EmpRepo = repo
+
+			h := NewHandler(logger, svc)
+
+			// Create a request to pass to our handler
+			req, err := http.NewRequest("GET", tc.path, nil)
+			require.NoError(t, err, "failed to create request")
+
+			rr := httptest.NewRecorder()
+
+			_, r := gin.CreateTestContext(rr)
+
+			// Set up routes
+			h.SetupRoutes(r)
+
+			// Call the handler
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.httpStatus, rr.Code)
+
+			if rr.Code != http.StatusOK {
+				return
+			}
+
+			if strings.HasSuffix(tc.path, "csv") {
+				require.Equal(t, "text/csv", rr.Header().Get("Content-Type"))
+
+				records, err := csv.NewReader(rr.Body).ReadAll()
+				require.NoError(t, err, "failed to read csv")
+
+				require.Equal(t, csvColumns, records[0])
+				require.Len(t, records, len(*data)+1)
+
+				// Sorted by last name
+				require.Equal(t, employeeId1, records[1][0])
+				require.Equal(t, employeeId2, records[2][0])
+			} else {
+				employees := []Employee{}
+				err = json.Unmarshal(rr.Body.Bytes(), &employees)
+				require.NoError(t, err, "failed to unmarshal response body")
+
+				require.Len(t, employees, len(*data))
+			}
+		})
+	}
+}
+
+// Test that cells starting like a formula are exported as text and imported back unchanged
+func TestCSVSafe(t *testing.T) {
+	testCases := []struct {
+		value    string
+		exported string
+	}{
+		{value: "John", exported: "John"},
+		{value: "", exported: ""},
+		{value: "=HYPERLINK(\"http://example.com\")", exported: "'=HYPERLINK(\"http://example.com\")"},
+		{value: "+1", exported: "'+1"},
+		{value: "-1", exported: "'-1"},
+		{value: "@SUM(A1)", exported: "'@SUM(A1)"},
+		{value: "O'Brien", exported: "O'Brien"},
+		{value: "'quoted", exported: "'quoted"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			require.Equal(t, tc.exported, csvSafe(tc.value))
+			require.Equal(t, tc.value, csvUnescape(csvSafe(tc.value)))
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// HeaderAPIKey identifies the client for rate limiting; the client IP is used if it's not sent
+	// or isn't one of the configured keys
+	HeaderAPIKey = "X-API-Key"
+
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+	HeaderRetryAfter         = "Retry-After"
+
+	ErrorRateLimited  = "too many requests"
+	ErrorBodyTooLarge = "request body is too large"
+
+	// Idle clients are forgotten once their bucket is full again; they're looked for at this interval
+	rateLimitSweepInterval = time.Minute
+)
+
+// Limits are the request limits of each client
+type Limits struct {
+	// RequestsPerSecond is the rate the tokens of a client are refilled at; 0 disables rate limiting
+	RequestsPerSecond float64
+	// Burst is the number of requests a client can make at once
+	Burst int
+	// MaxBodyBytes caps the body of POST and PUT requests; 0 disables the cap
+	MaxBodyBytes int64
+	// MaxImportBodyBytes caps the body of imports and batches which are larger than other requests
+	MaxImportBodyBytes int64
+	// APIKeys are the keys clients are identified by instead of their IP
+	// Other keys are ignored so clients can't get a new bucket by sending a new key
+	APIKeys []string
+}
+
+// DefaultLimits are the limits of a handler unless set with WithLimits
+var DefaultLimits = Limits{
+	RequestsPerSecond:  10,
+	Burst:              20,
+	MaxBodyBytes:       1 << 20,
+	MaxImportBodyBytes: 10 << 20,
+}
+
+// Routes that aren't rate limited so that probes and scrapes keep working under load
+var unlimitedRoutes = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+// WithLimits returns a copy of the handler with the given request limits
+// Must be called before the routes are set up
+func (h Handler) WithLimits(limits Limits) Handler {
+	h.limits = limits
+	return h
+}
+
+// RateLimit is the middleware limiting the requests of each client with a token bucket
+// Clients are identified by a configured X-API-Key or their IP. Every response carries the
+// RateLimit-* headers; requests over the limit are 429 with Retry-After
+func (h Handler) RateLimit() gin.HandlerFunc {
+	limiter := newRateLimiter(h.limits.RequestsPerSecond, h.limits.Burst, time.Now)
+
+	return func(c *gin.Context) {
+		if h.limits.RequestsPerSecond <= 0 || unlimitedRoutes[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		allowed, remaining, reset := limiter.take(h.clientKey(c))
+
+		c.Header(HeaderRateLimitLimit, strconv.Itoa(h.limits.Burst))
+		c.Header(HeaderRateLimitRemaining, strconv.Itoa(remaining))
+		c.Header(HeaderRateLimitReset, strconv.Itoa(reset))
+
+		if !allowed {
+			l := h.requestLogger(c).With().Str("package", packageName).Str("func", "RateLimit").Logger()
+			l.Warn().Str("clientIP", c.ClientIP()).Msg("rate limited")
+
+			c.Header(HeaderRetryAfter, strconv.Itoa(reset))
+			h.abortWithError(c, &RequestError{Status: http.StatusTooManyRequests, Detail: ErrorRateLimited})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Identifies the client of a request by its X-API-Key header if it's one of the configured keys,
+// or its IP otherwise
+// The IP is taken from forwarding headers only for the trusted proxies of the engine
+func (h Handler) clientKey(c *gin.Context) string {
+	if apiKey := c.GetHeader(HeaderAPIKey); apiKey != "" {
+		for _, key := range h.limits.APIKeys {
+			if subtle.ConstantTimeCompare([]byte(apiKey), []byte(key)) == 1 {
+				return "key:" + key
+			}
+		}
+	}
+
+	return "ip:" + c.ClientIP()
+}
+
+// BodyLimit is the middleware capping the body of POST and PUT requests
+// Bodies declared larger than the cap are 413 right away; others are cut at the cap
+// and 413 once read past it
+func (h Handler) BodyLimit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodPost && c.Request.Method != http.MethodPut {
+			c.Next()
+			return
+		}
+
+		limit := h.limits.MaxBodyBytes
+		if path := routePath(c); path == employeesMethodPath+"import" || path == employeesMethodPath+"batch" {
+			limit = h.limits.MaxImportBodyBytes
+		}
+
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > limit {
+			l := h.requestLogger(c).With().Str("package", packageName).Str("func", "BodyLimit").Logger()
+			l.Error().Int64("contentLength", c.Request.ContentLength).Int64("limit", limit).Msg("request body is too large")
+
+			h.abortWithError(c, &http.MaxBytesError{Limit: limit})
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+
+		c.Next()
+	}
+}
+
+// tokenBucket holds the tokens of a client; one is taken per request
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter keeps a token bucket per client
+type rateLimiter struct {
+	rate      float64
+	burst     float64
+	now       func() time.Time
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+func newRateLimiter(rate float64, burst int, now func() time.Time) *rateLimiter {
+	return &rateLimiter{
+		rate:      rate,
+		burst:     float64(burst),
+		now:       now,
+		buckets:   make(map[string]*tokenBucket),
+		lastSweep: now(),
+	}
+}
+
+// Takes a token from the bucket of a client
+// Returns whether there was one, the tokens left and the seconds until the bucket is full again,
+// or until there's a token if there was none
+func (r *rateLimiter) take(key string) (bool, int, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	r.sweep(now)
+
+	bucket, ok := r.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: r.burst, last: now}
+		r.buckets[key] = bucket
+	}
+
+	bucket.tokens = r.refilled(bucket, now)
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		return false, 0, r.secondsFor(1 - bucket.tokens)
+	}
+
+	bucket.tokens--
+
+	return true, int(bucket.tokens), r.secondsFor(r.burst - bucket.tokens)
+}
+
+// Returns the tokens of a bucket refilled since it was last used
+func (r *rateLimiter) refilled(bucket *tokenBucket, now time.Time) float64 {
+	return math.Min(r.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*r.rate)
+}
+
+// Returns the seconds it takes to refill the given tokens, rounded up
+func (r *rateLimiter) secondsFor(tokens float64) int {
+	return int(math.Ceil(tokens / r.rate))
+}
+
+// Forgets the clients whose bucket is full again since they're no different from new ones
+func (r *rateLimiter) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	r.lastSweep = now
+
+	for key, bucket := range r.buckets {
+		if r.refilled(bucket, now) >= r.burst {
+			delete(r.buckets, key)
+		}
+	}
+}
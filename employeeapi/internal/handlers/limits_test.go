@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"employeeapi/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// Test that tokens are taken per client and refilled over time
+func TestRateLimiter(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(1, 2, func() time.Time { return now })
+
+	allowed, remaining, reset := limiter.take("a")
+	require.True(t, allowed)
+	require.Equal(t, 1, remaining)
+	require.Equal(t, 1, reset)
+
+	allowed, remaining, reset = limiter.take("a")
+	require.True(t, allowed)
+	require.Equal(t, 0, remaining)
+	require.Equal(t, 2, reset)
+
+	allowed, remaining, reset = limiter.take("a")
+	require.False(t, allowed)
+	require.Equal(t, 0, remaining)
+	require.Equal(t, 1, reset)
+
+	// Other clients have their own bucket
+	allowed, _, _ = limiter.take("b")
+	require.True(t, allowed)
+
+	now = now.Add(1500 * time.Millisecond)
+
+	allowed, remaining, _ = limiter.take("a")
+	require.True(t, allowed)
+	require.Equal(t, 0, remaining)
+
+	// Full buckets are forgotten
+	now = now.Add(rateLimitSweepInterval)
+	limiter.take("c")
+	require.Len(t, limiter.buckets, 1)
+}
+
+// Test RateLimit middleware
+func TestRateLimit(t *testing.T) {
+	repo, _ := mockRepo("single")
+
+	svc := services.NewService(logger, false)
+	svc.EmpRepo = repo
+
+	h := NewHandler(logger, svc).WithLimits(Limits{RequestsPerSecond: 0.001, Burst: 2, APIKeys: []string{"payroll-sync"}})
+
+	rr := httptest.NewRecorder()
+
+	_, r := gin.CreateTestContext(rr)
+
+	// Set up routes
+	h.SetupRoutes(r)
+
+	send := func(path, apiKey string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(t, err, "failed to create request")
+
+		if apiKey != "" {
+			req.Header.Set(HeaderAPIKey, apiKey)
+		}
+
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	rr = send("/employees", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, "2", rr.Header().Get(HeaderRateLimitLimit))
+	require.Equal(t, "1", rr.Header().Get(HeaderRateLimitRemaining))
+
+	rr = send("/employees", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, "0", rr.Header().Get(HeaderRateLimitRemaining))
+
+	rr = send("/employees", "")
+	require.Equal(t, http.StatusTooManyRequests, rr.Code)
+	require.Equal(t, "0", rr.Header().Get(HeaderRateLimitRemaining))
+	require.NotEmpty(t, rr.Header().Get(HeaderRetryAfter))
+	require.Equal(t, rr.Header().Get(HeaderRateLimitReset), rr.Header().Get(HeaderRetryAfter))
+
+	problem := Problem{}
+	err := json.Unmarshal(rr.Body.Bytes(), &problem)
+	require.NoError(t, err, "failed to unmarshal response body")
+	require.Equal(t, ErrorRateLimited, problem.Detail)
+
+	// Clients with a configured API key are limited on their own
+	rr = send("/employees", "payroll-sync")
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	// Other keys don't get a bucket of their own
+	rr = send("/employees", "made-up")
+	require.Equal(t, http.StatusTooManyRequests, rr.Code)
+
+	// Probes aren't limited
+	rr = send("/healthz", "")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Empty(t, rr.Header().Get(HeaderRateLimitLimit))
+}
+
+// Test that clients are identified by configured API keys and by IP otherwise
+func TestClientKey(t *testing.T) {
+	testCases := []struct {
+		name           string
+		apiKey         string
+		forwardedFor   string
+		trustedProxies []string
+		key            string
+	}{
+		{
+			name:   "Configured API key",
+			apiKey: "payroll-sync",
+			key:    "key:payroll-sync",
+		},
+		{
+			name:   "Unknown API key",
+			apiKey: "made-up",
+			key:    "ip:192.0.2.1",
+		},
+		{
+			name: "No API key",
+			key:  "ip:192.0.2.1",
+		},
+		{
+			name:         "Forwarded IP of an untrusted proxy",
+			forwardedFor: "203.0.113.7",
+			key:          "ip:192.0.2.1",
+		},
+		{
+			name:           "Forwarded IP of a trusted proxy",
+			forwardedFor:   "203.0.113.7",
+			trustedProxies: []string{"192.0.2.1"},
+			key:            "ip:203.0.113.7",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHandler(logger, services.NewService(logger, false)).WithLimits(Limits{APIKeys: []string{"payroll-sync"}})
+
+			c, r := gin.CreateTestContext(httptest.NewRecorder())
+			require.NoError(t, r.SetTrustedProxies(tc.trustedProxies))
+
+			c.Request = httptest.NewRequest("GET", "/employees", nil)
+			c.Request.RemoteAddr = "192.0.2.1:1234"
+			if tc.apiKey != "" {
+				c.Request.Header.Set(HeaderAPIKey, tc.apiKey)
+			}
+			if tc.forwardedFor != "" {
+				c.Request.Header.Set("X-Forwarded-For", tc.forwardedFor)
+			}
+
+			require.Equal(t, tc.key, h.clientKey(c))
+		})
+	}
+}
+
+// Test BodyLimit middleware
+func TestBodyLimit(t *testing.T) {
+	body := `{"first_name": "John", "last_name": "Smith", "dob": "1990-01-01", "email": "john.smith@example.com"}`
+
+	testCases := []struct {
+		name          string
+		path          string
+		contentType   string
+		body          string
+		unknownLength bool
+		httpStatus    int
+	}{
+		{
+			name:        "Successful - Body Limit - under the cap",
+			path:        "/employees",
+			contentType: "application/json",
+			body:        body,
+			httpStatus:  http.StatusCreated,
+		},
+		{
+			name:        "Failed - Body Limit - declared over the cap",
+			path:        "/employees",
+			contentType: "application/json",
+			body:        body + strings.Repeat(" ", 200),
+			httpStatus:  http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:          "Failed - Body Limit - read over the cap",
+			path:          "/employees",
+			contentType:   "application/json",
+			body:          body + strings.Repeat(" ", 200),
+			unknownLength: true,
+			httpStatus:    http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:        "Successful - Body Limit - import has its own cap",
+			path:        "/employees:import",
+			contentType: "text/csv",
+			body:        "first_name,last_name,dob,email\nJane,Smith,1990-09-22,jane.smith@example.com\n" + strings.Repeat("\n", 200),
+			httpStatus:  http.StatusCreated,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, _ := mockRepo("single")
+
+			svc := services.NewService(logger, false)
+			svc.EmpRepo = repo
+
+			h := NewHandler(logger, svc).WithLimits(Limits{MaxBodyBytes: 200, MaxImportBodyBytes: 1000})
+
+			var reqBody io.Reader = strings.NewReader(tc.body)
+			if tc.unknownLength {
+				// Hides the length so the body is only found too large once read
+				reqBody = io.MultiReader(reqBody)
+			}
+
+			req, err := http.NewRequest("POST", tc.path, reqBody)
+			require.NoError(t, err, "failed to create request")
+
+			req.Header.Set("Content-Type", tc.contentType)
+
+			rr := httptest.NewRecorder()
+
+			_, r := gin.CreateTestContext(rr)
+
+			// Set up routes
+			h.SetupRoutes(r)
+
+			// Call the handler
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.httpStatus, rr.Code)
+
+			if tc.httpStatus == http.StatusRequestEntityTooLarge {
+				problem := Problem{}
+				err = json.Unmarshal(rr.Body.Bytes(), &problem)
+				require.NoError(t, err, "failed to unmarshal response body")
+				require.Equal(t, ErrorBodyTooLarge, problem.Detail)
+			}
+		})
+	}
+}
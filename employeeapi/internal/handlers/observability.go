@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// HeaderRequestID correlates the logs of a request; it's generated if not sent
+	HeaderRequestID = "X-Request-ID"
+
+	// RouteUnmatched is the route of requests that didn't match any route
+	RouteUnmatched = "unmatched"
+)
+
+// Request ids sent by clients are only accepted if they can't break the logs
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestDuration observes the latency of requests by method, route and status
+var RequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "employeeapi",
+	Subsystem: "http",
+	Name:      "request_duration_seconds",
+	Help:      "Latency of HTTP requests by method, route and status.",
+	Buckets:   prometheus.DefBuckets,
+}, []string{"method", "route", "status"})
+
+var metricsHandler = promhttp.Handler()
+
+type requestIDCtxKey struct{}
+
+// RequestID is the middleware assigning each request an id: the X-Request-ID header if it's
+// valid or a new one otherwise
+// The id is sent back in the X-Request-ID header and added to the logs of the request
+func (h Handler) RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(HeaderRequestID)
+		if !validRequestID.MatchString(id) {
+			id = uuid.New().String()
+		}
+
+		c.Header(HeaderRequestID, id)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), requestIDCtxKey{}, id))
+
+		c.Next()
+	}
+}
+
+// AccessLog is the middleware logging each request once it's served and observing its latency
+func (h Handler) AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		latency := time.Since(start)
+		route := routePath(c)
+		if route == "" {
+			route = RouteUnmatched
+		}
+
+		RequestDuration.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).Observe(latency.Seconds())
+
+		l := h.requestLogger(c)
+
+		event := l.Info()
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			event = l.Error()
+		}
+
+		event.Str("method", c.Request.Method).
+			Str("path", c.Request.URL.Path).
+			Str("route", route).
+			Int("status", c.Writer.Status()).
+			Dur("latency", latency).
+			Int("size", c.Writer.Size()).
+			Str("client_ip", c.ClientIP()).
+			Str("user_agent", c.Request.UserAgent()).
+			Msg("request served")
+	}
+}
+
+// Recovery is the middleware recovering from panics in handlers; they're sent as 500 problem responses
+func (h Handler) Recovery() gin.HandlerFunc {
+	return gin.CustomRecovery(h.recovered)
+}
+
+// Metrics serves the Prometheus metrics
+func (h Handler) Metrics(c *gin.Context) {
+	metricsHandler.ServeHTTP(c.Writer, c.Request)
+}
+
+func (h Handler) recovered(c *gin.Context, recovered any) {
+	l := h.requestLogger(c).With().Str("package", packageName).Str("func", "recovered").Logger()
+
+	err := fmt.Errorf("panic: %v", recovered)
+	l.Error().Err(err).Msg("recovered from panic")
+
+	h.abortWithError(c, err)
+}
+
+// Returns the logger of a request; its entries carry the request id
+func (h Handler) requestLogger(c *gin.Context) zerolog.Logger {
+	id := requestID(c)
+	if id == "" {
+		return h.logger
+	}
+
+	return h.logger.With().Str("request_id", id).Logger()
+}
+
+// Returns the id of a request; see RequestID
+func requestID(c *gin.Context) string {
+	id, _ := c.Request.Context().Value(requestIDCtxKey{}).(string)
+	return id
+}
+
+// Returns the route of a request, with the method of documented "/employees:<method>" routes
+// so that unknown methods don't make up routes; empty if the request matched no route
+func routePath(c *gin.Context) string {
+	path := c.FullPath()
+	if strings.HasSuffix(path, employeesMethodPath+"method") {
+		withMethod := strings.TrimSuffix(path, ":method") + c.Param("method")
+		if _, ok := operations[c.Request.Method+" "+withMethod]; ok {
+			path = withMethod
+		}
+	}
+
+	return path
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"employeeapi/internal/repos"
+	"employeeapi/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// Test that requests are assigned ids that are sent back and added to the logs
+func TestRequestID(t *testing.T) {
+	testCases := []struct {
+		name      string
+		requestID string
+		kept      bool
+	}{
+		{
+			name:      "Successful - Request ID - propagated",
+			requestID: "3f2b8c1e-trace.42",
+			kept:      true,
+		},
+		{
+			name: "Successful - Request ID - generated",
+		},
+		{
+			name:      "Successful - Request ID - invalid is replaced",
+			requestID: "bad id\n{\"level\":\"fake\"}",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, _ := mockRepo("single")
+
+			svc := services.NewService(logger, false)
+			svc.EmpRepo = repo
+
+			var logs bytes.Buffer
+			h := NewHandler(zerolog.New(&logs), svc)
+
+			req, err := http.NewRequest("GET", "/employees/"+employeeId1, nil)
+			require.NoError(t, err, "failed to create request")
+
+			if tc.requestID != "" {
+				req.Header.Set(HeaderRequestID, tc.requestID)
+			}
+
+			rr := httptest.NewRecorder()
+
+			_, r := gin.CreateTestContext(rr)
+
+			// Set up routes
+			h.SetupRoutes(r)
+
+			// Call the handler
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, http.StatusOK, rr.Code)
+
+			requestID := rr.Header().Get(HeaderRequestID)
+			require.NotEmpty(t, requestID)
+			if tc.kept {
+				require.Equal(t, tc.requestID, requestID)
+			} else {
+				require.NotEqual(t, tc.requestID, requestID)
+			}
+
+			// The access log is the last entry
+			lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+
+			entry := map[string]any{}
+			err = json.Unmarshal([]byte(lines[len(lines)-1]), &entry)
+			require.NoError(t, err, "failed to unmarshal access log")
+
+			require.Equal(t, requestID, entry["request_id"])
+			require.Equal(t, "GET", entry["method"])
+			require.Equal(t, "/employees/:id", entry["route"])
+			require.Equal(t, float64(http.StatusOK), entry["status"])
+		})
+	}
+}
+
+// Test that the repository logs carry the id of the request
+func TestRequestIDRepositoryLogs(t *testing.T) {
+	var logs bytes.Buffer
+
+	svc := services.NewService(logger, false)
+	svc.EmpRepo = repos.NewEmployeeRepo(zerolog.New(&logs), nil)
+
+	h := NewHandler(logger, svc)
+
+	req, err := http.NewRequest("DELETE", "/departments/Nowhere", nil)
+	require.NoError(t, err, "failed to create request")
+
+	req.Header.Set(HeaderRequestID, "3f2b8c1e-trace.42")
+
+	rr := httptest.NewRecorder()
+
+	_, r := gin.CreateTestContext(rr)
+
+	// Set up routes
+	h.SetupRoutes(r)
+
+	// Call the handler
+	r.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusNotFound, rr.Code)
+
+	entry := map[string]any{}
+	err = json.Unmarshal(bytes.TrimSpace(logs.Bytes()), &entry)
+	require.NoError(t, err, "failed to unmarshal repository log")
+
+	require.Equal(t, "deleteCatalogueEntry", entry["func"])
+	require.Equal(t, "3f2b8c1e-trace.42", entry["request_id"])
+}
+
+// Test that request latencies and repository operations are exposed as metrics
+func TestMetrics(t *testing.T) {
+	svc := services.NewService(logger, false)
+
+	h := NewHandler(logger, svc)
+
+	rr := httptest.NewRecorder()
+
+	_, r := gin.CreateTestContext(rr)
+
+	// Set up routes
+	h.SetupRoutes(r)
+
+	for _, path := range []string{"/employees", "/employees:export?format=csv", "/managers"} {
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(t, err, "failed to create request")
+
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	req, err := http.NewRequest("GET", "/metrics", nil)
+	require.NoError(t, err, "failed to create request")
+
+	r.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	body := rr.Body.String()
+	require.Contains(t, body, `employeeapi_http_request_duration_seconds_count{method="GET",route="/employees",status="200"}`)
+	require.Contains(t, body, `employeeapi_http_request_duration_seconds_count{method="GET",route="/employees:export",status="200"}`)
+	require.Contains(t, body, `employeeapi_http_request_duration_seconds_count{method="GET",route="`+RouteUnmatched+`",status="404"}`)
+	require.Contains(t, body, `employeeapi_repo_operations_total{operation="GetEmployees",result="ok"}`)
+}
+
+// Test that panics in handlers are sent as problem responses
+func TestRecovery(t *testing.T) {
+	repo, _ := mockRepo("single")
+
+	svc := services.NewService(logger, false)
+	svc.EmpRepo = repo
+
+	h := NewHandler(logger, svc)
+
+	req, err := http.NewRequest("GET", "/panic", nil)
+	require.NoError(t, err, "failed to create request")
+
+	rr := httptest.NewRecorder()
+
+	_, r := gin.CreateTestContext(rr)
+
+	// Set up routes
+	h.SetupRoutes(r)
+	r.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	// Call the handler
+	r.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusInternalServerError, rr.Code)
+	require.Equal(t, ContentTypeProblem, rr.Header().Get("Content-Type"))
+
+	problem := Problem{}
+	err = json.Unmarshal(rr.Body.Bytes(), &problem)
+	require.NoError(t, err, "failed to unmarshal response body")
+
+	require.Equal(t, http.StatusInternalServerError, problem.Status)
+	require.Empty(t, problem.Detail)
+}
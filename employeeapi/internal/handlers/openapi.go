@@ -0,0 +1,664 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/openapi3gen"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	APITitle   = "Employee API"
+	APIVersion = "1.0.0"
+
+	ErrorSpecUnavailable = "openapi document is unavailable"
+
+	componentSchemasPath = "#/components/schemas/"
+
+	// Prefix of the "/employees:<method>" routes; see employeesMethod
+	employeesMethodPath = "/employees:"
+)
+
+// swaggerUI is the page rendering the OpenAPI document with Swagger UI
+const swaggerUI = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8" />
+  <title>` + APITitle + `</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css" />
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>`
+
+// apiOperation documents a route; the request body and response are generated from the Go types
+type apiOperation struct {
+	summary string
+	params  openapi3.Parameters
+	// body is a value of the request body type, nil if the route takes no body
+	body any
+	// content overrides the content of the request body when it's not just JSON
+	content openapi3.Content
+	status  int
+	// response is a value of the response body type, nil if the route responds with no body
+	response any
+	// produces is the content type of the response, JSON if empty
+	produces []string
+}
+
+// operations documents the routes keyed by "<METHOD> <path>" where path is the gin path
+// Each "/employees:<method>" is documented on its own
+var operations = map[string]apiOperation{
+	"GET /employees": {
+		summary:  "List employees",
+		params:   openapi3.Parameters{includeDeletedParam, asOfParam, fieldsParam},
+		response: []Employee{},
+	},
+	"GET /employees/duplicates": {
+		summary:  "List groups of employees sharing a name and date of birth",
+		params:   openapi3.Parameters{fieldsParam},
+		response: []DuplicateGroup{},
+	},
+	"GET /employees/search": {
+		summary: "Search employees by name, email, department or role",
+		params: openapi3.Parameters{
+			queryParam("q", "Search terms; partial and misspelled terms are matched", openapi3.NewStringSchema(), true),
+			queryParam("limit", "Maximum number of results",
+				openapi3.NewIntegerSchema().WithMin(1).WithMax(MaxSearchLimit).WithDefault(DefaultSearchLimit), false),
+			fieldsParam,
+		},
+		response: []SearchResult{},
+	},
+	"GET /employees/:id": {
+		summary:  "Get an employee",
+		params:   openapi3.Parameters{includeDeletedParam, asOfParam, fieldsParam},
+		response: Employee{},
+	},
+	"POST /employees": {
+		summary:  "Create an employee",
+		params:   openapi3.Parameters{fieldsParam},
+		body:     Employee{},
+		status:   http.StatusCreated,
+		response: Employee{},
+	},
+	"PUT /employees/:id": {
+		summary:  "Update an employee",
+		params:   openapi3.Parameters{fieldsParam},
+		body:     Employee{},
+		response: Employee{},
+	},
+	"DELETE /employees/:id": {
+		summary: "Delete an employee; it can be restored until it's purged",
+	},
+	"GET /employees/:id/history": {
+		summary:  "List the changes made to an employee",
+		params:   openapi3.Parameters{fieldsParam},
+		response: []AuditEntry{},
+	},
+	"GET /employees/:id/timeline": {
+		summary:  "List the department, role and active status periods of an employee",
+		params:   openapi3.Parameters{fieldsParam},
+		response: []Period{},
+	},
+	"GET /employees/:id/reports": {
+		summary:  "List the direct and transitive reports of an employee",
+		params:   openapi3.Parameters{fieldsParam},
+		response: Reports{},
+	},
+	"GET /employees/:id/chain": {
+		summary:  "List the managers of an employee up to the top of the organisation",
+		params:   openapi3.Parameters{fieldsParam},
+		response: []Employee{},
+	},
+	"GET /orgchart": {
+		summary:  "Get the organisation chart",
+		params:   openapi3.Parameters{fieldsParam},
+		response: []OrgNode{},
+	},
+	"GET /employees/events": {
+		summary: "Stream the employee events as Server-Sent Events",
+		params: openapi3.Parameters{
+			&openapi3.ParameterRef{Value: openapi3.NewHeaderParameter(HeaderLastEventID).
+				WithDescription("Id of the last event received, to resume the stream after it").
+				WithSchema(openapi3.NewIntegerSchema().WithMin(0))},
+			fieldsParam,
+		},
+		response: EmployeeEvent{},
+		produces: []string{ContentTypeEventStream},
+	},
+	"GET /reports/headcount": {
+		summary: "Report the headcount by department, role and active status, the age bands and the upcoming birthdays and anniversaries",
+		params: openapi3.Parameters{
+			queryParam("format", "Format of the report; the CSV has a row per group, age band and upcoming event",
+				openapi3.NewStringSchema().WithEnum(ExportFormatJSON, ExportFormatCSV).WithDefault(ExportFormatJSON), false),
+			queryParam("days", "Number of days ahead the birthdays and anniversaries are looked for",
+				openapi3.NewIntegerSchema().WithMin(1).WithMax(MaxUpcomingDays).WithDefault(DefaultUpcomingDays), false),
+			asOfParam,
+			fieldsParam,
+		},
+		response: HeadcountReport{},
+		produces: []string{gin.MIMEJSON, "text/csv"},
+	},
+	"GET /employees:export": {
+		summary: "Export employees",
+		params: openapi3.Parameters{
+			queryParam("format", "Format of the export",
+				openapi3.NewStringSchema().WithEnum(ExportFormatJSON, ExportFormatCSV).WithDefault(ExportFormatJSON), false),
+			fieldsParam,
+		},
+		response: []Employee{},
+		produces: []string{gin.MIMEJSON, "text/csv"},
+	},
+	"POST /employees:import": {
+		summary: "Import employees from JSON or CSV; rows are validated one by one",
+		params: openapi3.Parameters{
+			queryParam("mode", "Whether invalid rows abort the whole import",
+				openapi3.NewStringSchema().WithEnum(ImportModeAllOrNothing, ImportModeBestEffort).WithDefault(ImportModeAllOrNothing), false),
+		},
+		// Rows are validated by the import itself so one invalid row doesn't fail the request
+		content: openapi3.Content{
+			gin.MIMEJSON: openapi3.NewMediaType().WithSchema(openapi3.NewArraySchema().WithItems(openapi3.NewObjectSchema())),
+			"text/csv":   openapi3.NewMediaType().WithSchema(openapi3.NewStringSchema()),
+		},
+		status:   http.StatusCreated,
+		response: ImportResult{},
+	},
+	"POST /employees:batch": {
+		summary: "Create, update and delete employees in a single write; nothing is written if an operation fails",
+		params: openapi3.Parameters{
+			queryParam("dry_run", "Checks every operation without writing anything",
+				openapi3.NewBoolSchema().WithDefault(false), false),
+			fieldsParam,
+		},
+		// Operations are validated by the batch itself so they're reported one by one
+		content: openapi3.Content{
+			gin.MIMEJSON: openapi3.NewMediaType().WithSchema(openapi3.NewArraySchema().WithItems(openapi3.NewObjectSchema())),
+		},
+		response: BatchResult{},
+	},
+	"POST /employees/:id/restore": {
+		summary:  "Restore a deleted employee",
+		params:   openapi3.Parameters{fieldsParam},
+		response: Employee{},
+	},
+	"POST /admin/employees/purge": {
+		summary: "Permanently remove employees deleted longer than the retention period; requires the admin bearer token",
+		params: openapi3.Parameters{
+			queryParam("retention", "Duration deleted employees are kept, at least 24h, e.g. 720h",
+				openapi3.NewStringSchema().WithDefault(DefaultPurgeRetention.String()), false),
+		},
+		response: PurgeResult{},
+	},
+
+	"GET /departments":          {summary: "List departments", response: []CatalogueEntry{}},
+	"GET /departments/:name":    {summary: "Get a department", response: CatalogueEntry{}},
+	"POST /departments":         {summary: "Create a department", body: CatalogueEntry{}, status: http.StatusCreated, response: CatalogueEntry{}},
+	"PUT /departments/:name":    {summary: "Update a department", body: CatalogueEntry{}, response: CatalogueEntry{}},
+	"DELETE /departments/:name": {summary: "Delete a department that's not in use"},
+	"GET /roles":                {summary: "List roles", response: []CatalogueEntry{}},
+	"GET /roles/:name":          {summary: "Get a role", response: CatalogueEntry{}},
+	"POST /roles":               {summary: "Create a role", body: CatalogueEntry{}, status: http.StatusCreated, response: CatalogueEntry{}},
+	"PUT /roles/:name":          {summary: "Update a role", body: CatalogueEntry{}, response: CatalogueEntry{}},
+	"DELETE /roles/:name":       {summary: "Delete a role that's not in use"},
+
+	"GET /webhooks":                {summary: "List webhook subscriptions", response: []Webhook{}},
+	"GET /webhooks/:id":            {summary: "Get a webhook subscription", response: Webhook{}},
+	"POST /webhooks":               {summary: "Subscribe a URL to employee events", body: Webhook{}, status: http.StatusCreated, response: Webhook{}},
+	"PUT /webhooks/:id":            {summary: "Update a webhook subscription", body: Webhook{}, response: Webhook{}},
+	"DELETE /webhooks/:id":         {summary: "Delete a webhook subscription"},
+	"GET /webhooks/:id/deliveries": {summary: "List the deliveries of a webhook subscription", response: []WebhookDelivery{}},
+
+	"GET /healthz":      {summary: "Check the server is alive", response: HealthStatus{}},
+	"GET /readyz":       {summary: "Check the server accepts traffic; unavailable while shutting down", response: HealthStatus{}},
+	"GET /metrics":      {summary: "Get the Prometheus metrics", produces: []string{"text/plain"}},
+	"GET /openapi.json": {summary: "Get this OpenAPI document"},
+	"GET /docs":         {summary: "Browse this OpenAPI document with Swagger UI", produces: []string{gin.MIMEHTML}},
+}
+
+var includeDeletedParam = queryParam("include_deleted", "Whether deleted employees are included", openapi3.NewBoolSchema(), false)
+
+var asOfParam = queryParam("as_of",
+	"Sends the employees as they were at a past time: their department, role and active status then. "+
+		"A RFC 3339 time, or a date for the end of that day in UTC",
+	openapi3.NewStringSchema(), false)
+
+var fieldsParam = queryParam(QueryFields,
+	"Comma separated fields of the employees to send, all of them if not given; leave out "+
+		strings.Join(PIIFields, " and ")+" to get responses without PII",
+	openapi3.NewStringSchema(), false)
+
+// Returns the definition of a query parameter
+func queryParam(name, description string, schema *openapi3.Schema, required bool) *openapi3.ParameterRef {
+	param := openapi3.NewQueryParameter(name).WithDescription(description).WithSchema(schema)
+	param.Required = required
+
+	return &openapi3.ParameterRef{Value: param}
+}
+
+// openAPI is the document generated from the routes and the routes to validate requests against
+type openAPI struct {
+	doc *openapi3.T
+	// routes are keyed by "<METHOD> <path>" like operations
+	routes map[string]*routers.Route
+}
+
+// Generates the OpenAPI document of the routes
+func newOpenAPI(routes gin.RoutesInfo) (*openAPI, error) {
+	doc := &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info: &openapi3.Info{
+			Title:   APITitle,
+			Version: APIVersion,
+		},
+		Paths: openapi3.NewPaths(),
+		Components: &openapi3.Components{
+			Schemas: openapi3.Schemas{},
+		},
+	}
+
+	api := &openAPI{
+		doc:    doc,
+		routes: make(map[string]*routers.Route),
+	}
+
+	if _, err := api.schemaRef(reflect.TypeOf(Problem{})); err != nil {
+		return nil, err
+	}
+
+	for _, route := range routes {
+		// The wildcard is documented as one path per method name
+		if strings.HasSuffix(route.Path, employeesMethodPath+"method") {
+			for _, key := range sortedOperationKeys() {
+				if strings.HasPrefix(key, route.Method+" "+employeesMethodPath) {
+					if err := api.addOperation(route.Method, strings.TrimPrefix(key, route.Method+" ")); err != nil {
+						return nil, err
+					}
+				}
+			}
+			continue
+		}
+
+		if err := api.addOperation(route.Method, route.Path); err != nil {
+			return nil, err
+		}
+	}
+
+	// Schemas referenced by the generated ones, e.g. those of recursive types, are only
+	// known by their reference until resolved
+	if err := openapi3.NewLoader().ResolveRefsIn(doc, nil); err != nil {
+		return nil, err
+	}
+
+	return api, nil
+}
+
+// Adds the operation of a route to the document
+func (a *openAPI) addOperation(method, path string) error {
+	op := operations[method+" "+path]
+
+	operation := openapi3.NewOperation()
+	operation.Summary = op.summary
+	operation.Parameters = append(pathParams(path), op.params...)
+
+	// Every POST can be retried safely with an idempotency key; see Idempotency
+	if method == http.MethodPost {
+		operation.Parameters = append(operation.Parameters, idempotencyKeyParam)
+	}
+
+	if op.body != nil || op.content != nil {
+		content := op.content
+		if content == nil {
+			schema, err := a.schemaRef(reflect.TypeOf(op.body))
+			if err != nil {
+				return err
+			}
+			content = openapi3.NewContentWithJSONSchemaRef(schema)
+		}
+
+		operation.RequestBody = &openapi3.RequestBodyRef{
+			Value: openapi3.NewRequestBody().WithRequired(true).WithContent(content),
+		}
+	}
+
+	status := op.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	response := openapi3.NewResponse().WithDescription(http.StatusText(status))
+	if op.response != nil {
+		schema, err := a.schemaRef(reflect.TypeOf(op.response))
+		if err != nil {
+			return err
+		}
+
+		produces := op.produces
+		if len(produces) == 0 {
+			produces = []string{gin.MIMEJSON}
+		}
+
+		response.Content = openapi3.Content{}
+		for _, contentType := range produces {
+			response.Content[contentType] = openapi3.NewMediaType().WithSchemaRef(schema)
+		}
+	} else if len(op.produces) > 0 {
+		response.Content = openapi3.NewContentWithSchema(openapi3.NewStringSchema(), op.produces)
+	}
+
+	operation.AddResponse(status, response)
+
+	// Every error is sent as a problem; see ErrorHandler
+	operation.Responses.Set("default", &openapi3.ResponseRef{
+		Value: openapi3.NewResponse().
+			WithDescription("Problem").
+			WithContent(openapi3.Content{
+				ContentTypeProblem: openapi3.NewMediaType().WithSchemaRef(
+					openapi3.NewSchemaRef(componentSchemasPath+"Problem", a.doc.Components.Schemas["Problem"].Value)),
+			}),
+	})
+
+	specPath := specPath(path)
+	a.doc.AddOperation(specPath, method, operation)
+
+	pathItem := a.doc.Paths.Value(specPath)
+	a.routes[method+" "+path] = &routers.Route{
+		Spec:      a.doc,
+		Path:      specPath,
+		PathItem:  pathItem,
+		Method:    method,
+		Operation: operation,
+	}
+
+	return nil
+}
+
+// Returns the schema of a type; named structs are added to the components and referenced
+func (a *openAPI) schemaRef(t reflect.Type) (*openapi3.SchemaRef, error) {
+	if t.Kind() == reflect.Slice {
+		items, err := a.schemaRef(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		schema := openapi3.NewArraySchema()
+		schema.Items = items
+
+		return openapi3.NewSchemaRef("", schema), nil
+	}
+
+	name := t.Name()
+	if schema, ok := a.doc.Components.Schemas[name]; ok {
+		return openapi3.NewSchemaRef(componentSchemasPath+name, schema.Value), nil
+	}
+
+	schema, err := openapi3gen.NewSchemaRefForValue(reflect.Zero(t).Interface(), a.doc.Components.Schemas,
+		openapi3gen.SchemaCustomizer(customizeSchema))
+	if err != nil {
+		return nil, err
+	}
+
+	a.doc.Components.Schemas[name] = schema
+
+	return openapi3.NewSchemaRef(componentSchemasPath+name, schema.Value), nil
+}
+
+// OpenAPI serves the OpenAPI document of the API
+func (h Handler) OpenAPI(c *gin.Context) {
+	if h.openapi.doc == nil {
+		h.abortWithError(c, &RequestError{Status: http.StatusServiceUnavailable, Detail: ErrorSpecUnavailable})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.openapi.doc)
+}
+
+// SwaggerUI serves the page browsing the OpenAPI document
+func (h Handler) SwaggerUI(c *gin.Context) {
+	c.Data(http.StatusOK, gin.MIMEHTML+"; charset=utf-8", []byte(swaggerUI))
+}
+
+// RequestValidator is the middleware rejecting requests that don't match the OpenAPI document:
+// unknown or malformed query parameters and request bodies not matching the schema
+// Requests to routes not in the document are passed through
+func (h Handler) RequestValidator() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		l := h.requestLogger(c).With().Str("package", packageName).Str("func", "RequestValidator").Logger()
+
+		path := routePath(c)
+		route, ok := h.openapi.routes[c.Request.Method+" "+path]
+		if !ok {
+			c.Next()
+			return
+		}
+
+		pathParams := make(map[string]string)
+		for _, param := range c.Params {
+			pathParams[param.Key] = param.Value
+		}
+
+		options := &openapi3filter.Options{
+			MultiError: true,
+			// Defaults are applied by the handlers; the body is left as is
+			SkipSettingDefaults: true,
+		}
+
+		// Unsupported content types are left to the handler which responds with 415
+		if body := route.Operation.RequestBody; body != nil && body.Value.Content.Get(c.ContentType()) == nil {
+			options.ExcludeRequestBody = true
+		}
+
+		err := openapi3filter.ValidateRequest(c.Request.Context(), &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		})
+		if err != nil {
+			l.Error().Err(err).Str("path", path).Msg("request doesn't match the openapi document")
+
+			// The body is over the cap rather than invalid; see BodyLimit
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				h.abortWithError(c, err)
+				return
+			}
+
+			h.abortWithError(c, newSpecRequestError(err))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Maps the errors of validating a request against the document to a request error
+// listing the invalid parameters and body fields
+func newSpecRequestError(err error) *RequestError {
+	reqErr := &RequestError{Detail: ErrorInvalidQueryParam}
+
+	var errs []error
+	if multiErr, ok := err.(openapi3.MultiError); ok {
+		errs = multiErr
+	} else {
+		errs = []error{err}
+	}
+
+	for i, err := range errs {
+		filterErr, ok := err.(*openapi3filter.RequestError)
+		if !ok {
+			reqErr.Errors = append(reqErr.Errors, ValidationErrorDtl{Message: err.Error()})
+			continue
+		}
+
+		if filterErr.RequestBody != nil && i == 0 {
+			reqErr.Detail = ErrorInvalidBody
+		}
+
+		field := ""
+		if filterErr.Parameter != nil {
+			field = filterErr.Parameter.Name
+		}
+
+		reqErr.Errors = append(reqErr.Errors, validationErrorDetailsOf(field, filterErr)...)
+	}
+
+	return reqErr
+}
+
+// Returns the invalid fields of a parameter or request body error
+func validationErrorDetailsOf(field string, err *openapi3filter.RequestError) []ValidationErrorDtl {
+	var schemaErrs []error
+	switch cause := err.Err.(type) {
+	case openapi3.MultiError:
+		schemaErrs = cause
+	case *openapi3.SchemaError:
+		schemaErrs = []error{cause}
+	case nil:
+		return []ValidationErrorDtl{{Field: field, Message: err.Reason}}
+	default:
+		return []ValidationErrorDtl{{Field: field, Message: cause.Error()}}
+	}
+
+	errDtls := make([]ValidationErrorDtl, 0)
+	for _, schemaErr := range schemaErrs {
+		e, ok := schemaErr.(*openapi3.SchemaError)
+		if !ok {
+			errDtls = append(errDtls, ValidationErrorDtl{Field: field, Message: schemaErr.Error()})
+			continue
+		}
+
+		// Body fields are named by their path in the body, e.g. events.0
+		name := field
+		if pointer := e.JSONPointer(); len(pointer) > 0 {
+			name = strings.Join(pointer, ".")
+		}
+
+		errDtls = append(errDtls, ValidationErrorDtl{Field: name, Message: e.Reason})
+	}
+
+	return errDtls
+}
+
+// Documents the validation rules of struct fields and makes fields that may be null nullable
+// Only rules that hold for every value are documented; e.g. date formats are left to the validator
+// so that its messages are kept
+func customizeSchema(name string, t reflect.Type, tag reflect.StructTag, schema *openapi3.Schema) error {
+	if t.Kind() != reflect.Struct || t == reflect.TypeOf(time.Time{}) {
+		return nil
+	}
+
+	for _, field := range structFields(t) {
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		prop, ok := schema.Properties[jsonName]
+		if !ok || prop.Value == nil || strings.HasPrefix(prop.Ref, componentSchemasPath) {
+			continue
+		}
+
+		switch field.Type.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map:
+			prop.Value.Nullable = true
+		}
+
+		for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+			rule, param, _ := strings.Cut(rule, "=")
+
+			switch rule {
+			case "required":
+				schema.Required = append(schema.Required, jsonName)
+			case "max":
+				if max, err := strconv.ParseUint(param, 10, 64); err == nil && field.Type.Kind() == reflect.String {
+					prop.Value.MaxLength = &max
+				}
+			case "email":
+				prop.Value.Format = "email"
+			case "url":
+				prop.Value.Format = "uri"
+			case "dob":
+				prop.Value.Description = "Date of birth in YYYY-MM-DD format"
+			case "hire_date":
+				prop.Value.Description = "Hire date in YYYY-MM-DD format; work anniversaries count from it"
+			case "age":
+				prop.Value.Description += ", within the ages of the validation policy"
+			case "company_email":
+				prop.Value.Description = "Email at one of the domains of the validation policy, if any"
+			case "name":
+				prop.Value.Description = "Letters and the characters allowed by the validation policy"
+			case "department":
+				prop.Value.Description = "Name of a department in /departments"
+			case "role":
+				prop.Value.Description = "Name of a role in /roles"
+			}
+		}
+	}
+
+	return nil
+}
+
+// Returns the fields of a struct including those of embedded structs
+func structFields(t reflect.Type) []reflect.StructField {
+	fields := make([]reflect.StructField, 0)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			fields = append(fields, structFields(field.Type)...)
+			continue
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// Returns the path parameters of a gin path
+func pathParams(path string) openapi3.Parameters {
+	params := openapi3.Parameters{}
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, ":") {
+			params = append(params, &openapi3.ParameterRef{
+				Value: openapi3.NewPathParameter(segment[1:]).WithSchema(openapi3.NewStringSchema()),
+			})
+		}
+	}
+
+	return params
+}
+
+// Converts a gin path to an OpenAPI path, e.g. /employees/:id to /employees/{id}
+func specPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// Returns the keys of the documented operations in order
+func sortedOperationKeys() []string {
+	keys := make([]string, 0, len(operations))
+	for key := range operations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"employeeapi/internal/services"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// Test that the OpenAPI document is valid and documents every route
+func TestOpenAPI(t *testing.T) {
+	repo, _ := mockRepo("single")
+
+	svc := services.NewService(logger, false)
+	svc.EmpRepo = repo
+
+	h := NewHandler(logger, svc)
+
+	req, err := http.NewRequest("GET", "/openapi.json", nil)
+	require.NoError(t, err, "failed to create request")
+
+	rr := httptest.NewRecorder()
+
+	_, r := gin.CreateTestContext(rr)
+
+	// Set up routes
+	h.SetupRoutes(r)
+
+	// Call the handler
+	r.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	doc, err := openapi3.NewLoader().LoadFromData(rr.Body.Bytes())
+	require.NoError(t, err, "failed to load openapi document")
+	require.NoError(t, doc.Validate(context.Background()), "openapi document is invalid")
+
+	for _, route := range r.Routes() {
+		if strings.HasSuffix(route.Path, employeesMethodPath+"method") {
+			continue
+		}
+
+		_, ok := operations[route.Method+" "+route.Path]
+		require.True(t, ok, "route %s %s is not documented", route.Method, route.Path)
+
+		pathItem := doc.Paths.Value(specPath(route.Path))
+		require.NotNil(t, pathItem, "path %s is missing", route.Path)
+		require.NotNil(t, pathItem.GetOperation(route.Method), "operation %s %s is missing", route.Method, route.Path)
+	}
+
+	for _, path := range []string{"/employees:export", "/employees:import"} {
+		require.NotNil(t, doc.Paths.Value(path), "path %s is missing", path)
+	}
+
+	employee := doc.Components.Schemas["Employee"]
+	require.NotNil(t, employee, "employee schema is missing")
+	require.Equal(t, []string{"first_name", "last_name", "dob", "email"}, employee.Value.Required)
+	require.True(t, employee.Value.Properties["manager_id"].Value.Nullable)
+}
+
+// Test that requests not matching the OpenAPI document are rejected
+func TestRequestValidator(t *testing.T) {
+	testCases := []struct {
+		name        string
+		method      string
+		path        string
+		contentType string
+		body        string
+		httpStatus  int
+		detail      string
+		errors      []ValidationErrorDtl
+	}{
+		{
+			name:       "Successful - Get Employees",
+			method:     "GET",
+			path:       "/employees?include_deleted=true",
+			httpStatus: http.StatusOK,
+		},
+		{
+			name:       "Failed - Get Employees - invalid boolean",
+			method:     "GET",
+			path:       "/employees?include_deleted=maybe",
+			httpStatus: http.StatusBadRequest,
+			detail:     ErrorInvalidQueryParam,
+			errors:     []ValidationErrorDtl{{Field: "include_deleted", Message: "value maybe: an invalid boolean: invalid syntax"}},
+		},
+		{
+			name:       "Failed - Search Employees - limit out of range",
+			method:     "GET",
+			path:       "/employees/search?q=john&limit=1000",
+			httpStatus: http.StatusBadRequest,
+			detail:     ErrorInvalidQueryParam,
+			errors:     []ValidationErrorDtl{{Field: "limit", Message: "number must be at most 100"}},
+		},
+		{
+			name:       "Failed - Export Employees - unknown format",
+			method:     "GET",
+			path:       "/employees:export?format=xml",
+			httpStatus: http.StatusBadRequest,
+			detail:     ErrorInvalidQueryParam,
+			errors:     []ValidationErrorDtl{{Field: "format", Message: `value is not one of the allowed values ["json","csv"]`}},
+		},
+		{
+			name:        "Failed - Create Employee - name too long",
+			method:      "POST",
+			path:        "/employees",
+			contentType: "application/json",
+			body:        `{"first_name": "` + strings.Repeat("a", 101) + `", "last_name": "Smith", "dob": "1990-01-01", "email": "john.smith@example.com"}`,
+			httpStatus:  http.StatusBadRequest,
+			detail:      ErrorInvalidBody,
+			errors:      []ValidationErrorDtl{{Field: "first_name", Message: "maximum string length is 100"}},
+		},
+		{
+			name:        "Failed - Create Webhook - wrong type in array",
+			method:      "POST",
+			path:        "/webhooks",
+			contentType: "application/json",
+			body:        `{"url": "https://example.com/hook", "events": [1]}`,
+			httpStatus:  http.StatusBadRequest,
+			detail:      ErrorInvalidBody,
+			errors:      []ValidationErrorDtl{{Field: "events.0", Message: "value must be a string"}},
+		},
+		{
+			name:        "Failed - Import Employees - unsupported content type is left to the handler",
+			method:      "POST",
+			path:        "/employees:import",
+			contentType: "application/xml",
+			body:        `<employees/>`,
+			httpStatus:  http.StatusUnsupportedMediaType,
+			detail:      ErrorUnsupportedContentType,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, _ := mockRepo("single")
+
+			svc := services.NewService(logger, false)
+			svc.EmpRepo = repo
+
+			h := NewHandler(logger, svc)
+
+			// Create a request to pass to our handler
+			req, err := http.NewRequest(tc.method, tc.path, bytes.NewBufferString(tc.body))
+			require.NoError(t, err, "failed to create request")
+
+			if tc.contentType != "" {
+				req.Header.Set("Content-Type", tc.contentType)
+			}
+
+			rr := httptest.NewRecorder()
+
+			_, r := gin.CreateTestContext(rr)
+
+			// Set up routes
+			h.SetupRoutes(r)
+
+			// Call the handler
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.httpStatus, rr.Code)
+
+			if tc.httpStatus < http.StatusBadRequest {
+				return
+			}
+
+			problem := Problem{}
+			err = json.Unmarshal(rr.Body.Bytes(), &problem)
+			require.NoError(t, err, "failed to unmarshal response body")
+
+			require.Equal(t, tc.detail, problem.Detail)
+			require.Equal(t, tc.errors, problem.Errors)
+		})
+	}
+}
+
+// Test that the Swagger UI page is served
+func TestSwaggerUI(t *testing.T) {
+	repo, _ := mockRepo("single")
+
+	svc := services.NewService(logger, false)
+	svc.EmpRepo = repo
+
+	h := NewHandler(logger, svc)
+
+	req, err := http.NewRequest("GET", "/docs", nil)
+	require.NoError(t, err, "failed to create request")
+
+	rr := httptest.NewRecorder()
+
+	_, r := gin.CreateTestContext(rr)
+
+	// Set up routes
+	h.SetupRoutes(r)
+
+	// Call the handler
+	r.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Contains(t, rr.Header().Get("Content-Type"), gin.MIMEHTML)
+	require.Contains(t, rr.Body.String(), `url: "/openapi.json"`)
+}
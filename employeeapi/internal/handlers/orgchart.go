@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"net/http"
+
+	"employeeapi/internal/repos"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Reports struct {
+	Direct     []Employee `json:"direct"`
+	Transitive []Employee `json:"transitive"`
+}
+
+type OrgNode struct {
+	Employee
+	Reports []OrgNode `json:"reports"`
+}
+
+// GetReports returns the employees reporting to an employee, directly and at any level below
+func (h Handler) GetReports(c *gin.Context) {
+	l := h.requestLogger(c).With().Str("package", packageName).Str("func", "GetReports").Logger()
+
+	id := c.Param("id")
+	if id == "" {
+		l.Error().Msg("id is empty")
+		h.abortWithError(c, &RequestError{Detail: ErrorIDRequired})
+		return
+	}
+
+	fields, err := projectionFields(c)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to parse query parameters")
+		h.abortWithError(c, err)
+		return
+	}
+
+	direct, transitive, err := h.svc.EmpRepo.GetReports(h.repoContext(c), id)
+	if err != nil {
+		l.Error().Err(err).Str("id", id).Msg("failed to get reports")
+
+		h.abortWithError(c, err, ErrorEmpNotFound)
+		return
+	}
+
+	h.projectedJSON(c, http.StatusOK, Reports{
+		Direct:     newEmployees(direct),
+		Transitive: newEmployees(transitive),
+	}, fields)
+}
+
+// GetManagementChain returns the managers of an employee, from the direct manager to the top
+func (h Handler) GetManagementChain(c *gin.Context) {
+	l := h.requestLogger(c).With().Str("package", packageName).Str("func", "GetManagementChain").Logger()
+
+	id := c.Param("id")
+	if id == "" {
+		l.Error().Msg("id is empty")
+		h.abortWithError(c, &RequestError{Detail: ErrorIDRequired})
+		return
+	}
+
+	fields, err := projectionFields(c)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to parse query parameters")
+		h.abortWithError(c, err)
+		return
+	}
+
+	chain, err := h.svc.EmpRepo.GetManagementChain(h.repoContext(c), id)
+	if err != nil {
+		l.Error().Err(err).Str("id", id).Msg("failed to get management chain")
+
+		h.abortWithError(c, err, ErrorEmpNotFound)
+		return
+	}
+
+	h.projectedJSON(c, http.StatusOK, newEmployees(chain), fields)
+}
+
+// GetOrgChart returns the whole organisation as nested reporting lines
+func (h Handler) GetOrgChart(c *gin.Context) {
+	l := h.requestLogger(c).With().Str("package", packageName).Str("func", "GetOrgChart").Logger()
+
+	fields, err := projectionFields(c)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to parse query parameters")
+		h.abortWithError(c, err)
+		return
+	}
+
+	chart, err := h.svc.EmpRepo.GetOrgChart(h.repoContext(c))
+	if err != nil {
+		l.Error().Err(err).Msg("failed to get org chart")
+		h.abortWithError(c, err)
+		return
+	}
+
+	h.projectedJSON(c, http.StatusOK, newOrgNodes(chart), fields)
+}
+
+func newOrgNodes(nodes []*repos.OrgNode) []OrgNode {
+	resp := make([]OrgNode, 0)
+	for _, node := range nodes {
+		resp = append(resp, OrgNode{
+			Employee: newEmployee(node.Employee),
+			Reports:  newOrgNodes(node.Reports),
+		})
+	}
+
+	return resp
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"employeeapi/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// Test the org chart handlers with employee 2 reporting to employee 1
+func TestOrgChart(t *testing.T) {
+	testCases := []struct {
+		name       string
+		path       string
+		httpStatus int
+		check      func(t *testing.T, body []byte)
+	}{
+		{
+			name:       "Successful - Get Reports",
+			path:       "/employees/" + employeeId1 + "/reports",
+			httpStatus: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				reports := Reports{}
+				require.NoError(t, json.Unmarshal(body, &reports))
+
+				require.Len(t, reports.Direct, 1)
+				require.Len(t, reports.Transitive, 1)
+				require.Equal(t, employeeId2, reports.Direct[0].ID)
+			},
+		},
+		{
+			name:       "Failed - Get Reports - not found",
+			path:       "/employees/" + uuid.New().String() + "/reports",
+			httpStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Successful - Get Management Chain",
+			path:       "/employees/" + employeeId2 + "/chain",
+			httpStatus: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				chain := []Employee{}
+				require.NoError(t, json.Unmarshal(body, &chain))
+
+				require.Len(t, chain, 1)
+				require.Equal(t, employeeId1, chain[0].ID)
+			},
+		},
+		{
+			name:       "Successful - Get Org Chart",
+			path:       "/orgchart",
+			httpStatus: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				chart := []OrgNode{}
+				require.NoError(t, json.Unmarshal(body, &chart))
+
+				require.Len(t, chart, 1)
+				require.Equal(t, employeeId1, chart[0].ID)
+				require.Len(t, chart[0].Reports, 1)
+				require.Equal(t, employeeId2, chart[0].Reports[0].ID)
+				require.Equal(t, employeeId1, *chart[0].Reports[0].ManagerID)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, data := mockRepo("mult")
+
+			(*data)[employeeId2].ManagerID = employeeId1
+
+			svc := services.NewService(logger, false)
+			svc.EmpRepo = repo
+
+			h := NewHandler(logger, svc)
+
+			// Create a request to pass to our handler
+			req, err := http.NewRequest("GET", tc.path, nil)
+			require.NoError(t, err, "failed to create request")
+
+			rr := httptest.NewRecorder()
+
+			_, r := gin.CreateTestContext(rr)
+
+			// Set up routes
+			h.SetupRoutes(r)
+
+			// Call the handler
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.httpStatus, rr.Code)
+
+			if tc.check != nil {
+				tc.check(t, rr.Body.Bytes())
+			}
+		})
+	}
+}
+
+// Test manager assignment through UpdateEmployee
+func TestUpdateEmployeeManager(t *testing.T) {
+	noManager := ""
+	unknownManager := uuid.New().String()
+	cycleManager := employeeId2
+	manager := employeeId1
+
+	testCases := []struct {
+		name       string
+		id         string
+		managerID  *string
+		httpStatus int
+		expected   string
+	}{
+		{
+			name:       "Successful - Update Employee - manager not passed",
+			id:         employeeId2,
+			httpStatus: http.StatusOK,
+			expected:   employeeId1,
+		},
+		{
+			name:       "Successful - Update Employee - manager removed",
+			id:         employeeId2,
+			managerID:  &noManager,
+			httpStatus: http.StatusOK,
+			expected:   "",
+		},
+		{
+			name:       "Successful - Update Employee - manager reassigned",
+			id:         employeeId2,
+			managerID:  &manager,
+			httpStatus: http.StatusOK,
+			expected:   employeeId1,
+		},
+		{
+			name:       "Failed - Update Employee - manager not found",
+			id:         employeeId2,
+			managerID:  &unknownManager,
+			httpStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Failed - Update Employee - manager cycle",
+			id:         employeeId1,
+			managerID:  &cycleManager,
+			httpStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, data := mockRepo("mult")
+
+			(*data)[employeeId2].ManagerID = employeeId1
+
+			svc := services.NewService(logger, false)
+			svc.EmpRepo = repo
+
+			h := NewHandler(logger, svc)
+
+			emp := (*data)[tc.id]
+			body, err := json.Marshal(Employee{
+				FirstName:   emp.FirstName,
+				LastName:    emp.LastName,
+				DateOfBirth: emp.DateOfBirth,
+				Email:       emp.Email,
+				ManagerID:   tc.managerID,
+			})
+			require.NoError(t, err, "failed to marshal JSON")
+
+			// Create a request to pass to our handler
+			req, err := http.NewRequest("PUT", "/employees/"+tc.id, bytes.NewBuffer(body))
+			require.NoError(t, err, "failed to create request")
+
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+
+			_, r := gin.CreateTestContext(rr)
+
+			// Set up routes
+			h.SetupRoutes(r)
+
+			// Call the handler
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.httpStatus, rr.Code)
+
+			if rr.Code == http.StatusOK {
+				require.Equal(t, tc.expected, (*data)[tc.id].ManagerID)
+			}
+		})
+	}
+}
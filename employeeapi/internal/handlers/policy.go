@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"employeeapi/internal/services"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// The validations of the policy are registered as aliases of tags whose parameters are the
+// limits of the policy, so the error of a field tells which limit it broke
+const (
+	tagMinAge      = "min_age"
+	tagMaxAge      = "max_age"
+	tagEmailDomain = "email_domain"
+	tagNameChars   = "name_chars"
+)
+
+// Commas and pipes separate the tags of the validator so they're escaped in parameters
+var tagParamReplacer = strings.NewReplacer(",", "0x2C", "|", "0x7C")
+
+// Registers the validations of the policy and the age, company_email and name aliases enforcing it
+func registerPolicy(v *validator.Validate, policy services.Policy) {
+	v.RegisterValidation(tagMinAge, minAge)
+	v.RegisterValidation(tagMaxAge, maxAge)
+	v.RegisterValidation(tagEmailDomain, emailDomain)
+	v.RegisterValidation(tagNameChars, nameCharacters)
+
+	v.RegisterAlias("age", fmt.Sprintf("%s=%d,%s=%d", tagMinAge, policy.MinAge, tagMaxAge, policy.MaxAge))
+	v.RegisterAlias("company_email", tagEmailDomain+"="+strings.Join(policy.EmailDomains, " "))
+	v.RegisterAlias("name", tagNameChars+"="+tagParamReplacer.Replace(policy.NameCharacters))
+}
+
+// Returns the messages of the validations whose default message only names the tag
+func validationMessage(e validator.FieldError) string {
+	switch e.ActualTag() {
+	case "dob", "hire_date":
+		return "must be a date formatted as YYYY-MM-DD and not in the future"
+	case tagMinAge:
+		return fmt.Sprintf("must be at least %s years old", e.Param())
+	case tagMaxAge:
+		return fmt.Sprintf("must be at most %s years old", e.Param())
+	case tagEmailDomain:
+		return "must be an email address at " + strings.Join(strings.Fields(e.Param()), " or ")
+	case tagNameChars:
+		if e.Param() == "" {
+			return "may only contain letters"
+		}
+		return fmt.Sprintf("may only contain letters and the characters %q", e.Param())
+	default:
+		return e.Error()
+	}
+}
+
+// Custom validation for the minimum age given by the parameter; 0 allows any age
+func minAge(fl validator.FieldLevel) bool {
+	limit, err := strconv.Atoi(fl.Param())
+	if err != nil {
+		return false
+	}
+
+	age, ok := fieldAge(fl)
+	return ok && age >= limit
+}
+
+// Custom validation for the maximum age given by the parameter; 0 allows any age
+func maxAge(fl validator.FieldLevel) bool {
+	limit, err := strconv.Atoi(fl.Param())
+	if err != nil {
+		return false
+	}
+
+	age, ok := fieldAge(fl)
+	return ok && (limit == 0 || age <= limit)
+}
+
+// Returns the age today of the date of birth of a field, false if it's not a valid date
+func fieldAge(fl validator.FieldLevel) (int, bool) {
+	dob, err := time.Parse(time.DateOnly, fl.Field().String())
+	if err != nil {
+		return 0, false
+	}
+
+	return yearsBetween(dob, time.Now().UTC()), true
+}
+
+// Custom validation for the domain of an email, one of the space separated domains of the
+// parameter; any domain is allowed when there are none
+func emailDomain(fl validator.FieldLevel) bool {
+	domains := strings.Fields(fl.Param())
+	if len(domains) == 0 {
+		return true
+	}
+
+	_, domain, ok := strings.Cut(fl.Field().String(), "@")
+	if !ok {
+		return false
+	}
+
+	for _, allowed := range domains {
+		if strings.EqualFold(domain, allowed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Custom validation for the characters of a name: letters, including their accents, and the
+// characters of the parameter
+func nameCharacters(fl validator.FieldLevel) bool {
+	allowed := fl.Param()
+
+	for _, r := range fl.Field().String() {
+		if !unicode.IsLetter(r) && !unicode.Is(unicode.Mn, r) && !strings.ContainsRune(allowed, r) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"employeeapi/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// Test that creating an employee enforces the validation policy
+func TestCreateEmployeePolicy(t *testing.T) {
+	policy := services.Policy{
+		MinAge:         18,
+		MaxAge:         70,
+		EmailDomains:   []string{"example.com", "example.org"},
+		NameCharacters: " -',",
+	}
+
+	// Dates of birth relative to today so the ages don't change over time
+	now := time.Now().UTC()
+	dob := func(years, days int) string {
+		return now.AddDate(-years, 0, days).Format(time.DateOnly)
+	}
+
+	valid := Employee{
+		FirstName:   "Anne-Marie",
+		LastName:    "O'Brien",
+		DateOfBirth: "1985-05-15",
+		Email:       "anne.obrien@Example.org",
+	}
+
+	testCases := []struct {
+		name       string
+		update     func(emp *Employee)
+		httpStatus int
+		errors     []ValidationErrorDtl
+	}{
+		{
+			name:       "Successful - Create Employee - policy",
+			update:     func(emp *Employee) {},
+			httpStatus: http.StatusCreated,
+		},
+		{
+			name: "Successful - Create Employee - policy - accents and allowed characters",
+			update: func(emp *Employee) {
+				emp.FirstName = "José, Jr"
+				emp.LastName = "Müller"
+			},
+			httpStatus: http.StatusCreated,
+		},
+		{
+			name:       "Successful - Create Employee - policy - minimum age today",
+			update:     func(emp *Employee) { emp.DateOfBirth = dob(18, 0) },
+			httpStatus: http.StatusCreated,
+		},
+		{
+			name:       "Failed - Create Employee - policy - too young",
+			update:     func(emp *Employee) { emp.DateOfBirth = dob(18, 1) },
+			httpStatus: http.StatusBadRequest,
+			errors:     []ValidationErrorDtl{{Field: "DateOfBirth", Message: "must be at least 18 years old"}},
+		},
+		{
+			name:       "Failed - Create Employee - policy - too old",
+			update:     func(emp *Employee) { emp.DateOfBirth = dob(71, 0) },
+			httpStatus: http.StatusBadRequest,
+			errors:     []ValidationErrorDtl{{Field: "DateOfBirth", Message: "must be at most 70 years old"}},
+		},
+		{
+			name:       "Failed - Create Employee - policy - future date of birth",
+			update:     func(emp *Employee) { emp.DateOfBirth = dob(0, 1) },
+			httpStatus: http.StatusBadRequest,
+			errors: []ValidationErrorDtl{
+				{Field: "DateOfBirth", Message: "must be a date formatted as YYYY-MM-DD and not in the future"},
+			},
+		},
+		{
+			name:       "Failed - Create Employee - policy - email domain",
+			update:     func(emp *Employee) { emp.Email = "anne@example.net" },
+			httpStatus: http.StatusBadRequest,
+			errors: []ValidationErrorDtl{
+				{Field: "Email", Message: "must be an email address at example.com or example.org"},
+			},
+		},
+		{
+			name: "Failed - Create Employee - policy - name characters",
+			update: func(emp *Employee) {
+				emp.FirstName = "Anne2"
+				emp.LastName = "O_Brien"
+			},
+			httpStatus: http.StatusBadRequest,
+			errors: []ValidationErrorDtl{
+				{Field: "FirstName", Message: `may only contain letters and the characters " -',"`},
+				{Field: "LastName", Message: `may only contain letters and the characters " -',"`},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, _ := mockRepo("single")
+
+			svc := services.NewService(logger, false)
+			svc.EmpRepo = repo
+			svc.Policy = policy
+
+			h := NewHandler(logger, svc)
+
+			_, r := gin.CreateTestContext(httptest.NewRecorder())
+
+			// Set up routes
+			h.SetupRoutes(r)
+
+			emp := valid
+			tc.update(&emp)
+
+			body, err := json.Marshal(emp)
+			require.NoError(t, err)
+
+			req, err := http.NewRequest("POST", "/employees", bytes.NewReader(body))
+			require.NoError(t, err, "failed to create request")
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.httpStatus, rr.Code)
+
+			if tc.errors == nil {
+				return
+			}
+
+			var problem Problem
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &problem))
+			require.Equal(t, tc.errors, problem.Errors)
+		})
+	}
+}
+
+// Test that the default policy keeps the current employees valid while rejecting impossible ages
+func TestDefaultPolicy(t *testing.T) {
+	svc := services.NewService(logger, false)
+	v := NewValidator(svc)
+
+	emp := Employee{FirstName: "Mary Jane", LastName: "Watson-Parker", DateOfBirth: "1985-05-15", Email: "mj@example.com"}
+	require.NoError(t, v.Struct(emp))
+
+	emp.DateOfBirth = "1824-05-15"
+	require.Error(t, v.Struct(emp))
+}
@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// QueryFields selects the fields of the employees in a response, e.g. fields=id,first_name,last_name
+	// Callers that mustn't see PII leave out PIIFields. Every route sending employees supports it;
+	// the headcount report applies it to its upcoming events
+	QueryFields = "fields"
+
+	ErrorUnknownField = "unknown field"
+)
+
+// PIIFields are the fields of an employee that are personal data
+var PIIFields = []string{"dob", "email"}
+
+// Fields of an employee that can be selected, by JSON name
+var employeeFields = jsonFieldNames(reflect.TypeOf(Employee{}))
+
+// Returns the JSON names of the fields of a struct
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+
+	return names
+}
+
+// Parses the fields query parameter
+// Returns nil if it's not given, i.e. every field is sent
+func projectionFields(c *gin.Context) ([]string, error) {
+	return ParseFields(c.Query(QueryFields))
+}
+
+// ParseFields parses a comma separated list of employee fields like the fields query parameter,
+// e.g. the fields metadata of the gRPC API
+// Returns nil if it's empty, i.e. every field is sent
+func ParseFields(list string) ([]string, error) {
+	if list == "" {
+		return nil, nil
+	}
+
+	fields := make([]string, 0)
+	errDtls := make([]ValidationErrorDtl, 0)
+
+	for _, field := range strings.Split(list, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		if !employeeFields[field] {
+			errDtls = append(errDtls, ValidationErrorDtl{
+				Field:   QueryFields,
+				Message: fmt.Sprintf("%s %q", ErrorUnknownField, field),
+			})
+			continue
+		}
+
+		fields = append(fields, field)
+	}
+
+	if len(errDtls) > 0 {
+		return nil, &RequestError{Detail: ErrorInvalidQueryParam, Errors: errDtls}
+	}
+
+	return fields, nil
+}
+
+// Sends the value as the JSON response with only the given fields of the employees in it
+func (h Handler) projectedJSON(c *gin.Context, status int, value any, fields []string) {
+	resp, err := project(value, fields)
+	if err != nil {
+		l := h.requestLogger(c).With().Str("package", packageName).Str("func", "projectedJSON").Logger()
+		l.Error().Err(err).Msg("failed to project response")
+		h.abortWithError(c, err)
+		return
+	}
+
+	c.JSON(status, resp)
+}
+
+// Returns whether a field of the employees is selected by the fields query parameter
+func selected(fields []string, field string) bool {
+	if fields == nil {
+		return true
+	}
+
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Types whose JSON objects hold fields of an employee besides Employee and the types embedding it
+var employeeNodeTypes = map[reflect.Type]bool{
+	reflect.TypeOf(DuplicateGroup{}): true,
+	reflect.TypeOf(Period{}):         true,
+}
+
+// Returns the response value with only the given fields of the employees in it
+// Only the objects of the employees are filtered, e.g. the employees of a search result or an
+// org chart node, the name and date of birth of a duplicate group and the periods of a timeline;
+// the objects wrapping them keep every field, e.g. the id of a batch operation result
+// The value is returned as it is if fields is nil
+func project(value any, fields []string) (any, error) {
+	if fields == nil {
+		return value, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	// Numbers are kept as they are rather than turned into floats
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var tree any
+	if err := decoder.Decode(&tree); err != nil {
+		return nil, err
+	}
+
+	keep := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		keep[field] = true
+	}
+
+	return filterEmployeeFields(reflect.TypeOf(value), tree, keep), nil
+}
+
+// Removes the employee fields that aren't kept from the objects of the employees of a decoded
+// JSON value, walking it along the type it was encoded from
+func filterEmployeeFields(t reflect.Type, node any, keep map[string]bool) any {
+	if t == nil {
+		return node
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return filterEmployeeFields(t.Elem(), node, keep)
+	case reflect.Slice, reflect.Array:
+		if children, ok := node.([]any); ok {
+			for i, child := range children {
+				children[i] = filterEmployeeFields(t.Elem(), child, keep)
+			}
+		}
+	case reflect.Map:
+		if children, ok := node.(map[string]any); ok {
+			for name, child := range children {
+				children[name] = filterEmployeeFields(t.Elem(), child, keep)
+			}
+		}
+	case reflect.Struct:
+		if object, ok := node.(map[string]any); ok {
+			filterStructFields(t, object, keep)
+		}
+	}
+
+	return node
+}
+
+// Filters the object a struct was encoded to; the fields of embedded structs are in the same object
+func filterStructFields(t reflect.Type, object map[string]any, keep map[string]bool) {
+	if isEmployeeNode(t) {
+		for name := range object {
+			if employeeFields[name] && !keep[name] {
+				delete(object, name)
+			}
+		}
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			filterStructFields(field.Type, object, keep)
+			continue
+		}
+
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		if child, ok := object[name]; ok {
+			object[name] = filterEmployeeFields(field.Type, child, keep)
+		}
+	}
+}
+
+// Reports whether the objects of a struct type hold the fields of an employee
+func isEmployeeNode(t reflect.Type) bool {
+	if t == reflect.TypeOf(Employee{}) || employeeNodeTypes[t] {
+		return true
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.Anonymous && field.Type == reflect.TypeOf(Employee{}) {
+			return true
+		}
+	}
+
+	return false
+}
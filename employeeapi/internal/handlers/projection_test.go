@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"employeeapi/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// Test the fields projection of the routes sending employees
+func TestProjection(t *testing.T) {
+	testCases := []struct {
+		name       string
+		path       string
+		httpStatus int
+		body       string
+		// csv compares the body as it is rather than as JSON
+		csv bool
+	}{
+		{
+			name:       "Successful - Projection - get employee",
+			path:       "/employees/" + employeeId1 + "?fields=id,first_name,last_name",
+			httpStatus: http.StatusOK,
+			body:       `{"id":"` + employeeId1 + `","first_name":"John","last_name":"Doe"}`,
+		},
+		{
+			name:       "Successful - Projection - list employees",
+			path:       "/employees?fields=id,%20department",
+			httpStatus: http.StatusOK,
+			body:       `[{"id":"` + employeeId1 + `","department":"Engineering"},{"id":"` + employeeId2 + `","department":"Marketing"}]`,
+		},
+		{
+			name:       "Successful - Projection - without PII",
+			path:       "/employees/" + employeeId1 + "?fields=id,first_name,last_name,is_active,department,role,manager_id",
+			httpStatus: http.StatusOK,
+			body:       `{"id":"` + employeeId1 + `","first_name":"John","last_name":"Doe","is_active":false,"department":"Engineering","role":"Software Developer","manager_id":null}`,
+		},
+		{
+			name:       "Successful - Projection - search results keep their score",
+			path:       "/employees/search?q=smith&fields=id",
+			httpStatus: http.StatusOK,
+			body:       `[{"id":"` + employeeId2 + `","score":3}]`,
+		},
+		{
+			name:       "Successful - Projection - org chart nodes keep their reports",
+			path:       "/orgchart?fields=id",
+			httpStatus: http.StatusOK,
+			body:       `[{"id":"` + employeeId1 + `","reports":[]},{"id":"` + employeeId2 + `","reports":[]}]`,
+		},
+		{
+			name:       "Successful - Projection - timeline",
+			path:       "/employees/" + employeeId1 + "/timeline?fields=department",
+			httpStatus: http.StatusOK,
+			body:       `[{"department":"Engineering","from":null,"to":null}]`,
+		},
+		{
+			name:       "Successful - Projection - JSON export",
+			path:       "/employees:export?fields=first_name",
+			httpStatus: http.StatusOK,
+			body:       `[{"first_name":"John"},{"first_name":"Jane"}]`,
+		},
+		{
+			name:       "Successful - Projection - CSV export",
+			path:       "/employees:export?format=csv&fields=first_name,id",
+			httpStatus: http.StatusOK,
+			body:       "id,first_name\n" + employeeId1 + ",John\n" + employeeId2 + ",Jane\n",
+			csv:        true,
+		},
+		{
+			name:       "Failed - Projection - unknown field",
+			path:       "/employees?fields=id,salary",
+			httpStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, _ := mockRepo("mult")
+
+			svc := services.NewService(logger, false)
+			svc.EmpRepo = repo
+
+			h := NewHandler(logger, svc)
+
+			// Create a request to pass to our handler
+			req, err := http.NewRequest("GET", tc.path, nil)
+			require.NoError(t, err, "failed to create request")
+
+			rr := httptest.NewRecorder()
+
+			_, r := gin.CreateTestContext(rr)
+
+			// Set up routes
+			h.SetupRoutes(r)
+
+			// Call the handler
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.httpStatus, rr.Code)
+
+			if tc.csv {
+				require.Equal(t, tc.body, rr.Body.String())
+				return
+			}
+
+			if tc.httpStatus == http.StatusOK {
+				require.JSONEq(t, tc.body, rr.Body.String())
+				return
+			}
+
+			problem := Problem{}
+			err = json.Unmarshal(rr.Body.Bytes(), &problem)
+			require.NoError(t, err, "failed to unmarshal response body")
+			require.Equal(t, ErrorInvalidQueryParam, problem.Detail)
+			require.Equal(t, []ValidationErrorDtl{{Field: QueryFields, Message: `unknown field "salary"`}}, problem.Errors)
+		})
+	}
+}
+
+// Test the projection leaves the objects wrapping the employees whole, e.g. batch results
+func TestProjectionBatch(t *testing.T) {
+	repo, _ := mockRepo("mult")
+
+	svc := services.NewService(logger, false)
+	svc.EmpRepo = repo
+
+	h := NewHandler(logger, svc)
+
+	body := `[{"op":"update","id":"` + employeeId1 + `","employee":{"first_name":"John","last_name":"Doe","dob":"1985-05-15","email":"johndoe@example.com"}},` +
+		`{"op":"delete","id":"` + employeeId2 + `"}]`
+
+	req, err := http.NewRequest("POST", "/employees:batch?fields=first_name", strings.NewReader(body))
+	require.NoError(t, err, "failed to create request")
+
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+
+	_, r := gin.CreateTestContext(rr)
+
+	// Set up routes
+	h.SetupRoutes(r)
+
+	// Call the handler
+	r.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `{"dry_run":false,"applied":true,"total":2,"operations":[`+
+		`{"index":0,"op":"update","status":"updated","id":"`+employeeId1+`","employee":{"first_name":"John"}},`+
+		`{"index":1,"op":"delete","status":"deleted","id":"`+employeeId2+`","employee":{"first_name":"Jane"}}]}`, rr.Body.String())
+}
@@ -0,0 +1,367 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"employeeapi/internal/repos"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// Number of days ahead the upcoming birthdays and anniversaries are looked for
+	DefaultUpcomingDays = 30
+	MaxUpcomingDays     = 366
+
+	AgeBandUnknown = "unknown"
+
+	// Sections of the rows of the CSV report
+	ReportSectionHeadcount   = "headcount"
+	ReportSectionAgeBand     = "age_band"
+	ReportSectionBirthday    = "birthday"
+	ReportSectionAnniversary = "anniversary"
+
+	ErrorInvalidUpcomingDays = "days must be between 1 and 366"
+)
+
+// Age bands of the report, the last one has no upper bound
+var ageBands = []struct {
+	name     string
+	min, max int
+}{
+	{"under 25", 0, 24},
+	{"25-34", 25, 34},
+	{"35-44", 35, 44},
+	{"45-54", 45, 54},
+	{"55-64", 55, 64},
+	{"65 and over", 65, -1},
+}
+
+// Columns of the CSV report; each row fills the columns of its section and leaves the others empty
+var headcountCSVColumns = []string{
+	"section", "department", "role", "is_active", "age_band",
+	"id", "first_name", "last_name", "date", "in_days", "years", "count",
+}
+
+type HeadcountReport struct {
+	// Day the report is computed for, today unless as_of is given
+	Date     string           `json:"date"`
+	Total    int              `json:"total"`
+	Active   int              `json:"active"`
+	Inactive int              `json:"inactive"`
+	Groups   []HeadcountGroup `json:"groups"`
+	// Ages of the active employees
+	AgeBands []AgeBand `json:"age_bands"`
+	// Birthdays and anniversaries of the active employees in the next days, soonest first
+	UpcomingBirthdays     []UpcomingEvent `json:"upcoming_birthdays"`
+	UpcomingAnniversaries []UpcomingEvent `json:"upcoming_anniversaries"`
+}
+
+type HeadcountGroup struct {
+	Department string `json:"department"`
+	Role       string `json:"role"`
+	IsActive   bool   `json:"is_active"`
+	Count      int    `json:"count"`
+}
+
+type AgeBand struct {
+	Band  string `json:"band"`
+	Count int    `json:"count"`
+}
+
+type UpcomingEvent struct {
+	// The id and names are left out when the fields query parameter doesn't select them
+	ID        string `json:"id,omitempty"`
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+	Date      string `json:"date"`
+	InDays    int    `json:"in_days"`
+	// Years of service on an anniversary; birthdays have none as the age turned and the date
+	// would give the date of birth
+	Years int `json:"years,omitempty"`
+}
+
+// GetHeadcountReport reports the number of employees by department, role and active status,
+// the age distribution and the upcoming birthdays and work anniversaries, as JSON or CSV
+// Anniversaries count from the hire date; employees without one have none
+// The fields query parameter applies to the upcoming events: the birthdays are left out unless
+// the date of birth is selected, and so are the ids and names that aren't
+func (h Handler) GetHeadcountReport(c *gin.Context) {
+	l := h.requestLogger(c).With().Str("package", packageName).Str("func", "GetHeadcountReport").Logger()
+
+	format := c.DefaultQuery("format", ExportFormatJSON)
+	if format != ExportFormatJSON && format != ExportFormatCSV {
+		l.Error().Str("format", format).Msg("unsupported report format")
+		h.abortWithError(c, &RequestError{Detail: ErrorUnsupportedFormat})
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(DefaultUpcomingDays)))
+	if err != nil || days < 1 || days > MaxUpcomingDays {
+		l.Error().Err(err).Str("days", c.Query("days")).Msg("invalid upcoming days")
+		h.abortWithError(c, &RequestError{Detail: ErrorInvalidUpcomingDays, Err: err})
+		return
+	}
+
+	opts, err := h.queryOptions(c)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to parse query parameters")
+		h.abortWithError(c, &RequestError{Detail: ErrorInvalidQueryParam, Err: err})
+		return
+	}
+
+	fields, err := projectionFields(c)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to parse query parameters")
+		h.abortWithError(c, err)
+		return
+	}
+
+	at := opts.AsOf
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	employees, err := h.svc.EmpRepo.GetEmployees(h.repoContext(c), opts)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to get employees")
+		h.abortWithError(c, err)
+		return
+	}
+
+	report := newHeadcountReport(employees, at, days)
+	projectUpcomingEvents(&report, fields)
+
+	if format == ExportFormatJSON {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="headcount-%s.csv"`, report.Date))
+
+	// The status is already sent at this point so the error can only be logged
+	if err := writeHeadcountCSV(c, report); err != nil {
+		l.Error().Err(err).Msg("failed to write report")
+	}
+}
+
+// Computes the report of the employees on the day of at, looking for the events in the next days
+func newHeadcountReport(employees []*repos.Employee, at time.Time, days int) HeadcountReport {
+	at = at.UTC()
+	today := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+
+	report := HeadcountReport{
+		Date:                  today.Format(time.DateOnly),
+		Groups:                make([]HeadcountGroup, 0),
+		AgeBands:              make([]AgeBand, 0, len(ageBands)+1),
+		UpcomingBirthdays:     make([]UpcomingEvent, 0),
+		UpcomingAnniversaries: make([]UpcomingEvent, 0),
+	}
+
+	for _, band := range ageBands {
+		report.AgeBands = append(report.AgeBands, AgeBand{Band: band.name})
+	}
+	report.AgeBands = append(report.AgeBands, AgeBand{Band: AgeBandUnknown})
+
+	groups := make(map[HeadcountGroup]int)
+
+	for _, emp := range employees {
+		report.Total++
+		groups[HeadcountGroup{Department: emp.Department, Role: emp.Role, IsActive: emp.IsActive}]++
+
+		if !emp.IsActive {
+			report.Inactive++
+			continue
+		}
+		report.Active++
+
+		dob, err := time.Parse(time.DateOnly, emp.DateOfBirth)
+		if err != nil || dob.After(today) {
+			report.AgeBands[len(report.AgeBands)-1].Count++
+		} else {
+			report.AgeBands[ageBandIndex(yearsBetween(dob, today))].Count++
+
+			if event, ok := upcomingEvent(emp, dob, today, days); ok {
+				event.Years = 0
+				report.UpcomingBirthdays = append(report.UpcomingBirthdays, event)
+			}
+		}
+
+		if hired, err := time.Parse(time.DateOnly, emp.HireDate); err == nil && !hired.After(today) {
+			if event, ok := upcomingEvent(emp, hired, today, days); ok && event.Years > 0 {
+				report.UpcomingAnniversaries = append(report.UpcomingAnniversaries, event)
+			}
+		}
+	}
+
+	for group, count := range groups {
+		group.Count = count
+		report.Groups = append(report.Groups, group)
+	}
+
+	sort.Slice(report.Groups, func(i, j int) bool {
+		a, b := report.Groups[i], report.Groups[j]
+		if a.Department != b.Department {
+			return a.Department < b.Department
+		}
+		if a.Role != b.Role {
+			return a.Role < b.Role
+		}
+		return a.IsActive && !b.IsActive
+	})
+
+	sortUpcomingEvents(report.UpcomingBirthdays)
+	sortUpcomingEvents(report.UpcomingAnniversaries)
+
+	return report
+}
+
+// Leaves out what the fields of the employees don't select from the upcoming events
+// The date of a birthday is part of the date of birth so birthdays are only sent with it
+func projectUpcomingEvents(report *HeadcountReport, fields []string) {
+	if !selected(fields, "dob") {
+		report.UpcomingBirthdays = make([]UpcomingEvent, 0)
+	}
+
+	for _, events := range [][]UpcomingEvent{report.UpcomingBirthdays, report.UpcomingAnniversaries} {
+		for i := range events {
+			if !selected(fields, "id") {
+				events[i].ID = ""
+			}
+			if !selected(fields, "first_name") {
+				events[i].FirstName = ""
+			}
+			if !selected(fields, "last_name") {
+				events[i].LastName = ""
+			}
+		}
+	}
+}
+
+// Returns the next yearly return of a date within the days following today, today included
+func upcomingEvent(emp *repos.Employee, date, today time.Time, days int) (UpcomingEvent, bool) {
+	next := sameDayIn(date, today.Year())
+	if next.Before(today) {
+		next = sameDayIn(date, today.Year()+1)
+	}
+
+	inDays := int(next.Sub(today).Hours() / 24)
+	if inDays >= days {
+		return UpcomingEvent{}, false
+	}
+
+	return UpcomingEvent{
+		ID:        emp.ID,
+		FirstName: emp.FirstName,
+		LastName:  emp.LastName,
+		Date:      next.Format(time.DateOnly),
+		InDays:    inDays,
+		Years:     next.Year() - date.Year(),
+	}, true
+}
+
+// Returns the day of a date in another year; 29 February falls on the 28th in common years
+func sameDayIn(date time.Time, year int) time.Time {
+	day := date.Day()
+	if date.Month() == time.February && day == 29 && time.Date(year, time.March, 0, 0, 0, 0, 0, time.UTC).Day() != 29 {
+		day = 28
+	}
+
+	return time.Date(year, date.Month(), day, 0, 0, 0, 0, time.UTC)
+}
+
+// Returns the number of full years from one date to another
+func yearsBetween(from, to time.Time) int {
+	years := to.Year() - from.Year()
+	if sameDayIn(from, to.Year()).After(to) {
+		years--
+	}
+
+	return years
+}
+
+func ageBandIndex(age int) int {
+	for i, band := range ageBands {
+		if age >= band.min && (band.max < 0 || age <= band.max) {
+			return i
+		}
+	}
+
+	return len(ageBands) - 1
+}
+
+func sortUpcomingEvents(events []UpcomingEvent) {
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].InDays != events[j].InDays {
+			return events[i].InDays < events[j].InDays
+		}
+		if events[i].LastName != events[j].LastName {
+			return events[i].LastName < events[j].LastName
+		}
+		return events[i].FirstName < events[j].FirstName
+	})
+}
+
+func writeHeadcountCSV(c *gin.Context, report HeadcountReport) error {
+	c.Header("Content-Type", "text/csv")
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+
+	if err := w.Write(headcountCSVColumns); err != nil {
+		return err
+	}
+
+	for _, group := range report.Groups {
+		if err := w.Write([]string{
+			ReportSectionHeadcount, csvSafe(group.Department), csvSafe(group.Role), strconv.FormatBool(group.IsActive), "",
+			"", "", "", "", "", "", strconv.Itoa(group.Count),
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, band := range report.AgeBands {
+		if err := w.Write([]string{
+			ReportSectionAgeBand, "", "", "", band.Band,
+			"", "", "", "", "", "", strconv.Itoa(band.Count),
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := writeUpcomingCSV(w, ReportSectionBirthday, report.UpcomingBirthdays); err != nil {
+		return err
+	}
+
+	if err := writeUpcomingCSV(w, ReportSectionAnniversary, report.UpcomingAnniversaries); err != nil {
+		return err
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+func writeUpcomingCSV(w *csv.Writer, section string, events []UpcomingEvent) error {
+	for _, event := range events {
+		// Birthdays have no years
+		years := ""
+		if event.Years > 0 {
+			years = strconv.Itoa(event.Years)
+		}
+
+		if err := w.Write([]string{
+			section, "", "", "", "",
+			event.ID, csvSafe(event.FirstName), csvSafe(event.LastName), event.Date, strconv.Itoa(event.InDays), years, "",
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"employeeapi/internal/repos"
+	"employeeapi/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// Test GetHeadcountReport handler
+func TestGetHeadcountReport(t *testing.T) {
+	testCases := []struct {
+		name       string
+		path       string
+		httpStatus int
+		birthdays  int
+	}{
+		{
+			name:       "Successful - Get Headcount Report",
+			path:       "/reports/headcount?as_of=2024-09-10",
+			httpStatus: http.StatusOK,
+			birthdays:  1,
+		},
+		{
+			name:       "Successful - Get Headcount Report - birthday not within days",
+			path:       "/reports/headcount?as_of=2024-09-10&days=7",
+			httpStatus: http.StatusOK,
+		},
+		{
+			name:       "Successful - Get Headcount Report - csv",
+			path:       "/reports/headcount?as_of=2024-09-10&format=csv",
+			httpStatus: http.StatusOK,
+			birthdays:  1,
+		},
+		{
+			name:       "Successful - Get Headcount Report - birthdays left out without dob",
+			path:       "/reports/headcount?as_of=2024-09-10&fields=id,first_name,last_name",
+			httpStatus: http.StatusOK,
+		},
+		{
+			name:       "Successful - Get Headcount Report - birthdays with dob",
+			path:       "/reports/headcount?as_of=2024-09-10&fields=id,dob",
+			httpStatus: http.StatusOK,
+			birthdays:  1,
+		},
+		{
+			name:       "Failed - Get Headcount Report - unknown field",
+			path:       "/reports/headcount?fields=salary",
+			httpStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Failed - Get Headcount Report - invalid days",
+			path:       "/reports/headcount?days=0",
+			httpStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Failed - Get Headcount Report - unsupported format",
+			path:       "/reports/headcount?format=xml",
+			httpStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, _ := mockRepo("mult")
+
+			svc := services.NewService(logger, false)
+			svc.EmpRepo = repo
+
+			h := NewHandler(logger, svc)
+
+			req, err := http.NewRequest("GET", tc.path, nil)
+			require.NoError(t, err, "failed to create request")
+
+			rr := httptest.NewRecorder()
+
+			_, r := gin.CreateTestContext(rr)
+
+			// Set up routes
+			h.SetupRoutes(r)
+
+			// Call the handler
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.httpStatus, rr.Code)
+
+			if rr.Code != http.StatusOK {
+				return
+			}
+
+			if rr.Header().Get("Content-Type") == "text/csv" {
+				require.Equal(t, `attachment; filename="headcount-2024-09-10.csv"`, rr.Header().Get("Content-Disposition"))
+
+				rows, err := csv.NewReader(rr.Body).ReadAll()
+				require.NoError(t, err, "failed to read csv")
+				require.Equal(t, headcountCSVColumns, rows[0])
+				// Two groups, the age bands and the birthday
+				require.Len(t, rows, 1+2+len(ageBands)+1+tc.birthdays)
+				require.Equal(t, []string{ReportSectionHeadcount, "Engineering", "Software Developer", "false", "", "", "", "", "", "", "", "1"}, rows[1])
+				require.Equal(t, []string{ReportSectionBirthday, "", "", "", "", employeeId2, "Jane", "Smith", "2024-09-22", "12", "", ""}, rows[len(rows)-1])
+				return
+			}
+
+			report := HeadcountReport{}
+			err = json.Unmarshal(rr.Body.Bytes(), &report)
+			require.NoError(t, err, "failed to unmarshal response body")
+
+			require.Equal(t, "2024-09-10", report.Date)
+			require.Equal(t, 2, report.Total)
+			require.Equal(t, 1, report.Active)
+			require.Equal(t, 1, report.Inactive)
+			require.Equal(t, []HeadcountGroup{
+				{Department: "Engineering", Role: "Software Developer", IsActive: false, Count: 1},
+				{Department: "Marketing", Role: "Marketing Specialist", IsActive: true, Count: 1},
+			}, report.Groups)
+			require.Equal(t, AgeBand{Band: "25-34", Count: 1}, report.AgeBands[1])
+			require.Len(t, report.UpcomingBirthdays, tc.birthdays)
+			require.Empty(t, report.UpcomingAnniversaries)
+		})
+	}
+}
+
+// Test the age bands and the upcoming events of the report
+func TestNewHeadcountReport(t *testing.T) {
+	employees := []*repos.Employee{
+		{ID: "1", FirstName: "Leap", LastName: "Day", DateOfBirth: "2000-02-29", IsActive: true},
+		{ID: "2", FirstName: "Old", LastName: "Timer", DateOfBirth: "1950-03-01", IsActive: true, HireDate: "2020-03-02"},
+		// Hired today, no anniversary yet
+		{ID: "3", FirstName: "No", LastName: "Birthday", IsActive: true, HireDate: "2023-02-27"},
+		{ID: "4", FirstName: "Gone", LastName: "Away", DateOfBirth: "1990-02-28", IsActive: false, HireDate: "2010-03-01"},
+	}
+
+	report := newHeadcountReport(employees, time.Date(2023, 2, 27, 12, 0, 0, 0, time.UTC), 7)
+
+	require.Equal(t, 4, report.Total)
+	require.Equal(t, 3, report.Active)
+
+	bands := make(map[string]int)
+	for _, band := range report.AgeBands {
+		bands[band.Band] = band.Count
+	}
+	require.Equal(t, map[string]int{
+		"under 25": 1, "25-34": 0, "35-44": 0, "45-54": 0, "55-64": 0, "65 and over": 1, AgeBandUnknown: 1,
+	}, bands)
+
+	// 29 February is celebrated on the 28th in common years, inactive employees are left out
+	require.Equal(t, []UpcomingEvent{
+		{ID: "1", FirstName: "Leap", LastName: "Day", Date: "2023-02-28", InDays: 1},
+		{ID: "2", FirstName: "Old", LastName: "Timer", Date: "2023-03-01", InDays: 2},
+	}, report.UpcomingBirthdays)
+
+	require.Equal(t, []UpcomingEvent{
+		{ID: "2", FirstName: "Old", LastName: "Timer", Date: "2023-03-02", InDays: 3, Years: 3},
+	}, report.UpcomingAnniversaries)
+}
+
+// Test the date of birth can't be rebuilt from the report: birthdays have a date but no age
+func TestHeadcountReportHidesDateOfBirth(t *testing.T) {
+	for _, format := range []string{ExportFormatJSON, ExportFormatCSV} {
+		t.Run(format, func(t *testing.T) {
+			repo, _ := mockRepo("mult")
+
+			svc := services.NewService(logger, false)
+			svc.EmpRepo = repo
+
+			h := NewHandler(logger, svc)
+
+			req, err := http.NewRequest("GET", "/reports/headcount?as_of=2024-09-10&format="+format, nil)
+			require.NoError(t, err, "failed to create request")
+
+			rr := httptest.NewRecorder()
+
+			_, r := gin.CreateTestContext(rr)
+			h.SetupRoutes(r)
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, http.StatusOK, rr.Code)
+
+			// Jane Smith was born on 1990-09-22 and turns 34 on the birthday of the report
+			require.NotContains(t, rr.Body.String(), "1990")
+
+			if format == ExportFormatCSV {
+				rows, err := csv.NewReader(rr.Body).ReadAll()
+				require.NoError(t, err, "failed to read csv")
+
+				birthday := rows[len(rows)-1]
+				require.Equal(t, ReportSectionBirthday, birthday[0])
+				require.Equal(t, "2024-09-22", birthday[8])
+				require.Empty(t, birthday[10], "the age turned is sent")
+				return
+			}
+
+			var report struct {
+				UpcomingBirthdays []map[string]any `json:"upcoming_birthdays"`
+			}
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &report))
+			require.Len(t, report.UpcomingBirthdays, 1)
+			require.Equal(t, "2024-09-22", report.UpcomingBirthdays[0]["date"])
+			require.NotContains(t, report.UpcomingBirthdays[0], "years", "the age turned is sent")
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"employeeapi/internal/repos"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+
+	ErrorQueryRequired = "q is required"
+)
+
+type SearchResult struct {
+	Employee
+	Score float64 `json:"score"`
+}
+
+// SearchEmployees finds employees by name, email, department or role
+// Partial and misspelled terms are matched; the most relevant employees come first
+// The email is only searched if it's selected by the fields query parameter
+func (h Handler) SearchEmployees(c *gin.Context) {
+	l := h.requestLogger(c).With().Str("package", packageName).Str("func", "SearchEmployees").Logger()
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		l.Error().Msg("query is empty")
+		h.abortWithError(c, &RequestError{Detail: ErrorQueryRequired})
+		return
+	}
+
+	limit := DefaultSearchLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > MaxSearchLimit {
+			l.Error().Err(err).Str("limit", value).Msg("invalid limit")
+			h.abortWithError(c, &RequestError{Detail: ErrorInvalidQueryParam, Err: err})
+			return
+		}
+		limit = parsed
+	}
+
+	fields, err := projectionFields(c)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to parse query parameters")
+		h.abortWithError(c, err)
+		return
+	}
+
+	// The email terms aren't searched if the email isn't selected so a hit doesn't confirm an
+	// address the caller can't see
+	opts := repos.SearchOptions{Limit: limit, ExcludePII: !selected(fields, "email")}
+
+	results, err := h.svc.EmpRepo.SearchEmployees(h.repoContext(c), query, opts)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to search employees")
+		h.abortWithError(c, err)
+		return
+	}
+
+	resp := make([]SearchResult, 0)
+	for _, result := range results {
+		resp = append(resp, SearchResult{
+			Employee: newEmployee(result.Employee),
+			Score:    result.Score,
+		})
+	}
+
+	h.projectedJSON(c, http.StatusOK, resp, fields)
+}
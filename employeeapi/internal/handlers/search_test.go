@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"employeeapi/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// Test SearchEmployees handler
+func TestSearchEmployees(t *testing.T) {
+	testCases := []struct {
+		name       string
+		query      string
+		httpStatus int
+		ids        []string
+	}{
+		{
+			name:       "Successful - Search Employees",
+			query:      "?q=smith",
+			httpStatus: http.StatusOK,
+			ids:        []string{employeeId2},
+		},
+		{
+			name:       "Successful - Search Employees - misspelled",
+			query:      "?q=Jnae%20Smiht",
+			httpStatus: http.StatusOK,
+			ids:        []string{employeeId2},
+		},
+		{
+			name:       "Successful - Search Employees - email",
+			query:      "?q=johndoe",
+			httpStatus: http.StatusOK,
+			ids:        []string{employeeId1},
+		},
+		{
+			name:       "Successful - Search Employees - email not selected",
+			query:      "?q=johndoe&fields=id,first_name,last_name",
+			httpStatus: http.StatusOK,
+			ids:        []string{},
+		},
+		{
+			name:       "Successful - Search Employees - no match",
+			query:      "?q=nobody",
+			httpStatus: http.StatusOK,
+			ids:        []string{},
+		},
+		{
+			name:       "Failed - Search Employees - query required",
+			httpStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Failed - Search Employees - invalid limit",
+			query:      "?q=smith&limit=0",
+			httpStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, _ := mockRepo("mult")
+
+			svc := services.NewService(logger, false)
+			svc.EmpRepo = repo
+
+			h := NewHandler(logger, svc)
+
+			// Create a request to pass to our handler
+			req, err := http.NewRequest("GET", "/employees/search"+tc.query, nil)
+			require.NoError(t, err, "failed to create request")
+
+			rr := httptest.NewRecorder()
+
+			_, r := gin.CreateTestContext(rr)
+
+			// Set up routes
+			h.SetupRoutes(r)
+
+			// Call the handler
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.httpStatus, rr.Code)
+
+			if rr.Code == http.StatusOK {
+				results := []SearchResult{}
+				err = json.Unmarshal(rr.Body.Bytes(), &results)
+				require.NoError(t, err, "failed to unmarshal response body")
+
+				ids := make([]string, 0)
+				for _, result := range results {
+					ids = append(ids, result.ID)
+					require.Greater(t, result.Score, 0.0)
+				}
+
+				require.Equal(t, tc.ids, ids)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"employeeapi/internal/repos"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// DefaultPurgeRetention is how long deleted employees are kept when no retention is given
+	DefaultPurgeRetention = 30 * 24 * time.Hour
+	// MinPurgeRetention is how long deleted employees are kept at least so a mistaken delete can
+	// still be restored
+	MinPurgeRetention = 24 * time.Hour
+
+	ErrorRetentionTooShort = "retention must be at least 24h"
+)
+
+type PurgeResult struct {
+	Count int      `json:"count"`
+	IDs   []string `json:"ids"`
+}
+
+// RestoreEmployee restores a deleted employee
+func (h Handler) RestoreEmployee(c *gin.Context) {
+	l := h.requestLogger(c).With().Str("package", packageName).Str("func", "RestoreEmployee").Logger()
+
+	id := c.Param("id")
+	if id == "" {
+		l.Error().Msg("id is empty")
+		h.abortWithError(c, &RequestError{Detail: ErrorIDRequired})
+		return
+	}
+
+	fields, err := projectionFields(c)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to parse query parameters")
+		h.abortWithError(c, err)
+		return
+	}
+
+	emp, err := h.svc.EmpRepo.RestoreEmployee(h.repoContext(c), id)
+	if err != nil {
+		l.Error().Err(err).Str("id", id).Msg("failed to restore employee")
+
+		switch {
+		case errors.Is(err, repos.ErrNotDeleted):
+			h.abortWithError(c, err, ErrorEmpNotDeleted)
+		case errors.Is(err, repos.ErrManagerCycle):
+			h.abortWithError(c, err, ErrorManagerCycle)
+		case errors.Is(err, repos.ErrConflict):
+			h.abortWithError(c, err, ErrorEmailInUse)
+		default:
+			h.abortWithError(c, err, ErrorEmpNotFound)
+		}
+		return
+	}
+
+	h.projectedJSON(c, http.StatusOK, newEmployee(emp), fields)
+}
+
+// PurgeEmployees permanently removes employees deleted longer than the retention period
+// The retention period is passed as a duration (e.g. 720h) in the retention query parameter and
+// can't be shorter than MinPurgeRetention
+func (h Handler) PurgeEmployees(c *gin.Context) {
+	l := h.requestLogger(c).With().Str("package", packageName).Str("func", "PurgeEmployees").Logger()
+
+	retention := DefaultPurgeRetention
+	if value := c.Query("retention"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			l.Error().Err(err).Str("retention", value).Msg("invalid retention")
+			h.abortWithError(c, &RequestError{Detail: ErrorInvalidQueryParam, Err: err})
+			return
+		}
+		if parsed < MinPurgeRetention {
+			l.Error().Str("retention", value).Msg("retention too short")
+			h.abortWithError(c, &RequestError{Detail: ErrorRetentionTooShort})
+			return
+		}
+		retention = parsed
+	}
+
+	ids, err := h.svc.EmpRepo.PurgeEmployees(h.repoContext(c), time.Now().UTC().Add(-retention))
+	if err != nil {
+		l.Error().Err(err).Msg("failed to purge employees")
+		h.abortWithError(c, err)
+		return
+	}
+
+	l.Info().Int("count", len(ids)).Dur("retention", retention).Msg("purged employees")
+
+	c.JSON(http.StatusOK, PurgeResult{
+		Count: len(ids),
+		IDs:   ids,
+	})
+}
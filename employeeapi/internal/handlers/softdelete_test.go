@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"employeeapi/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// Test that deleted employees are hidden unless include_deleted is set
+func TestGetEmployeesIncludeDeleted(t *testing.T) {
+	testCases := []struct {
+		name       string
+		query      string
+		httpStatus int
+		count      int
+	}{
+		{
+			name:       "Successful - Get Employees - deleted hidden",
+			httpStatus: http.StatusOK,
+			count:      1,
+		},
+		{
+			name:       "Successful - Get Employees - include deleted",
+			query:      "?include_deleted=true",
+			httpStatus: http.StatusOK,
+			count:      2,
+		},
+		{
+			name:       "Failed - Get Employees - invalid include_deleted",
+			query:      "?include_deleted=maybe",
+			httpStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, data := mockRepo("mult")
+
+			deletedAt := time.Now().UTC()
+			(*data)[employeeId2].DeletedAt = &deletedAt
+
+			svc := services.NewService(logger, false)
+			svc.EmpRepo = repo
+
+			h := NewHandler(logger, svc)
+
+			// Create a request to pass to our handler
+			req, err := http.NewRequest("GET", "/employees"+tc.query, nil)
+			require.NoError(t, err, "failed to create request")
+
+			rr := httptest.NewRecorder()
+
+			_, r := gin.CreateTestContext(rr)
+
+			// Set up routes
+			h.SetupRoutes(r)
+
+			// Call the handler
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.httpStatus, rr.Code)
+
+			if rr.Code == http.StatusOK {
+				employees := []Employee{}
+
+				err = json.Unmarshal(rr.Body.Bytes(), &employees)
+				require.NoError(t, err, "failed to unmarshal response body")
+
+				require.Len(t, employees, tc.count)
+			}
+		})
+	}
+}
+
+// Test RestoreEmployee handler
+func TestRestoreEmployee(t *testing.T) {
+	testCases := []struct {
+		name       string
+		id         string
+		httpStatus int
+	}{
+		{
+			name:       "Successful - Restore Employee",
+			id:         employeeId2,
+			httpStatus: http.StatusOK,
+		},
+		{
+			name:       "Failed - Restore Employee - not deleted",
+			id:         employeeId1,
+			httpStatus: http.StatusConflict,
+		},
+		{
+			name:       "Failed - Restore Employee - not found",
+			id:         uuid.New().String(),
+			httpStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, data := mockRepo("mult")
+
+			deletedAt := time.Now().UTC()
+			(*data)[employeeId2].DeletedAt = &deletedAt
+
+			svc := services.NewService(logger, false)
+			svc.EmpRepo = repo
+
+			h := NewHandler(logger, svc)
+
+			// Create a request to pass to our handler
+			req, err := http.NewRequest("POST", "/employees/"+tc.id+"/restore", nil)
+			require.NoError(t, err, "failed to create request")
+
+			rr := httptest.NewRecorder()
+
+			_, r := gin.CreateTestContext(rr)
+
+			// Set up routes
+			h.SetupRoutes(r)
+
+			// Call the handler
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.httpStatus, rr.Code)
+
+			if rr.Code == http.StatusOK {
+				require.Nil(t, (*data)[tc.id].DeletedAt, "employee not restored")
+			}
+		})
+	}
+}
+
+// Test PurgeEmployees handler
+func TestPurgeEmployees(t *testing.T) {
+	testCases := []struct {
+		name  string
+		query string
+		// Token sent by the client and token of the handler
+		token      string
+		adminToken string
+		httpStatus int
+		count      int
+	}{
+		{
+			name:       "Successful - Purge Employees - default retention",
+			token:      "secret",
+			adminToken: "secret",
+			httpStatus: http.StatusOK,
+			count:      0,
+		},
+		{
+			name:       "Successful - Purge Employees - custom retention",
+			query:      "?retention=24h",
+			token:      "secret",
+			adminToken: "secret",
+			httpStatus: http.StatusOK,
+			count:      1,
+		},
+		{
+			name:       "Failed - Purge Employees - invalid retention",
+			query:      "?retention=soon",
+			token:      "secret",
+			adminToken: "secret",
+			httpStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Failed - Purge Employees - retention too short",
+			query:      "?retention=0s",
+			token:      "secret",
+			adminToken: "secret",
+			httpStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Failed - Purge Employees - invalid token",
+			query:      "?retention=24h",
+			token:      "guess",
+			adminToken: "secret",
+			httpStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "Failed - Purge Employees - no token",
+			query:      "?retention=24h",
+			adminToken: "secret",
+			httpStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "Failed - Purge Employees - admin endpoints disabled",
+			query:      "?retention=24h",
+			token:      "secret",
+			httpStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, data := mockRepo("mult")
+
+			deletedAt := time.Now().UTC().Add(-48 * time.Hour)
+			(*data)[employeeId2].DeletedAt = &deletedAt
+
+			svc := services.NewService(logger, false)
+			svc.EmpRepo = repo
+
+			h := NewHandler(logger, svc).WithAdminToken(tc.adminToken)
+
+			// Create a request to pass to our handler
+			req, err := http.NewRequest("POST", "/admin/employees/purge"+tc.query, nil)
+			require.NoError(t, err, "failed to create request")
+
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+
+			rr := httptest.NewRecorder()
+
+			_, r := gin.CreateTestContext(rr)
+
+			// Set up routes
+			h.SetupRoutes(r)
+
+			// Call the handler
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.httpStatus, rr.Code)
+
+			if rr.Code == http.StatusOK {
+				result := PurgeResult{}
+
+				err = json.Unmarshal(rr.Body.Bytes(), &result)
+				require.NoError(t, err, "failed to unmarshal response body")
+
+				require.Equal(t, tc.count, result.Count)
+				require.Len(t, *data, 2-tc.count)
+			} else {
+				require.Len(t, *data, 2)
+			}
+		})
+	}
+}
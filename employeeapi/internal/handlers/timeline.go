@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Period struct {
+	Department string `json:"department"`
+	Role       string `json:"role"`
+	IsActive   bool   `json:"is_active"`
+	// From is null for the first period of the employees recorded before periods were kept
+	From *time.Time `json:"from"`
+	// To is null for the current period
+	To *time.Time `json:"to"`
+}
+
+// GetEmployeeTimeline returns the department, role and active status periods of an employee, oldest first
+func (h Handler) GetEmployeeTimeline(c *gin.Context) {
+	l := h.requestLogger(c).With().Str("package", packageName).Str("func", "GetEmployeeTimeline").Logger()
+
+	id := c.Param("id")
+	if id == "" {
+		l.Error().Msg("id is empty")
+		h.abortWithError(c, &RequestError{Detail: ErrorIDRequired})
+		return
+	}
+
+	fields, err := projectionFields(c)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to parse query parameters")
+		h.abortWithError(c, err)
+		return
+	}
+
+	periods, err := h.svc.EmpRepo.GetEmployeeTimeline(h.repoContext(c), id)
+	if err != nil {
+		l.Error().Err(err).Str("id", id).Msg("failed to get employee timeline")
+
+		h.abortWithError(c, err, ErrorEmpNotFound)
+		return
+	}
+
+	resp := make([]Period, 0)
+	for _, period := range periods {
+		resp = append(resp, Period{
+			Department: period.Department,
+			Role:       period.Role,
+			IsActive:   period.IsActive,
+			From:       period.From,
+			To:         period.To,
+		})
+	}
+
+	h.projectedJSON(c, http.StatusOK, resp, fields)
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"employeeapi/internal/repos"
+	"employeeapi/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// Test GetEmployeeTimeline handler and the as_of queries
+func TestGetEmployeeTimeline(t *testing.T) {
+	yesterday := time.Now().UTC().AddDate(0, 0, -1).Format(time.DateOnly)
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1).Format(time.RFC3339)
+
+	testCases := []struct {
+		name       string
+		path       string
+		httpStatus int
+		department string
+		periods    int
+	}{
+		{
+			name:       "Successful - Get Employee Timeline",
+			path:       "/employees/" + employeeId1 + "/timeline",
+			httpStatus: http.StatusOK,
+			periods:    2,
+		},
+		{
+			name:       "Failed - Get Employee Timeline - not found",
+			path:       "/employees/" + uuid.New().String() + "/timeline",
+			httpStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Successful - Get Employee - as of a past date",
+			path:       "/employees/" + employeeId1 + "?as_of=" + yesterday,
+			httpStatus: http.StatusOK,
+			department: "Engineering",
+		},
+		{
+			name:       "Successful - Get Employee - as of a time",
+			path:       "/employees/" + employeeId1 + "?as_of=" + tomorrow,
+			httpStatus: http.StatusOK,
+			department: "Finance",
+		},
+		{
+			name:       "Successful - Get Employee - current",
+			path:       "/employees/" + employeeId1,
+			httpStatus: http.StatusOK,
+			department: "Finance",
+		},
+		{
+			name:       "Successful - Get Employees - as of a past date",
+			path:       "/employees?as_of=" + yesterday,
+			httpStatus: http.StatusOK,
+			department: "Engineering",
+		},
+		{
+			name:       "Failed - Get Employee - invalid as of",
+			path:       "/employees/" + employeeId1 + "?as_of=last-year",
+			httpStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, _ := mockRepo("single")
+
+			svc := services.NewService(logger, false)
+			svc.EmpRepo = repo
+
+			h := NewHandler(logger, svc)
+
+			// Move the employee to another department so it has two periods
+			emp, err := repo.GetEmployee(context.Background(), employeeId1, repos.QueryOptions{})
+			require.NoError(t, err)
+			emp.Department = "Finance"
+			_, err = repo.UpdateEmployee(context.Background(), emp)
+			require.NoError(t, err)
+
+			req, err := http.NewRequest("GET", tc.path, nil)
+			require.NoError(t, err, "failed to create request")
+
+			rr := httptest.NewRecorder()
+
+			_, r := gin.CreateTestContext(rr)
+
+			// Set up routes
+			h.SetupRoutes(r)
+
+			// Call the handler
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.httpStatus, rr.Code)
+
+			switch {
+			case rr.Code != http.StatusOK:
+			case tc.periods > 0:
+				periods := []Period{}
+				err = json.Unmarshal(rr.Body.Bytes(), &periods)
+				require.NoError(t, err, "failed to unmarshal response body")
+				require.Len(t, periods, tc.periods)
+				require.Nil(t, periods[0].From)
+				require.Equal(t, "Engineering", periods[0].Department)
+				require.Equal(t, periods[0].To, periods[1].From)
+				require.Equal(t, "Finance", periods[1].Department)
+			default:
+				body := rr.Body.Bytes()
+				if body[0] == '[' {
+					emps := []Employee{}
+					err = json.Unmarshal(body, &emps)
+					require.NoError(t, err, "failed to unmarshal response body")
+					require.Len(t, emps, 1)
+					body, _ = json.Marshal(emps[0])
+				}
+
+				emp := Employee{}
+				err = json.Unmarshal(body, &emp)
+				require.NoError(t, err, "failed to unmarshal response body")
+				require.Equal(t, tc.department, emp.Department)
+			}
+		})
+	}
+}
+
+// Test ParseAsOf
+func TestParseAsOf(t *testing.T) {
+	asOf, err := ParseAsOf("2024-03-01")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 3, 1, 23, 59, 59, 999999999, time.UTC), asOf)
+
+	asOf, err = ParseAsOf("2024-03-01T10:00:00+01:00")
+	require.NoError(t, err)
+	require.True(t, asOf.Equal(time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)))
+
+	_, err = ParseAsOf("01/03/2024")
+	require.Error(t, err)
+}
@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"employeeapi/internal/repos"
+	"employeeapi/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+const ErrorWebhookNotFound = "webhook not found"
+
+type Webhook struct {
+	ID     string   `json:"id"`
+	URL    string   `json:"url" validate:"required,url,max=2000"`
+	Events []string `json:"events" validate:"dive,webhook_event"`
+	// Fields of the employee sent in the payloads, every field if empty; leave out the PII fields
+	// for receivers that mustn't get them
+	Fields []string `json:"fields" validate:"dive,employee_field"`
+	// Secret is only returned when the webhook is created
+	Secret string `json:"secret,omitempty" validate:"omitempty,min=16,max=200"`
+	// IsActive defaults to true on create and to the current status on update
+	IsActive  *bool     `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WebhookDelivery struct {
+	ID         string    `json:"id"`
+	EventID    string    `json:"event_id"`
+	EventType  string    `json:"event_type"`
+	Status     string    `json:"status"`
+	Attempts   int       `json:"attempts"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Sets up the webhook subscription routes
+func (h Handler) setupWebhookRoutes(gin *gin.Engine) {
+	gin.GET("/webhooks", h.GetWebhooks)
+	gin.GET("/webhooks/:id", h.GetWebhook)
+	gin.POST("/webhooks", h.CreateWebhook)
+	gin.PUT("/webhooks/:id", h.UpdateWebhook)
+	gin.DELETE("/webhooks/:id", h.DeleteWebhook)
+	gin.GET("/webhooks/:id/deliveries", h.GetWebhookDeliveries)
+}
+
+// GetWebhooks gets all webhook subscriptions
+func (h Handler) GetWebhooks(c *gin.Context) {
+	l := h.requestLogger(c).With().Str("package", packageName).Str("func", "GetWebhooks").Logger()
+
+	webhooks, err := h.svc.WebhookRepo.GetWebhooks(h.repoContext(c))
+	if err != nil {
+		l.Error().Err(err).Msg("failed to get webhooks")
+		h.abortWithError(c, err)
+		return
+	}
+
+	resp := make([]Webhook, 0)
+	for _, webhook := range webhooks {
+		resp = append(resp, newWebhook(webhook))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetWebhook gets a webhook subscription by id
+func (h Handler) GetWebhook(c *gin.Context) {
+	l := h.requestLogger(c).With().Str("package", packageName).Str("func", "GetWebhook").Logger()
+
+	id := c.Param("id")
+
+	webhook, err := h.svc.WebhookRepo.GetWebhook(h.repoContext(c), id)
+	if err != nil {
+		l.Error().Err(err).Str("id", id).Msg("failed to get webhook")
+		h.abortWithError(c, err, ErrorWebhookNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, newWebhook(webhook))
+}
+
+// CreateWebhook subscribes a url to employee events
+// The response carries the secret used to sign the payloads; it isn't returned afterwards
+func (h Handler) CreateWebhook(c *gin.Context) {
+	l := h.requestLogger(c).With().Str("package", packageName).Str("func", "CreateWebhook").Logger()
+
+	var webhook Webhook
+	if err := c.ShouldBindJSON(&webhook); err != nil {
+		l.Error().Err(err).Msg("failed to bind json")
+		h.abortWithError(c, &RequestError{Detail: ErrorInvalidBody, Err: err})
+		return
+	}
+
+	if err := h.jsonValidator.Struct(webhook); err != nil {
+		l.Error().Err(err).Msg("failed to validate json")
+
+		h.abortWithError(c, err)
+		return
+	}
+
+	isActive := true
+	if webhook.IsActive != nil {
+		isActive = *webhook.IsActive
+	}
+
+	created, err := h.svc.WebhookRepo.CreateWebhook(h.repoContext(c), &repos.Webhook{
+		URL:      webhook.URL,
+		Events:   webhook.Events,
+		Fields:   webhook.Fields,
+		Secret:   webhook.Secret,
+		IsActive: isActive,
+	})
+	if err != nil {
+		l.Error().Err(err).Msg("failed to create webhook")
+		h.abortWithError(c, err)
+		return
+	}
+
+	resp := newWebhook(created)
+	resp.Secret = created.Secret
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// UpdateWebhook updates the url, events, fields and status of a webhook subscription
+// The secret can't be changed
+func (h Handler) UpdateWebhook(c *gin.Context) {
+	l := h.requestLogger(c).With().Str("package", packageName).Str("func", "UpdateWebhook").Logger()
+
+	id := c.Param("id")
+
+	var webhook Webhook
+	if err := c.ShouldBindJSON(&webhook); err != nil {
+		l.Error().Err(err).Msg("failed to bind json")
+		h.abortWithError(c, &RequestError{Detail: ErrorInvalidBody, Err: err})
+		return
+	}
+	webhook.Secret = ""
+
+	if err := h.jsonValidator.Struct(webhook); err != nil {
+		l.Error().Err(err).Msg("failed to validate json")
+
+		h.abortWithError(c, err)
+		return
+	}
+
+	ctx := h.repoContext(c)
+
+	current, err := h.svc.WebhookRepo.GetWebhook(ctx, id)
+	if err != nil {
+		l.Error().Err(err).Str("id", id).Msg("failed to get webhook")
+		h.abortWithError(c, err, ErrorWebhookNotFound)
+		return
+	}
+
+	isActive := current.IsActive
+	if webhook.IsActive != nil {
+		isActive = *webhook.IsActive
+	}
+
+	updated, err := h.svc.WebhookRepo.UpdateWebhook(ctx, &repos.Webhook{
+		ID:       id,
+		URL:      webhook.URL,
+		Events:   webhook.Events,
+		Fields:   webhook.Fields,
+		IsActive: isActive,
+	})
+	if err != nil {
+		l.Error().Err(err).Str("id", id).Msg("failed to update webhook")
+		h.abortWithError(c, err, ErrorWebhookNotFound)
+		return
+	}
+
+	c.JSON(http.StatusOK, newWebhook(updated))
+}
+
+// DeleteWebhook deletes a webhook subscription and its delivery log
+func (h Handler) DeleteWebhook(c *gin.Context) {
+	l := h.requestLogger(c).With().Str("package", packageName).Str("func", "DeleteWebhook").Logger()
+
+	id := c.Param("id")
+
+	if err := h.svc.WebhookRepo.DeleteWebhook(h.repoContext(c), id); err != nil {
+		l.Error().Err(err).Str("id", id).Msg("failed to delete webhook")
+		h.abortWithError(c, err, ErrorWebhookNotFound)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// GetWebhookDeliveries gets the delivery log of a webhook subscription, newest first
+func (h Handler) GetWebhookDeliveries(c *gin.Context) {
+	l := h.requestLogger(c).With().Str("package", packageName).Str("func", "GetWebhookDeliveries").Logger()
+
+	id := c.Param("id")
+
+	deliveries, err := h.svc.WebhookRepo.GetDeliveries(h.repoContext(c), id)
+	if err != nil {
+		l.Error().Err(err).Str("id", id).Msg("failed to get webhook deliveries")
+		h.abortWithError(c, err, ErrorWebhookNotFound)
+		return
+	}
+
+	resp := make([]WebhookDelivery, 0)
+	for _, delivery := range deliveries {
+		resp = append(resp, WebhookDelivery{
+			ID:         delivery.ID,
+			EventID:    delivery.EventID,
+			EventType:  delivery.EventType,
+			Status:     delivery.Status,
+			Attempts:   delivery.Attempts,
+			StatusCode: delivery.StatusCode,
+			Error:      delivery.Error,
+			CreatedAt:  delivery.CreatedAt,
+			UpdatedAt:  delivery.UpdatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Sends the error response of a failed webhook operation
+func (h Handler) sendWebhookErrorResponse(c *gin.Context, err error) {
+	h.abortWithError(c, err, ErrorWebhookNotFound)
+}
+
+// Converts a webhook to its response, without the secret
+func newWebhook(webhook *repos.Webhook) Webhook {
+	events := webhook.Events
+	if events == nil {
+		events = make([]string, 0)
+	}
+
+	fields := webhook.Fields
+	if fields == nil {
+		fields = make([]string, 0)
+	}
+
+	isActive := webhook.IsActive
+
+	return Webhook{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Events:    events,
+		Fields:    fields,
+		IsActive:  &isActive,
+		CreatedAt: webhook.CreatedAt,
+	}
+}
+
+// Custom validation that checks the value is an event webhooks can subscribe to
+func webhookEvent(fl validator.FieldLevel) bool {
+	event, ok := fl.Field().Interface().(string)
+	if !ok {
+		return false
+	}
+
+	for _, e := range services.WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Custom validation that checks the value is the JSON name of a field of the employees
+func employeeField(fl validator.FieldLevel) bool {
+	field, ok := fl.Field().Interface().(string)
+	return ok && employeeFields[field]
+}
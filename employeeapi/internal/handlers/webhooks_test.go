@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"employeeapi/internal/repos"
+	"employeeapi/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+// Test the webhook subscription handlers
+func TestWebhooks(t *testing.T) {
+	inactive := false
+
+	testCases := []struct {
+		name       string
+		method     string
+		path       string
+		body       any
+		httpStatus int
+	}{
+		{
+			name:       "Successful - Get Webhooks",
+			method:     "GET",
+			path:       "/webhooks",
+			httpStatus: http.StatusOK,
+		},
+		{
+			name:       "Successful - Get Webhook",
+			method:     "GET",
+			path:       "/webhooks/{id}",
+			httpStatus: http.StatusOK,
+		},
+		{
+			name:       "Failed - Get Webhook - not found",
+			method:     "GET",
+			path:       "/webhooks/missing",
+			httpStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Successful - Create Webhook",
+			method:     "POST",
+			path:       "/webhooks",
+			body:       Webhook{URL: "https://badges.example.com/hooks", Events: []string{services.EventEmployeeDeleted}},
+			httpStatus: http.StatusCreated,
+		},
+		{
+			name:       "Failed - Create Webhook - invalid url",
+			method:     "POST",
+			path:       "/webhooks",
+			body:       Webhook{URL: "badges"},
+			httpStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Failed - Create Webhook - unknown event",
+			method:     "POST",
+			path:       "/webhooks",
+			body:       Webhook{URL: "https://badges.example.com/hooks", Events: []string{"employee.promoted"}},
+			httpStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Successful - Create Webhook - without PII",
+			method:     "POST",
+			path:       "/webhooks",
+			body:       Webhook{URL: "https://badges.example.com/hooks", Fields: []string{"id", "first_name", "last_name"}},
+			httpStatus: http.StatusCreated,
+		},
+		{
+			name:       "Failed - Create Webhook - unknown field",
+			method:     "POST",
+			path:       "/webhooks",
+			body:       Webhook{URL: "https://badges.example.com/hooks", Fields: []string{"salary"}},
+			httpStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Successful - Update Webhook",
+			method:     "PUT",
+			path:       "/webhooks/{id}",
+			body:       Webhook{URL: "https://payroll.example.com/v2/hooks", IsActive: &inactive},
+			httpStatus: http.StatusOK,
+		},
+		{
+			name:       "Failed - Update Webhook - not found",
+			method:     "PUT",
+			path:       "/webhooks/missing",
+			body:       Webhook{URL: "https://payroll.example.com/v2/hooks"},
+			httpStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Successful - Delete Webhook",
+			method:     "DELETE",
+			path:       "/webhooks/{id}",
+			httpStatus: http.StatusOK,
+		},
+		{
+			name:       "Successful - Get Webhook Deliveries",
+			method:     "GET",
+			path:       "/webhooks/{id}/deliveries",
+			httpStatus: http.StatusOK,
+		},
+		{
+			name:       "Failed - Get Webhook Deliveries - not found",
+			method:     "GET",
+			path:       "/webhooks/missing/deliveries",
+			httpStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, _ := mockRepo("single")
+
+			svc := services.NewService(logger, false)
+			svc.EmpRepo = repo
+
+			h := NewHandler(logger, svc)
+
+			webhook, err := svc.WebhookRepo.CreateWebhook(context.Background(), &repos.Webhook{
+				URL:      "https://payroll.example.com/hooks",
+				IsActive: true,
+			})
+			require.NoError(t, err)
+
+			var body []byte
+			if tc.body != nil {
+				body, err = json.Marshal(tc.body)
+				require.NoError(t, err, "failed to marshal JSON")
+			}
+
+			path := strings.ReplaceAll(tc.path, "{id}", webhook.ID)
+
+			// Create a request to pass to our handler
+			req, err := http.NewRequest(tc.method, path, bytes.NewBuffer(body))
+			require.NoError(t, err, "failed to create request")
+
+			req.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+
+			_, r := gin.CreateTestContext(rr)
+
+			// Set up routes
+			h.SetupRoutes(r)
+
+			// Call the handler
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.httpStatus, rr.Code)
+
+			if rr.Code == http.StatusOK || rr.Code == http.StatusCreated {
+				require.NotContains(t, rr.Body.String(), webhook.Secret)
+			}
+
+			if rr.Code == http.StatusCreated {
+				var created Webhook
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+
+				// The secret is only returned on create
+				require.NotEmpty(t, created.Secret)
+				require.True(t, *created.IsActive)
+			}
+		})
+	}
+}
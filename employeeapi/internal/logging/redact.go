@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"io"
+	"regexp"
+)
+
+// Redacted replaces the PII found in the logs
+const Redacted = "[REDACTED]"
+
+// PIIFields are the log fields whose values are always redacted
+var PIIFields = []string{"dob", "date_of_birth", "email"}
+
+var (
+	// Matches a PII field and its JSON string value, wherever it's nested
+	piiFieldPattern = regexp.MustCompile(`("(?:` + fieldAlternatives() + `)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	// Matches emails inside any value, e.g. error messages
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+)
+
+func fieldAlternatives() string {
+	alternatives := ""
+	for i, field := range PIIFields {
+		if i > 0 {
+			alternatives += "|"
+		}
+		alternatives += regexp.QuoteMeta(field)
+	}
+
+	return alternatives
+}
+
+// redactWriter redacts the PII of the JSON log events written to it
+type redactWriter struct {
+	w io.Writer
+}
+
+// NewRedactWriter wraps the writer of a zerolog logger so the values of PIIFields
+// and any email in the events are redacted before they're written
+func NewRedactWriter(w io.Writer) io.Writer {
+	return &redactWriter{w: w}
+}
+
+// Write redacts an event; zerolog writes each event with a single call
+func (r *redactWriter) Write(p []byte) (int, error) {
+	redacted := piiFieldPattern.ReplaceAll(p, []byte(`${1}"`+Redacted+`"`))
+	redacted = emailPattern.ReplaceAll(redacted, []byte(Redacted))
+
+	if _, err := r.w.Write(redacted); err != nil {
+		return 0, err
+	}
+
+	// Report the length of the event given so zerolog doesn't see a short write
+	return len(p), nil
+}
@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests that the PII of the log events is redacted
+func TestRedactWriter(t *testing.T) {
+	testCases := []struct {
+		name string
+		log  func(l zerolog.Logger)
+		want string
+	}{
+		{
+			name: "Successful - Redact - PII fields",
+			log: func(l zerolog.Logger) {
+				l.Info().Str("email", "john.doe@example.com").Str("dob", "1985-05-15").Str("id", "1").Msg("created")
+			},
+			want: `{"level":"info","email":"[REDACTED]","dob":"[REDACTED]","id":"1","message":"created"}` + "\n",
+		},
+		{
+			name: "Successful - Redact - nested PII fields",
+			log: func(l zerolog.Logger) {
+				l.Info().Dict("employee", zerolog.Dict().Str("first_name", "John").Str("dob", `1985-"05"-15`)).Msg("created")
+			},
+			want: `{"level":"info","employee":{"first_name":"John","dob":"[REDACTED]"},"message":"created"}` + "\n",
+		},
+		{
+			name: "Successful - Redact - emails in errors",
+			log: func(l zerolog.Logger) {
+				l.Error().Err(errors.New(`email "john.doe@example.com" is already used by 1`)).Msg("failed to create employee")
+			},
+			want: `{"level":"error","error":"email \"[REDACTED]\" is already used by 1","message":"failed to create employee"}` + "\n",
+		},
+		{
+			name: "Successful - Redact - nothing to redact",
+			log: func(l zerolog.Logger) {
+				l.Info().Str("route", "/employees/:id").Int("status", 200).Msg("request served")
+			},
+			want: `{"level":"info","route":"/employees/:id","status":200,"message":"request served"}` + "\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			tc.log(zerolog.New(NewRedactWriter(&buf)))
+
+			require.Equal(t, tc.want, buf.String())
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package repos
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore"
+	AuditActionPurge   = "purge"
+
+	// SystemActor is used when no actor is attached to the context
+	SystemActor = "system"
+)
+
+type actorCtxKey struct{}
+
+type AuditEntry struct {
+	EmployeeID string        `json:"employee_id"`
+	Action     string        `json:"action"`
+	Actor      string        `json:"actor"`
+	Timestamp  time.Time     `json:"timestamp"`
+	Changes    []FieldChange `json:"changes"`
+}
+
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// ContextWithActor returns a copy of ctx that carries the actor recorded in the audit trail
+func ContextWithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorCtxKey{}, actor)
+}
+
+// ActorFromContext returns the actor carried by ctx, or SystemActor if there is none
+func ActorFromContext(ctx context.Context) string {
+	if ctx != nil {
+		if actor, ok := ctx.Value(actorCtxKey{}).(string); ok && actor != "" {
+			return actor
+		}
+	}
+
+	return SystemActor
+}
+
+// GetEmployeeHistory gets the audit entries of an employee, oldest first
+// Employees with no entries, e.g. seeded ones, have an empty history; the history of a purged
+// employee is kept
+func (e *employeeRepo) GetEmployeeHistory(ctx context.Context, id string) ([]*AuditEntry, error) {
+	l := requestLogger(ctx, e.logger).With().Str("package", packageName).Str("func", "GetEmployeeHistory").Logger()
+
+	// Ensure we read once it's safe to do so
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	entries, ok := e.auditLog[id]
+	if _, exists := e.empData[id]; !ok && !exists {
+		err := ErrNotFound
+
+		l.Error().Err(err).Msg("failed to get employee history")
+
+		return nil, err
+	}
+
+	history := make([]*AuditEntry, 0, len(entries))
+	for _, entry := range entries {
+		entryCopy := *entry
+		entryCopy.Changes = e.openChanges(entry.Changes)
+		history = append(history, &entryCopy)
+	}
+
+	return history, nil
+}
+
+// Appends an audit entry for the change from before to after
+// The caller must hold the write lock
+func (e *employeeRepo) recordAudit(ctx context.Context, action string, before, after *Employee) {
+	id := ""
+	if before != nil {
+		id = before.ID
+	} else if after != nil {
+		id = after.ID
+	}
+
+	e.auditLog[id] = append(e.auditLog[id], &AuditEntry{
+		EmployeeID: id,
+		Action:     action,
+		Actor:      ActorFromContext(ctx),
+		Timestamp:  time.Now().UTC(),
+		Changes:    e.sealChanges(diffEmployees(before, after)),
+	})
+}
+
+// Fields of the audit changes encrypted like the stored records
+var encryptedAuditFields = map[string]bool{
+	"dob":   true,
+	"email": true,
+}
+
+// Encrypts the values of the PII fields of changes in place
+func (e *employeeRepo) sealChanges(changes []FieldChange) []FieldChange {
+	for i, change := range changes {
+		if !encryptedAuditFields[change.Field] {
+			continue
+		}
+
+		if before, ok := change.Before.(string); ok {
+			changes[i].Before = e.cipher.Encrypt(before)
+		}
+		if after, ok := change.After.(string); ok {
+			changes[i].After = e.cipher.Encrypt(after)
+		}
+	}
+
+	return changes
+}
+
+// Returns a copy of changes with the values of the PII fields decrypted
+func (e *employeeRepo) openChanges(changes []FieldChange) []FieldChange {
+	opened := make([]FieldChange, len(changes))
+	copy(opened, changes)
+
+	for i, change := range opened {
+		if !encryptedAuditFields[change.Field] {
+			continue
+		}
+
+		if before, ok := change.Before.(string); ok {
+			opened[i].Before = e.decrypt(before)
+		}
+		if after, ok := change.After.(string); ok {
+			opened[i].After = e.decrypt(after)
+		}
+	}
+
+	return opened
+}
+
+// Compares the fields of two employee records and returns the ones that differ
+// When either side is nil (create or delete), every field is returned
+func diffEmployees(before, after *Employee) []FieldChange {
+	changes := make([]FieldChange, 0)
+
+	var beforeVal, afterVal reflect.Value
+	if before != nil {
+		beforeVal = reflect.ValueOf(*before)
+	}
+	if after != nil {
+		afterVal = reflect.ValueOf(*after)
+	}
+
+	empType := reflect.TypeOf(Employee{})
+	for i := 0; i < empType.NumField(); i++ {
+		field := empType.Field(i)
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		change := FieldChange{Field: name}
+		if beforeVal.IsValid() {
+			change.Before = beforeVal.Field(i).Interface()
+		}
+		if afterVal.IsValid() {
+			change.After = afterVal.Field(i).Interface()
+		}
+
+		if beforeVal.IsValid() && afterVal.IsValid() && reflect.DeepEqual(change.Before, change.After) {
+			continue
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes
+}
@@ -0,0 +1,87 @@
+package repos
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests that create, update and delete are recorded in the audit trail
+func TestGetEmployeeHistory(t *testing.T) {
+	logger := zerolog.New(os.Stdout)
+
+	repo := NewEmployeeRepo(logger, nil)
+
+	ctx := ContextWithActor(context.Background(), "hr.admin")
+
+	employee, err := repo.CreateEmployee(ctx, &Employee{
+		FirstName:   "John",
+		LastName:    "Doe",
+		DateOfBirth: "1985-05-15",
+		Email:       "johndoe@example.com",
+		IsActive:    true,
+		Department:  "Engineering",
+		Role:        "Software Developer",
+	})
+	require.NoError(t, err)
+
+	update := *employee
+	update.Department = "Marketing"
+	_, err = repo.UpdateEmployee(context.Background(), &update)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.DeleteEmployee(ctx, employee.ID))
+
+	testCases := []struct {
+		name    string
+		id      string
+		actions []string
+		actors  []string
+		err     error
+	}{
+		{
+			name:    "Successful - Get Employee History",
+			id:      employee.ID,
+			actions: []string{AuditActionCreate, AuditActionUpdate, AuditActionDelete},
+			actors:  []string{"hr.admin", SystemActor, "hr.admin"},
+		},
+		{
+			name: "Failed - Get Employee History",
+			id:   "123",
+			err:  ErrNotFound,
+		},
+	}
+
+	// Employees without audit entries, e.g. seeded ones, have an empty history
+	seeded := &Employee{ID: "seeded", FirstName: "Jane"}
+	repo.(*employeeRepo).empData[seeded.ID] = seeded
+
+	history, err := repo.GetEmployeeHistory(context.Background(), seeded.ID)
+	require.NoError(t, err)
+	require.Empty(t, history)
+	require.NotNil(t, history)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			history, err := repo.GetEmployeeHistory(context.Background(), tc.id)
+
+			require.ErrorIs(t, err, tc.err)
+
+			if tc.err == nil {
+				require.Len(t, history, len(tc.actions))
+
+				for i, entry := range history {
+					require.Equal(t, tc.id, entry.EmployeeID)
+					require.Equal(t, tc.actions[i], entry.Action)
+					require.Equal(t, tc.actors[i], entry.Actor)
+				}
+
+				// Only the changed field is recorded on update
+				require.Equal(t, []FieldChange{{Field: "department", Before: "Engineering", After: "Marketing"}}, history[1].Changes)
+			}
+		})
+	}
+}
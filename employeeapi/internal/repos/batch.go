@@ -0,0 +1,218 @@
+package repos
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	BatchOpCreate = "create"
+	BatchOpUpdate = "update"
+	BatchOpDelete = "delete"
+)
+
+var ErrUnknownBatchOp = errors.New("unknown batch operation")
+
+// BatchOperation is one of the writes of ApplyBatch
+type BatchOperation struct {
+	// Op is BatchOpCreate, BatchOpUpdate or BatchOpDelete
+	Op string
+	// ID of the employee to update or delete
+	ID string
+	// Employee to create
+	Employee *Employee
+	// Update of the employee to update, merged into the employee as the operations before
+	// it left it, see MergeEmployee
+	Update EmployeeUpdate
+}
+
+// A write applied to the stored records whose side effects (audit, periods, search index
+// and listeners) are left to onWrite
+type stagedWrite struct {
+	action        string
+	before, after *Employee
+}
+
+// ApplyBatch applies the operations in order, all of them or none
+// Each operation sees the writes of the ones before it, e.g. an email released by a delete can
+// be taken by a later update, and updates are merged under the write lock so no concurrent
+// write is lost
+// Returns the created and updated employees, and the deleted ones, in the order of ops
+// Returns a BulkError wrapping the error of the first operation that fails,
+// the same error its own write method would return
+// With dryRun every operation is checked but nothing is written
+func (e *employeeRepo) ApplyBatch(ctx context.Context, ops []*BatchOperation, dryRun bool) ([]*Employee, error) {
+	l := requestLogger(ctx, e.logger).With().Str("package", packageName).Str("func", "ApplyBatch").Logger()
+
+	// Ensure only one write at a time
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	rollback := e.snapshot()
+
+	writes := make([]stagedWrite, 0, len(ops))
+	for i, op := range ops {
+		var write stagedWrite
+		var err error
+
+		switch op.Op {
+		case BatchOpCreate:
+			write, err = e.stageCreate(op.Employee)
+		case BatchOpUpdate:
+			write, err = e.stageMerge(op.ID, op.Update)
+		case BatchOpDelete:
+			write, err = e.stageDelete(op.ID)
+		default:
+			err = &ValidationError{Field: "op", Err: ErrUnknownBatchOp}
+		}
+
+		if err != nil {
+			rollback()
+
+			bulkErr := &BulkError{Index: i, Err: err}
+
+			l.Error().Err(bulkErr).Msg("failed to apply batch")
+
+			return nil, bulkErr
+		}
+
+		writes = append(writes, write)
+	}
+
+	results := make([]*Employee, 0, len(writes))
+	for _, write := range writes {
+		results = append(results, write.after)
+	}
+
+	if dryRun {
+		rollback()
+		return results, nil
+	}
+
+	for _, write := range writes {
+		e.onWrite(ctx, write.action, write.before, write.after)
+	}
+
+	return results, nil
+}
+
+// Keeps the records and the email index as they are and returns the function restoring them, so
+// staged writes can be rolled back
+// Stored records are replaced rather than modified so a shallow copy is enough
+// The caller must hold the write lock
+func (e *employeeRepo) snapshot() func() {
+	empData := maps.Clone(e.empData)
+	emailIndex := maps.Clone(e.emailIndex)
+
+	return func() {
+		// The maps are restored in place as empData is shared with the caller of NewEmployeeRepo
+		clear(e.empData)
+		maps.Copy(e.empData, empData)
+		clear(e.emailIndex)
+		maps.Copy(e.emailIndex, emailIndex)
+	}
+}
+
+// Stores a new employee, setting its id
+// The caller must hold the write lock and pass the write to onWrite once committed
+func (e *employeeRepo) stageCreate(emp *Employee) (stagedWrite, error) {
+	if err := e.checkEmailAvailable("", emp.Email); err != nil {
+		return stagedWrite{}, err
+	}
+
+	if err := e.checkCatalogue(emp); err != nil {
+		return stagedWrite{}, err
+	}
+
+	if err := e.checkManager("", emp.ManagerID); err != nil {
+		return stagedWrite{}, err
+	}
+
+	emp.ID = uuid.New().String()
+	emp.DeletedAt = nil
+	e.empData[emp.ID] = e.seal(emp)
+	e.emailIndex[e.emailIndexKey(emp.Email)] = emp.ID
+
+	return stagedWrite{action: AuditActionCreate, after: emp}, nil
+}
+
+// Stores the new fields of an employee that isn't deleted
+// The caller must hold the write lock and pass the write to onWrite once committed
+func (e *employeeRepo) stageUpdate(emp *Employee) (stagedWrite, error) {
+	stored, ok := e.empData[emp.ID]
+	if !ok || stored.DeletedAt != nil {
+		return stagedWrite{}, ErrNotFound
+	}
+
+	if err := e.checkEmailAvailable(emp.ID, emp.Email); err != nil {
+		return stagedWrite{}, err
+	}
+
+	if err := e.checkCatalogue(emp); err != nil {
+		return stagedWrite{}, err
+	}
+
+	if err := e.checkManager(emp.ID, emp.ManagerID); err != nil {
+		return stagedWrite{}, err
+	}
+
+	before := e.open(stored)
+
+	after := *before
+	after.FirstName = emp.FirstName
+	after.LastName = emp.LastName
+	after.DateOfBirth = emp.DateOfBirth
+	after.Email = emp.Email
+	after.IsActive = emp.IsActive
+	after.Department = emp.Department
+	after.Role = emp.Role
+	after.ManagerID = emp.ManagerID
+	after.HireDate = emp.HireDate
+
+	e.empData[emp.ID] = e.seal(&after)
+
+	delete(e.emailIndex, e.emailIndexKey(before.Email))
+	e.emailIndex[e.emailIndexKey(after.Email)] = after.ID
+
+	return stagedWrite{action: AuditActionUpdate, before: before, after: &after}, nil
+}
+
+// Merges an update into an employee that isn't deleted and stores it
+// The caller must hold the write lock and pass the write to onWrite once committed
+func (e *employeeRepo) stageMerge(id string, update EmployeeUpdate) (stagedWrite, error) {
+	stored, ok := e.empData[id]
+	if !ok || stored.DeletedAt != nil {
+		return stagedWrite{}, ErrNotFound
+	}
+
+	emp := e.open(stored)
+	MergeEmployee(emp, update)
+
+	return e.stageUpdate(emp)
+}
+
+// Marks an employee that isn't deleted as deleted
+// The caller must hold the write lock and pass the write to onWrite once committed
+func (e *employeeRepo) stageDelete(id string) (stagedWrite, error) {
+	stored, ok := e.empData[id]
+	if !ok || stored.DeletedAt != nil {
+		return stagedWrite{}, ErrNotFound
+	}
+
+	before := e.open(stored)
+
+	after := *before
+	deletedAt := time.Now().UTC()
+	after.DeletedAt = &deletedAt
+
+	e.empData[id] = e.seal(&after)
+
+	// Deleted employees release their email
+	delete(e.emailIndex, e.emailIndexKey(after.Email))
+
+	return stagedWrite{action: AuditActionDelete, before: before, after: &after}, nil
+}
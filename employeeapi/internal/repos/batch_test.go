@@ -0,0 +1,149 @@
+package repos
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests the ApplyBatch method
+func TestApplyBatch(t *testing.T) {
+	john := &Employee{
+		ID:         uuid.New().String(),
+		FirstName:  "John",
+		LastName:   "Doe",
+		Email:      "johndoe@example.com",
+		Department: "Engineering",
+	}
+	jane := &Employee{
+		ID:        uuid.New().String(),
+		FirstName: "Jane",
+		LastName:  "Smith",
+		Email:     "janesmith@example.com",
+	}
+
+	testCases := []struct {
+		name   string
+		ops    []*BatchOperation
+		dryRun bool
+		index  int
+		err    error
+	}{
+		{
+			name: "Successful - Apply Batch",
+			ops: []*BatchOperation{
+				{Op: BatchOpCreate, Employee: &Employee{FirstName: "Emily", Email: "emily@example.com"}},
+				// Jane's email is released by her deletion so it can be taken in the same batch
+				{Op: BatchOpDelete, ID: jane.ID},
+				{Op: BatchOpUpdate, ID: john.ID, Update: EmployeeUpdate{FirstName: "John", Email: "janesmith@example.com", Department: "Finance"}},
+			},
+		},
+		{
+			name: "Successful - Apply Batch - update merged with the earlier one",
+			ops: []*BatchOperation{
+				{Op: BatchOpCreate, Employee: &Employee{FirstName: "Emily", Email: "emily@example.com"}},
+				{Op: BatchOpDelete, ID: jane.ID},
+				{Op: BatchOpUpdate, ID: john.ID, Update: EmployeeUpdate{FirstName: "John", Email: "johndoe@example.com", Department: "Finance"}},
+				// The department set by the update before is kept
+				{Op: BatchOpUpdate, ID: john.ID, Update: EmployeeUpdate{FirstName: "John", Email: "janesmith@example.com"}},
+			},
+		},
+		{
+			name: "Successful - Apply Batch - dry run",
+			ops: []*BatchOperation{
+				{Op: BatchOpCreate, Employee: &Employee{FirstName: "Emily", Email: "emily@example.com"}},
+				{Op: BatchOpDelete, ID: jane.ID},
+			},
+			dryRun: true,
+		},
+		{
+			name: "Failed - Apply Batch - email in use",
+			ops: []*BatchOperation{
+				{Op: BatchOpCreate, Employee: &Employee{FirstName: "Emily", Email: "emily@example.com"}},
+				{Op: BatchOpUpdate, ID: john.ID, Update: EmployeeUpdate{FirstName: "John", Email: "emily@example.com"}},
+			},
+			index: 1,
+			err:   ErrConflict,
+		},
+		{
+			name: "Failed - Apply Batch - employee deleted earlier in the batch",
+			ops: []*BatchOperation{
+				{Op: BatchOpDelete, ID: jane.ID},
+				{Op: BatchOpDelete, ID: jane.ID},
+			},
+			index: 1,
+			err:   ErrNotFound,
+		},
+		{
+			name: "Failed - Apply Batch - manager cycle",
+			ops: []*BatchOperation{
+				{Op: BatchOpUpdate, ID: jane.ID, Update: EmployeeUpdate{FirstName: "Jane", Email: jane.Email, ManagerID: &john.ID}},
+				{Op: BatchOpUpdate, ID: john.ID, Update: EmployeeUpdate{FirstName: "John", Email: john.Email, ManagerID: &jane.ID}},
+			},
+			index: 1,
+			err:   ErrManagerCycle,
+		},
+		{
+			name:  "Failed - Apply Batch - unknown operation",
+			ops:   []*BatchOperation{{Op: "upsert", ID: jane.ID}},
+			index: 0,
+			err:   ErrUnknownBatchOp,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := zerolog.New(os.Stdout)
+
+			johnCopy, janeCopy := *john, *jane
+			data := map[string]*Employee{john.ID: &johnCopy, jane.ID: &janeCopy}
+
+			repo := NewEmployeeRepo(logger, &data)
+
+			writes := 0
+			repo.AddListener(func(event Event) {
+				writes++
+			})
+
+			results, err := repo.ApplyBatch(context.Background(), tc.ops, tc.dryRun)
+
+			if tc.err != nil {
+				var bulkErr *BulkError
+
+				require.True(t, errors.As(err, &bulkErr))
+				require.Equal(t, tc.index, bulkErr.Index)
+				require.ErrorIs(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+				require.Len(t, results, len(tc.ops))
+			}
+
+			if tc.err != nil || tc.dryRun {
+				// Nothing is written
+				require.Zero(t, writes)
+				require.Len(t, data, 2)
+				require.Nil(t, data[jane.ID].DeletedAt)
+				require.Equal(t, "johndoe@example.com", data[john.ID].Email)
+
+				// The email index is rolled back too
+				_, err := repo.CreateEmployee(context.Background(), &Employee{FirstName: "Emily", Email: "emily@example.com"})
+				require.NoError(t, err)
+				_, err = repo.CreateEmployee(context.Background(), &Employee{FirstName: "Janet", Email: jane.Email})
+				require.ErrorIs(t, err, ErrConflict)
+				return
+			}
+
+			require.Equal(t, len(tc.ops), writes)
+			require.Len(t, data, 3)
+			require.NotNil(t, data[jane.ID].DeletedAt)
+			require.Equal(t, "janesmith@example.com", data[john.ID].Email)
+			require.Equal(t, "Finance", data[john.ID].Department)
+			require.Equal(t, "Emily", data[results[0].ID].FirstName)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+)
+
+// BulkError is returned by bulk writes and tells which record made the write fail
+type BulkError struct {
+	Index int
+	Err   error
+}
+
+func (b *BulkError) Error() string {
+	return fmt.Sprintf("record %d: %v", b.Index, b.Err)
+}
+
+func (b *BulkError) Unwrap() error {
+	return b.Err
+}
+
+// CreateEmployees creates all the employees or none of them
+// Returns a BulkError wrapping a ConflictError if an email is already used,
+// either by an existing employee or by another employee in emps,
+// or wrapping a ValidationError if a manager, department or role doesn't exist
+func (e *employeeRepo) CreateEmployees(ctx context.Context, emps []*Employee) ([]*Employee, error) {
+	l := requestLogger(ctx, e.logger).With().Str("package", packageName).Str("func", "CreateEmployees").Logger()
+
+	// Ensure only one write at a time
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// The records are created like the creates of a batch, each one seeing the ones before it
+	rollback := e.snapshot()
+
+	writes := make([]stagedWrite, 0, len(emps))
+	for i, emp := range emps {
+		write, err := e.stageCreate(emp)
+		if err != nil {
+			rollback()
+
+			bulkErr := &BulkError{Index: i, Err: err}
+
+			l.Error().Err(bulkErr).Msg("failed to create employees")
+
+			return nil, bulkErr
+		}
+
+		writes = append(writes, write)
+	}
+
+	for _, write := range writes {
+		e.onWrite(ctx, write.action, write.before, write.after)
+	}
+
+	return emps, nil
+}
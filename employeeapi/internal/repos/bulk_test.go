@@ -0,0 +1,91 @@
+package repos
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests the CreateEmployees method
+func TestCreateEmployees(t *testing.T) {
+	existing := &Employee{
+		ID:        uuid.New().String(),
+		FirstName: "John",
+		LastName:  "Doe",
+		Email:     "johndoe@example.com",
+	}
+
+	testCases := []struct {
+		name      string
+		employees []*Employee
+		index     int
+		err       bool
+	}{
+		{
+			name: "Successful - Create Employees",
+			employees: []*Employee{
+				{FirstName: "Jane", Email: "janesmith@example.com"},
+				{FirstName: "Emily", Email: "emilywilliams@example.com"},
+			},
+		},
+		{
+			name: "Failed - Create Employees - email of existing employee",
+			employees: []*Employee{
+				{FirstName: "Jane", Email: "janesmith@example.com"},
+				{FirstName: "Johnny", Email: "JohnDoe@example.com"},
+			},
+			index: 1,
+			err:   true,
+		},
+		{
+			name: "Failed - Create Employees - email used twice",
+			employees: []*Employee{
+				{FirstName: "Jane", Email: "janesmith@example.com"},
+				{FirstName: "Emily", Email: "emilywilliams@example.com"},
+				{FirstName: "Janet", Email: "janesmith@example.com"},
+			},
+			index: 2,
+			err:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := zerolog.New(os.Stdout)
+
+			data := map[string]*Employee{existing.ID: existing}
+
+			repo := NewEmployeeRepo(logger, &data)
+
+			employees, err := repo.CreateEmployees(context.Background(), tc.employees)
+
+			if tc.err {
+				var bulkErr *BulkError
+				var conflictErr *ConflictError
+
+				require.True(t, errors.As(err, &bulkErr))
+				require.True(t, errors.As(err, &conflictErr))
+				require.Equal(t, tc.index, bulkErr.Index)
+
+				// Nothing is created and the emails of the records staged before are released
+				require.Len(t, data, 1)
+				_, err = repo.CreateEmployee(context.Background(), &Employee{FirstName: "Jane", Email: "janesmith@example.com"})
+				require.NoError(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, employees, len(tc.employees))
+			require.Len(t, data, len(tc.employees)+1)
+
+			for _, emp := range employees {
+				require.Equal(t, emp, data[emp.ID])
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package repos
+
+import (
+	"context"
+	"sort"
+)
+
+const (
+	RecordInUse = "record is in use"
+
+	DepartmentNotFound = "department not found"
+	RoleNotFound       = "role not found"
+
+	DepartmentUnassigned = "Unassigned"
+	RoleUnassigned       = "Unassigned"
+)
+
+var (
+	// DefaultDepartments are the departments every new repository starts with
+	DefaultDepartments = []string{"Engineering", "Marketing", "Finance", "Human Resources", DepartmentUnassigned}
+	// DefaultRoles are the roles every new repository starts with
+	DefaultRoles = []string{"Software Developer", "Marketing Specialist", "Financial Analyst", "HR Specialist", RoleUnassigned}
+)
+
+// CatalogueEntry is a department or a role employees can be assigned to
+type CatalogueEntry struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type CatalogueRepo interface {
+	GetDepartments(ctx context.Context) ([]*CatalogueEntry, error)
+	GetDepartment(ctx context.Context, name string) (*CatalogueEntry, error)
+	CreateDepartment(ctx context.Context, dept *CatalogueEntry) (*CatalogueEntry, error)
+	UpdateDepartment(ctx context.Context, dept *CatalogueEntry) (*CatalogueEntry, error)
+	DeleteDepartment(ctx context.Context, name string) error
+
+	GetRoles(ctx context.Context) ([]*CatalogueEntry, error)
+	GetRole(ctx context.Context, name string) (*CatalogueEntry, error)
+	CreateRole(ctx context.Context, role *CatalogueEntry) (*CatalogueEntry, error)
+	UpdateRole(ctx context.Context, role *CatalogueEntry) (*CatalogueEntry, error)
+	DeleteRole(ctx context.Context, name string) error
+}
+
+// catalogue holds the entries of one kind (departments or roles) keyed by name
+type catalogue struct {
+	kind       string
+	unassigned string
+	entries    map[string]*CatalogueEntry
+	// notFound is the reason an employee referencing a missing entry is invalid
+	notFound error
+	// inUse tells if an employee references the entry
+	inUse func(emp *Employee, name string) bool
+}
+
+func newCatalogue(kind, unassigned string, names []string, notFound error, inUse func(emp *Employee, name string) bool) *catalogue {
+	c := &catalogue{
+		kind:       kind,
+		unassigned: unassigned,
+		entries:    make(map[string]*CatalogueEntry),
+		notFound:   notFound,
+		inUse:      inUse,
+	}
+
+	for _, name := range names {
+		c.entries[name] = &CatalogueEntry{Name: name}
+	}
+
+	return c
+}
+
+// Checks the department and role of an employee exist; empty ones aren't assigned
+// The request validators check them too, but only the check under the write lock can't race
+// with the deletion of an entry
+// The caller must hold the write lock
+func (e *employeeRepo) checkCatalogue(emp *Employee) error {
+	for _, ref := range []struct {
+		cat  *catalogue
+		name string
+	}{{e.departments, emp.Department}, {e.roles, emp.Role}} {
+		if ref.name == "" {
+			continue
+		}
+
+		if _, ok := ref.cat.entries[ref.name]; !ok {
+			return &ValidationError{Field: ref.cat.kind, Err: ref.cat.notFound}
+		}
+	}
+
+	return nil
+}
+
+func (e *employeeRepo) GetDepartments(ctx context.Context) ([]*CatalogueEntry, error) {
+	return e.getCatalogueEntries(e.departments)
+}
+
+func (e *employeeRepo) GetDepartment(ctx context.Context, name string) (*CatalogueEntry, error) {
+	return e.getCatalogueEntry(e.departments, name)
+}
+
+func (e *employeeRepo) CreateDepartment(ctx context.Context, dept *CatalogueEntry) (*CatalogueEntry, error) {
+	return e.createCatalogueEntry(ctx, e.departments, dept)
+}
+
+func (e *employeeRepo) UpdateDepartment(ctx context.Context, dept *CatalogueEntry) (*CatalogueEntry, error) {
+	return e.updateCatalogueEntry(ctx, e.departments, dept)
+}
+
+// DeleteDepartment deletes a department
+// Returns ErrInUse if an employee is still assigned to it
+func (e *employeeRepo) DeleteDepartment(ctx context.Context, name string) error {
+	return e.deleteCatalogueEntry(ctx, e.departments, name)
+}
+
+func (e *employeeRepo) GetRoles(ctx context.Context) ([]*CatalogueEntry, error) {
+	return e.getCatalogueEntries(e.roles)
+}
+
+func (e *employeeRepo) GetRole(ctx context.Context, name string) (*CatalogueEntry, error) {
+	return e.getCatalogueEntry(e.roles, name)
+}
+
+func (e *employeeRepo) CreateRole(ctx context.Context, role *CatalogueEntry) (*CatalogueEntry, error) {
+	return e.createCatalogueEntry(ctx, e.roles, role)
+}
+
+func (e *employeeRepo) UpdateRole(ctx context.Context, role *CatalogueEntry) (*CatalogueEntry, error) {
+	return e.updateCatalogueEntry(ctx, e.roles, role)
+}
+
+// DeleteRole deletes a role
+// Returns ErrInUse if an employee is still assigned to it
+func (e *employeeRepo) DeleteRole(ctx context.Context, name string) error {
+	return e.deleteCatalogueEntry(ctx, e.roles, name)
+}
+
+func (e *employeeRepo) getCatalogueEntries(cat *catalogue) ([]*CatalogueEntry, error) {
+	// Ensure we read once it's safe to do so
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	entries := make([]*CatalogueEntry, 0, len(cat.entries))
+	for _, entry := range cat.entries {
+		entryCopy := *entry
+		entries = append(entries, &entryCopy)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+
+	return entries, nil
+}
+
+func (e *employeeRepo) getCatalogueEntry(cat *catalogue, name string) (*CatalogueEntry, error) {
+	// Ensure we read once it's safe to do so
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if entry, ok := cat.entries[name]; ok {
+		entryCopy := *entry
+		return &entryCopy, nil
+	}
+
+	return nil, ErrNotFound
+}
+
+func (e *employeeRepo) createCatalogueEntry(ctx context.Context, cat *catalogue, entry *CatalogueEntry) (*CatalogueEntry, error) {
+	l := requestLogger(ctx, e.logger).With().Str("package", packageName).Str("func", "createCatalogueEntry").Str("kind", cat.kind).Logger()
+
+	// Ensure only one write at a time
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := cat.entries[entry.Name]; ok {
+		err := &ConflictError{
+			Field:      cat.kind,
+			Value:      entry.Name,
+			ConflictID: entry.Name,
+		}
+
+		l.Error().Err(err).Msg("failed to create catalogue entry")
+
+		return nil, err
+	}
+
+	entryCopy := *entry
+	cat.entries[entry.Name] = &entryCopy
+
+	return entry, nil
+}
+
+func (e *employeeRepo) updateCatalogueEntry(ctx context.Context, cat *catalogue, entry *CatalogueEntry) (*CatalogueEntry, error) {
+	l := requestLogger(ctx, e.logger).With().Str("package", packageName).Str("func", "updateCatalogueEntry").Str("kind", cat.kind).Logger()
+
+	// Ensure only one write at a time
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	existing, ok := cat.entries[entry.Name]
+	if !ok {
+		err := ErrNotFound
+
+		l.Error().Err(err).Msg("failed to update catalogue entry")
+
+		return nil, err
+	}
+
+	existing.Description = entry.Description
+
+	entryCopy := *existing
+	return &entryCopy, nil
+}
+
+func (e *employeeRepo) deleteCatalogueEntry(ctx context.Context, cat *catalogue, name string) error {
+	l := requestLogger(ctx, e.logger).With().Str("package", packageName).Str("func", "deleteCatalogueEntry").Str("kind", cat.kind).Logger()
+
+	// Ensure only one write at a time
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := cat.entries[name]; !ok {
+		err := ErrNotFound
+
+		l.Error().Err(err).Msg("failed to delete catalogue entry")
+
+		return err
+	}
+
+	// The unassigned entry is the default of new employees so it can't be removed
+	inUse := name == cat.unassigned
+
+	// Deleted employees count too since they can be restored
+	for _, emp := range e.empData {
+		if inUse {
+			break
+		}
+		inUse = cat.inUse(emp, name)
+	}
+
+	if inUse {
+		err := ErrInUse
+
+		l.Error().Err(err).Str("name", name).Msg("failed to delete catalogue entry")
+
+		return err
+	}
+
+	delete(cat.entries, name)
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package repos
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests the department catalogue methods
+func TestDepartments(t *testing.T) {
+	logger := zerolog.New(os.Stdout)
+
+	data := make(map[string]*Employee)
+
+	employee := &Employee{
+		ID:         uuid.New().String(),
+		FirstName:  "John",
+		LastName:   "Doe",
+		Email:      "johndoe@example.com",
+		Department: "Engineering",
+		Role:       "Software Developer",
+	}
+
+	data[employee.ID] = employee
+
+	repo := NewEmployeeRepo(logger, &data)
+	ctx := context.Background()
+
+	departments, err := repo.GetDepartments(ctx)
+	require.NoError(t, err)
+	require.Len(t, departments, len(DefaultDepartments))
+
+	_, err = repo.CreateDepartment(ctx, &CatalogueEntry{Name: "Legal"})
+	require.NoError(t, err)
+
+	var conflictErr *ConflictError
+	_, err = repo.CreateDepartment(ctx, &CatalogueEntry{Name: "Legal"})
+	require.True(t, errors.As(err, &conflictErr))
+
+	dept, err := repo.UpdateDepartment(ctx, &CatalogueEntry{Name: "Legal", Description: "Contracts and compliance"})
+	require.NoError(t, err)
+	require.Equal(t, "Contracts and compliance", dept.Description)
+
+	_, err = repo.UpdateDepartment(ctx, &CatalogueEntry{Name: "Sales"})
+	require.ErrorIs(t, err, ErrNotFound)
+
+	testCases := []struct {
+		name string
+		dept string
+		err  error
+	}{
+		{
+			name: "Successful - Delete Department",
+			dept: "Legal",
+			err:  nil,
+		},
+		{
+			name: "Failed - Delete Department - in use",
+			dept: "Engineering",
+			err:  ErrInUse,
+		},
+		{
+			name: "Failed - Delete Department - unassigned",
+			dept: DepartmentUnassigned,
+			err:  ErrInUse,
+		},
+		{
+			name: "Failed - Delete Department - not found",
+			dept: "Sales",
+			err:  ErrNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := repo.DeleteDepartment(ctx, tc.dept)
+
+			require.ErrorIs(t, err, tc.err)
+
+			if tc.err == nil {
+				_, err = repo.GetDepartment(ctx, tc.dept)
+				require.ErrorIs(t, err, ErrNotFound)
+			}
+		})
+	}
+}
+
+// Tests that roles of deleted employees are still protected
+func TestDeleteRoleInUseByDeletedEmployee(t *testing.T) {
+	logger := zerolog.New(os.Stdout)
+
+	repo := NewEmployeeRepo(logger, nil)
+	ctx := context.Background()
+
+	_, err := repo.CreateRole(ctx, &CatalogueEntry{Name: "Lawyer"})
+	require.NoError(t, err)
+
+	employee, err := repo.CreateEmployee(ctx, &Employee{FirstName: "John", Email: "johndoe@example.com", Role: "Lawyer"})
+	require.NoError(t, err)
+
+	require.NoError(t, repo.DeleteEmployee(ctx, employee.ID))
+
+	require.ErrorIs(t, repo.DeleteRole(ctx, "Lawyer"), ErrInUse)
+}
+
+// Tests that employees can't reference a department or role that doesn't exist, whichever path
+// writes them, so a deletion can't leave them dangling
+func TestEmployeeCatalogueEntryMissing(t *testing.T) {
+	logger := zerolog.New(os.Stdout)
+
+	repo := NewEmployeeRepo(logger, nil)
+	ctx := context.Background()
+
+	_, err := repo.CreateDepartment(ctx, &CatalogueEntry{Name: "Legal"})
+	require.NoError(t, err)
+	require.NoError(t, repo.DeleteDepartment(ctx, "Legal"))
+
+	_, err = repo.CreateEmployee(ctx, &Employee{FirstName: "John", Email: "johndoe@example.com", Department: "Legal"})
+	require.ErrorIs(t, err, ErrDepartmentNotFound)
+	require.ErrorIs(t, err, ErrValidation)
+
+	_, err = repo.CreateEmployees(ctx, []*Employee{{FirstName: "John", Email: "johndoe@example.com", Role: "Lawyer"}})
+	require.ErrorIs(t, err, ErrRoleNotFound)
+
+	employee, err := repo.CreateEmployee(ctx, &Employee{FirstName: "John", Email: "johndoe@example.com"})
+	require.NoError(t, err)
+
+	employee.Department = "Legal"
+	_, err = repo.UpdateEmployee(ctx, employee)
+	require.ErrorIs(t, err, ErrDepartmentNotFound)
+
+	_, err = repo.ApplyBatch(ctx, []*BatchOperation{
+		{Op: BatchOpUpdate, ID: employee.ID, Update: EmployeeUpdate{FirstName: "John", Email: employee.Email, Role: "Lawyer"}},
+	}, false)
+	require.ErrorIs(t, err, ErrRoleNotFound)
+}
@@ -0,0 +1,155 @@
+package repos
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// EncryptionKeySize is the size in bytes of the key of a FieldCipher (AES-256)
+	EncryptionKeySize = 32
+
+	// Encrypted values are stored as the prefix followed by the base64 of the nonce and ciphertext
+	encryptedPrefix = "enc:"
+)
+
+var (
+	ErrInvalidKey        = fmt.Errorf("encryption key must be %d bytes", EncryptionKeySize)
+	ErrInvalidCiphertext = errors.New("invalid ciphertext")
+)
+
+// FieldCipher encrypts the PII fields of the stored employees with AES-256-GCM
+// A nil FieldCipher stores the fields as they are
+type FieldCipher struct {
+	aead cipher.AEAD
+	// Key of the HMAC used to index encrypted values that have to be looked up, e.g. emails
+	indexKey []byte
+}
+
+// NewFieldCipher creates a cipher from a 32 bytes key
+func NewFieldCipher(key []byte) (*FieldCipher, error) {
+	if len(key) != EncryptionKeySize {
+		return nil, ErrInvalidKey
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// Derive a separate key for the index so the encryption key isn't used twice
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("employeeapi index key"))
+
+	return &FieldCipher{aead: aead, indexKey: mac.Sum(nil)}, nil
+}
+
+// NewFieldCipherFromBase64 creates a cipher from a base64 encoded 32 bytes key
+func NewFieldCipherFromBase64(key string) (*FieldCipher, error) {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidKey, err)
+	}
+
+	return NewFieldCipher(raw)
+}
+
+// Encrypt encrypts a value with a random nonce so equal values don't look alike
+// Empty values are left empty
+func (f *FieldCipher) Encrypt(value string) string {
+	if f == nil || value == "" {
+		return value
+	}
+
+	nonce := make([]byte, f.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		// crypto/rand doesn't fail on the supported platforms
+		panic(err)
+	}
+
+	sealed := f.aead.Seal(nonce, nonce, []byte(value), nil)
+
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed)
+}
+
+// Decrypt decrypts a value encrypted by Encrypt
+// Values that aren't encrypted are returned as they are so existing data can be read
+func (f *FieldCipher) Decrypt(value string) (string, error) {
+	if f == nil || !strings.HasPrefix(value, encryptedPrefix) {
+		return value, nil
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil || len(sealed) < f.aead.NonceSize() {
+		return "", ErrInvalidCiphertext
+	}
+
+	nonce, ciphertext := sealed[:f.aead.NonceSize()], sealed[f.aead.NonceSize():]
+
+	plaintext, err := f.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", errors.Join(ErrInvalidCiphertext, err)
+	}
+
+	return string(plaintext), nil
+}
+
+// Index returns a keyed hash of a value so it can be looked up without being stored in clear
+func (f *FieldCipher) Index(value string) string {
+	if f == nil {
+		return value
+	}
+
+	mac := hmac.New(sha256.New, f.indexKey)
+	mac.Write([]byte(value))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Returns a copy of an employee with its PII encrypted, as it's stored
+func (e *employeeRepo) seal(emp *Employee) *Employee {
+	sealed := *emp
+	sealed.DateOfBirth = e.cipher.Encrypt(emp.DateOfBirth)
+	sealed.Email = e.cipher.Encrypt(emp.Email)
+
+	return &sealed
+}
+
+// Returns a copy of a stored employee with its PII decrypted
+func (e *employeeRepo) open(emp *Employee) *Employee {
+	opened := *emp
+	opened.DateOfBirth = e.decrypt(emp.DateOfBirth)
+	opened.Email = e.decrypt(emp.Email)
+
+	return &opened
+}
+
+// Decrypts a stored value
+// Values that can't be decrypted are returned empty rather than leaking the ciphertext
+func (e *employeeRepo) decrypt(value string) string {
+	plaintext, err := e.cipher.Decrypt(value)
+	if err != nil {
+		l := e.logger.With().Str("package", packageName).Str("func", "decrypt").Logger()
+		l.Error().Err(err).Msg("failed to decrypt stored value")
+	}
+
+	return plaintext
+}
+
+// Returns the key of an email in the email index, hashed when the emails are encrypted
+func (e *employeeRepo) emailIndexKey(email string) string {
+	return e.cipher.Index(emailKey(email))
+}
@@ -0,0 +1,147 @@
+package repos
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+var testEncryptionKey = bytes.Repeat([]byte{7}, EncryptionKeySize)
+
+// Tests the FieldCipher
+func TestFieldCipher(t *testing.T) {
+	cipher, err := NewFieldCipher(testEncryptionKey)
+	require.NoError(t, err)
+
+	encrypted := cipher.Encrypt("john.doe@example.com")
+	require.True(t, strings.HasPrefix(encrypted, encryptedPrefix))
+	require.NotContains(t, encrypted, "john.doe")
+
+	// The same value doesn't encrypt the same twice
+	require.NotEqual(t, encrypted, cipher.Encrypt("john.doe@example.com"))
+
+	decrypted, err := cipher.Decrypt(encrypted)
+	require.NoError(t, err)
+	require.Equal(t, "john.doe@example.com", decrypted)
+
+	// Values stored before encryption was enabled are read as they are
+	decrypted, err = cipher.Decrypt("1985-05-15")
+	require.NoError(t, err)
+	require.Equal(t, "1985-05-15", decrypted)
+
+	require.Empty(t, cipher.Encrypt(""))
+	require.Equal(t, cipher.Index("a@example.com"), cipher.Index("a@example.com"))
+	require.NotEqual(t, "a@example.com", cipher.Index("a@example.com"))
+
+	// Values encrypted with another key can't be read
+	other, err := NewFieldCipherFromBase64(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{8}, EncryptionKeySize)))
+	require.NoError(t, err)
+
+	_, err = other.Decrypt(encrypted)
+	require.ErrorIs(t, err, ErrInvalidCiphertext)
+
+	_, err = NewFieldCipher([]byte("too short"))
+	require.ErrorIs(t, err, ErrInvalidKey)
+
+	_, err = NewFieldCipherFromBase64("not base64!")
+	require.ErrorIs(t, err, ErrInvalidKey)
+}
+
+// Tests that the repository stores the PII encrypted and returns it decrypted
+func TestEncryptedEmployeeRepo(t *testing.T) {
+	logger := zerolog.New(os.Stdout)
+
+	cipher, err := NewFieldCipher(testEncryptionKey)
+	require.NoError(t, err)
+
+	existing := &Employee{
+		ID:          uuid.New().String(),
+		FirstName:   "John",
+		LastName:    "Doe",
+		DateOfBirth: "1985-05-15",
+		Email:       "john.doe@example.com",
+		IsActive:    true,
+	}
+
+	data := map[string]*Employee{existing.ID: existing}
+
+	repo := NewEncryptedEmployeeRepo(logger, &data, cipher)
+	ctx := context.Background()
+
+	// Existing records are encrypted when the repository is created
+	require.NotEqual(t, "1985-05-15", data[existing.ID].DateOfBirth)
+	require.NotEqual(t, "john.doe@example.com", data[existing.ID].Email)
+
+	emp, err := repo.GetEmployee(ctx, existing.ID, QueryOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "1985-05-15", emp.DateOfBirth)
+	require.Equal(t, "john.doe@example.com", emp.Email)
+
+	created, err := repo.CreateEmployee(ctx, &Employee{
+		FirstName:   "Jane",
+		LastName:    "Smith",
+		DateOfBirth: "1990-09-22",
+		Email:       "jane.smith@example.com",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "jane.smith@example.com", created.Email)
+	require.True(t, strings.HasPrefix(data[created.ID].Email, encryptedPrefix))
+	require.True(t, strings.HasPrefix(data[created.ID].DateOfBirth, encryptedPrefix))
+
+	// Emails are still unique even though they're stored encrypted
+	_, err = repo.CreateEmployee(ctx, &Employee{FirstName: "Jane", LastName: "Doe", Email: "JANE.SMITH@example.com"})
+	require.ErrorIs(t, err, ErrConflict)
+
+	created.Email = "jane.doe@example.com"
+	updated, err := repo.UpdateEmployee(ctx, created)
+	require.NoError(t, err)
+	require.Equal(t, "jane.doe@example.com", updated.Email)
+	require.NotContains(t, data[created.ID].Email, "jane")
+
+	emps, err := repo.GetEmployees(ctx, QueryOptions{})
+	require.NoError(t, err)
+	require.Len(t, emps, 2)
+	require.Equal(t, "john.doe@example.com", emps[0].Email)
+	require.Equal(t, "jane.doe@example.com", emps[1].Email)
+
+	// The audit trail keeps the PII encrypted too
+	stored := repo.(*employeeRepo).auditLog[created.ID]
+	require.Len(t, stored, 2)
+	require.Equal(t, "email", stored[1].Changes[0].Field)
+	require.True(t, strings.HasPrefix(stored[1].Changes[0].After.(string), encryptedPrefix))
+
+	history, err := repo.GetEmployeeHistory(ctx, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, []FieldChange{{Field: "email", Before: "jane.smith@example.com", After: "jane.doe@example.com"}}, history[1].Changes)
+
+	require.NoError(t, repo.DeleteEmployee(ctx, created.ID))
+
+	restored, err := repo.RestoreEmployee(ctx, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, "1990-09-22", restored.DateOfBirth)
+	require.True(t, strings.HasPrefix(data[created.ID].DateOfBirth, encryptedPrefix))
+
+	// The search index keeps the email terms hashed; they're only matched exactly
+	index := repo.(*employeeRepo).searchIndex
+	require.NotContains(t, index.postings, "example")
+	require.Contains(t, index.postings, index.hashTerm("example"))
+
+	results, err := repo.SearchEmployees(ctx, "example", SearchOptions{})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	results, err = repo.SearchEmployees(ctx, "exampel", SearchOptions{})
+	require.NoError(t, err)
+	require.Empty(t, results)
+
+	results, err = repo.SearchEmployees(ctx, "example", SearchOptions{ExcludePII: true})
+	require.NoError(t, err)
+	require.Empty(t, results)
+}
@@ -0,0 +1,77 @@
+package repos
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kinds of the errors returned by the repositories, checked with errors.Is
+var (
+	// ErrNotFound is returned when a record doesn't exist
+	ErrNotFound = errors.New(RecordNotFound)
+	// ErrConflict is matched by the errors of writes conflicting with the stored records
+	ErrConflict = errors.New("record conflicts with the stored records")
+	// ErrValidation is matched by the errors of writes with invalid values
+	ErrValidation = errors.New("record is invalid")
+)
+
+// Errors returned by the repositories; each one also matches its kind with errors.Is
+var (
+	ErrNotDeleted         error = &kindError{msg: RecordNotDeleted, kind: ErrConflict}
+	ErrInUse              error = &kindError{msg: RecordInUse, kind: ErrConflict}
+	ErrManagerNotFound    error = &kindError{msg: ManagerNotFound, kind: ErrValidation}
+	ErrManagerCycle       error = &kindError{msg: ManagerCycle, kind: ErrConflict}
+	ErrDepartmentNotFound error = &kindError{msg: DepartmentNotFound, kind: ErrValidation}
+	ErrRoleNotFound       error = &kindError{msg: RoleNotFound, kind: ErrValidation}
+)
+
+// kindError is a sentinel error of one of the kinds above
+type kindError struct {
+	msg  string
+	kind error
+}
+
+func (k *kindError) Error() string {
+	return k.msg
+}
+
+func (k *kindError) Is(target error) bool {
+	return target == k.kind
+}
+
+// ConflictError is returned when a write would violate a unique constraint
+// It matches ErrConflict
+// The message only names the field: errors are logged and sent to the callers, and the value
+// may be PII, e.g. an email
+type ConflictError struct {
+	Field      string
+	Value      string
+	ConflictID string
+}
+
+func (c *ConflictError) Error() string {
+	return fmt.Sprintf("%s already in use", c.Field)
+}
+
+func (c *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// ValidationError is returned when a field of a record has an invalid value
+// It matches ErrValidation and wraps the reason the value is invalid
+type ValidationError struct {
+	Field string
+	Err   error
+}
+
+func (v *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %v", v.Field, v.Err)
+}
+
+func (v *ValidationError) Unwrap() error {
+	return v.Err
+}
+
+func (v *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
@@ -0,0 +1,69 @@
+package repos
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests that the repository errors match their kind
+func TestErrorKinds(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		kind error
+	}{
+		{
+			name: "Not deleted",
+			err:  ErrNotDeleted,
+			kind: ErrConflict,
+		},
+		{
+			name: "In use",
+			err:  ErrInUse,
+			kind: ErrConflict,
+		},
+		{
+			name: "Manager cycle",
+			err:  ErrManagerCycle,
+			kind: ErrConflict,
+		},
+		{
+			name: "Conflict",
+			err:  &ConflictError{Field: "email", Value: "johndoe@example.com", ConflictID: "1"},
+			kind: ErrConflict,
+		},
+		{
+			name: "Conflict - wrapped by bulk error",
+			err:  &BulkError{Index: 2, Err: &ConflictError{Field: "email"}},
+			kind: ErrConflict,
+		},
+		{
+			name: "Validation",
+			err:  &ValidationError{Field: "manager_id", Err: ErrManagerNotFound},
+			kind: ErrValidation,
+		},
+	}
+
+	kinds := []error{ErrNotFound, ErrConflict, ErrValidation}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, kind := range kinds {
+				require.Equal(t, kind == tc.kind, errors.Is(tc.err, kind))
+			}
+		})
+	}
+
+	// A validation error still matches the reason it wraps
+	require.ErrorIs(t, &ValidationError{Field: "manager_id", Err: ErrManagerNotFound}, ErrManagerNotFound)
+}
+
+// Tests the message of a conflict leaves the conflicting value out
+func TestConflictErrorMessage(t *testing.T) {
+	err := &ConflictError{Field: "email", Value: "johndoe@example.com", ConflictID: "1"}
+
+	require.Equal(t, "email already in use", err.Error())
+	require.NotContains(t, (&BulkError{Index: 2, Err: err}).Error(), err.Value)
+}
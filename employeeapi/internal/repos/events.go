@@ -0,0 +1,56 @@
+package repos
+
+import (
+	"context"
+	"time"
+)
+
+// Event describes a write to an employee, published to the listeners of the repository
+type Event struct {
+	Action    string
+	Actor     string
+	Timestamp time.Time
+	// Before is nil when the employee is created and After is nil when it's purged
+	Before *Employee
+	After  *Employee
+}
+
+// Listener is notified of every write to an employee
+// Listeners are called while the repository is locked so they must not block
+// nor call back into the repository
+type Listener func(event Event)
+
+// AddListener registers a listener notified after every write to an employee
+func (e *employeeRepo) AddListener(listener Listener) {
+	// Ensure listeners aren't added while an event is being published
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.listeners = append(e.listeners, listener)
+}
+
+// Notifies the listeners of a write with copies of the records
+// The caller must hold the write lock
+func (e *employeeRepo) publish(ctx context.Context, action string, before, after *Employee) {
+	if len(e.listeners) == 0 {
+		return
+	}
+
+	event := Event{
+		Action:    action,
+		Actor:     ActorFromContext(ctx),
+		Timestamp: time.Now().UTC(),
+	}
+	if before != nil {
+		beforeCopy := *before
+		event.Before = &beforeCopy
+	}
+	if after != nil {
+		afterCopy := *after
+		event.After = &afterCopy
+	}
+
+	for _, listener := range e.listeners {
+		listener(event)
+	}
+}
@@ -0,0 +1,48 @@
+package repos
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests that listeners are notified of employee writes with copies of the records
+func TestAddListener(t *testing.T) {
+	logger := zerolog.New(os.Stdout)
+
+	repo := NewEmployeeRepo(logger, nil)
+	ctx := ContextWithActor(context.Background(), "hr.admin")
+
+	events := make([]Event, 0)
+	repo.AddListener(func(event Event) {
+		events = append(events, event)
+	})
+
+	emp, err := repo.CreateEmployee(ctx, &Employee{FirstName: "John", LastName: "Doe", Email: "johndoe@example.com", IsActive: true})
+	require.NoError(t, err)
+
+	updated := *emp
+	updated.IsActive = false
+	_, err = repo.UpdateEmployee(ctx, &updated)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.DeleteEmployee(ctx, emp.ID))
+
+	require.Len(t, events, 3)
+
+	require.Equal(t, AuditActionCreate, events[0].Action)
+	require.Equal(t, "hr.admin", events[0].Actor)
+	require.Nil(t, events[0].Before)
+	require.Equal(t, emp.ID, events[0].After.ID)
+	require.NotSame(t, emp, events[0].After)
+
+	require.Equal(t, AuditActionUpdate, events[1].Action)
+	require.True(t, events[1].Before.IsActive)
+	require.False(t, events[1].After.IsActive)
+
+	require.Equal(t, AuditActionDelete, events[2].Action)
+	require.NotNil(t, events[2].After.DeletedAt)
+}
@@ -0,0 +1,35 @@
+package repos
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrUnavailable means the repository can't serve requests
+var ErrUnavailable = errors.New("repository is unavailable")
+
+// Health checks the repository can serve requests
+// The stored records are read under the lock so a repository stuck in a write is reported
+// once ctx is done
+func (e *employeeRepo) Health(ctx context.Context) error {
+	done := make(chan error, 1)
+
+	go func() {
+		e.mu.RLock()
+		defer e.mu.RUnlock()
+
+		if e.empData == nil || e.searchIndex == nil {
+			done <- ErrUnavailable
+			return
+		}
+
+		done <- nil
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return errors.Join(ErrUnavailable, ctx.Err())
+	}
+}
@@ -0,0 +1,32 @@
+package repos
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests that the repository is healthy unless it can't be read in time
+func TestHealth(t *testing.T) {
+	logger := zerolog.New(os.Stdout)
+
+	repo := NewEmployeeRepo(logger, nil)
+
+	require.NoError(t, repo.Health(context.Background()))
+
+	// A write holding the lock keeps the repository from being read
+	r := repo.(*employeeRepo)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := repo.Health(ctx)
+	require.ErrorIs(t, err, ErrUnavailable)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,204 @@
+package repos
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	OperationResultOK    = "ok"
+	OperationResultError = "error"
+)
+
+// Operations counts the repository operations by operation (method name) and result (ok or error)
+var Operations = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "employeeapi",
+	Subsystem: "repo",
+	Name:      "operations_total",
+	Help:      "Number of employee repository operations by operation and result.",
+}, []string{"operation", "result"})
+
+// instrumentedEmployeeRepo counts the operations of the wrapped repository
+type instrumentedEmployeeRepo struct {
+	EmployeeRepo
+}
+
+// NewInstrumentedEmployeeRepo wraps a repository so its operations are counted in Operations
+func NewInstrumentedEmployeeRepo(repo EmployeeRepo) EmployeeRepo {
+	return &instrumentedEmployeeRepo{EmployeeRepo: repo}
+}
+
+// Counts an operation by its result
+func observe(operation string, err error) {
+	result := OperationResultOK
+	if err != nil {
+		result = OperationResultError
+	}
+
+	Operations.WithLabelValues(operation, result).Inc()
+}
+
+func (r *instrumentedEmployeeRepo) GetEmployee(ctx context.Context, id string, opts QueryOptions) (*Employee, error) {
+	emp, err := r.EmployeeRepo.GetEmployee(ctx, id, opts)
+	observe("GetEmployee", err)
+	return emp, err
+}
+
+func (r *instrumentedEmployeeRepo) GetEmployees(ctx context.Context, opts QueryOptions) ([]*Employee, error) {
+	emps, err := r.EmployeeRepo.GetEmployees(ctx, opts)
+	observe("GetEmployees", err)
+	return emps, err
+}
+
+func (r *instrumentedEmployeeRepo) CreateEmployee(ctx context.Context, emp *Employee) (*Employee, error) {
+	created, err := r.EmployeeRepo.CreateEmployee(ctx, emp)
+	observe("CreateEmployee", err)
+	return created, err
+}
+
+func (r *instrumentedEmployeeRepo) CreateEmployees(ctx context.Context, emps []*Employee) ([]*Employee, error) {
+	created, err := r.EmployeeRepo.CreateEmployees(ctx, emps)
+	observe("CreateEmployees", err)
+	return created, err
+}
+
+func (r *instrumentedEmployeeRepo) ApplyBatch(ctx context.Context, ops []*BatchOperation, dryRun bool) ([]*Employee, error) {
+	results, err := r.EmployeeRepo.ApplyBatch(ctx, ops, dryRun)
+	observe("ApplyBatch", err)
+	return results, err
+}
+
+func (r *instrumentedEmployeeRepo) DeleteEmployee(ctx context.Context, id string) error {
+	err := r.EmployeeRepo.DeleteEmployee(ctx, id)
+	observe("DeleteEmployee", err)
+	return err
+}
+
+func (r *instrumentedEmployeeRepo) UpdateEmployee(ctx context.Context, emp *Employee) (*Employee, error) {
+	updated, err := r.EmployeeRepo.UpdateEmployee(ctx, emp)
+	observe("UpdateEmployee", err)
+	return updated, err
+}
+
+func (r *instrumentedEmployeeRepo) RestoreEmployee(ctx context.Context, id string) (*Employee, error) {
+	emp, err := r.EmployeeRepo.RestoreEmployee(ctx, id)
+	observe("RestoreEmployee", err)
+	return emp, err
+}
+
+func (r *instrumentedEmployeeRepo) PurgeEmployees(ctx context.Context, deletedBefore time.Time) ([]string, error) {
+	ids, err := r.EmployeeRepo.PurgeEmployees(ctx, deletedBefore)
+	observe("PurgeEmployees", err)
+	return ids, err
+}
+
+func (r *instrumentedEmployeeRepo) GetEmployeeHistory(ctx context.Context, id string) ([]*AuditEntry, error) {
+	entries, err := r.EmployeeRepo.GetEmployeeHistory(ctx, id)
+	observe("GetEmployeeHistory", err)
+	return entries, err
+}
+
+func (r *instrumentedEmployeeRepo) GetEmployeeTimeline(ctx context.Context, id string) ([]*Period, error) {
+	periods, err := r.EmployeeRepo.GetEmployeeTimeline(ctx, id)
+	observe("GetEmployeeTimeline", err)
+	return periods, err
+}
+
+func (r *instrumentedEmployeeRepo) FindDuplicateEmployees(ctx context.Context) ([][]*Employee, error) {
+	groups, err := r.EmployeeRepo.FindDuplicateEmployees(ctx)
+	observe("FindDuplicateEmployees", err)
+	return groups, err
+}
+
+func (r *instrumentedEmployeeRepo) GetReports(ctx context.Context, id string) ([]*Employee, []*Employee, error) {
+	direct, transitive, err := r.EmployeeRepo.GetReports(ctx, id)
+	observe("GetReports", err)
+	return direct, transitive, err
+}
+
+func (r *instrumentedEmployeeRepo) GetManagementChain(ctx context.Context, id string) ([]*Employee, error) {
+	chain, err := r.EmployeeRepo.GetManagementChain(ctx, id)
+	observe("GetManagementChain", err)
+	return chain, err
+}
+
+func (r *instrumentedEmployeeRepo) GetOrgChart(ctx context.Context) ([]*OrgNode, error) {
+	chart, err := r.EmployeeRepo.GetOrgChart(ctx)
+	observe("GetOrgChart", err)
+	return chart, err
+}
+
+func (r *instrumentedEmployeeRepo) SearchEmployees(ctx context.Context, query string, opts SearchOptions) ([]*SearchResult, error) {
+	results, err := r.EmployeeRepo.SearchEmployees(ctx, query, opts)
+	observe("SearchEmployees", err)
+	return results, err
+}
+
+func (r *instrumentedEmployeeRepo) GetDepartments(ctx context.Context) ([]*CatalogueEntry, error) {
+	entries, err := r.EmployeeRepo.GetDepartments(ctx)
+	observe("GetDepartments", err)
+	return entries, err
+}
+
+func (r *instrumentedEmployeeRepo) GetDepartment(ctx context.Context, name string) (*CatalogueEntry, error) {
+	entry, err := r.EmployeeRepo.GetDepartment(ctx, name)
+	observe("GetDepartment", err)
+	return entry, err
+}
+
+func (r *instrumentedEmployeeRepo) CreateDepartment(ctx context.Context, dept *CatalogueEntry) (*CatalogueEntry, error) {
+	entry, err := r.EmployeeRepo.CreateDepartment(ctx, dept)
+	observe("CreateDepartment", err)
+	return entry, err
+}
+
+func (r *instrumentedEmployeeRepo) UpdateDepartment(ctx context.Context, dept *CatalogueEntry) (*CatalogueEntry, error) {
+	entry, err := r.EmployeeRepo.UpdateDepartment(ctx, dept)
+	observe("UpdateDepartment", err)
+	return entry, err
+}
+
+func (r *instrumentedEmployeeRepo) DeleteDepartment(ctx context.Context, name string) error {
+	err := r.EmployeeRepo.DeleteDepartment(ctx, name)
+	observe("DeleteDepartment", err)
+	return err
+}
+
+func (r *instrumentedEmployeeRepo) GetRoles(ctx context.Context) ([]*CatalogueEntry, error) {
+	entries, err := r.EmployeeRepo.GetRoles(ctx)
+	observe("GetRoles", err)
+	return entries, err
+}
+
+func (r *instrumentedEmployeeRepo) GetRole(ctx context.Context, name string) (*CatalogueEntry, error) {
+	entry, err := r.EmployeeRepo.GetRole(ctx, name)
+	observe("GetRole", err)
+	return entry, err
+}
+
+func (r *instrumentedEmployeeRepo) CreateRole(ctx context.Context, role *CatalogueEntry) (*CatalogueEntry, error) {
+	entry, err := r.EmployeeRepo.CreateRole(ctx, role)
+	observe("CreateRole", err)
+	return entry, err
+}
+
+func (r *instrumentedEmployeeRepo) UpdateRole(ctx context.Context, role *CatalogueEntry) (*CatalogueEntry, error) {
+	entry, err := r.EmployeeRepo.UpdateRole(ctx, role)
+	observe("UpdateRole", err)
+	return entry, err
+}
+
+func (r *instrumentedEmployeeRepo) DeleteRole(ctx context.Context, name string) error {
+	err := r.EmployeeRepo.DeleteRole(ctx, name)
+	observe("DeleteRole", err)
+	return err
+}
+
+func (r *instrumentedEmployeeRepo) Health(ctx context.Context) error {
+	err := r.EmployeeRepo.Health(ctx)
+	observe("Health", err)
+	return err
+}
@@ -0,0 +1,36 @@
+package repos
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests that the operations of the instrumented repository are counted by result
+func TestInstrumentedEmployeeRepo(t *testing.T) {
+	logger := zerolog.New(os.Stdout)
+
+	repo := NewInstrumentedEmployeeRepo(NewEmployeeRepo(logger, nil))
+	ctx := context.Background()
+
+	created := testutil.ToFloat64(Operations.WithLabelValues("CreateEmployee", OperationResultOK))
+	found := testutil.ToFloat64(Operations.WithLabelValues("GetEmployee", OperationResultOK))
+	notFound := testutil.ToFloat64(Operations.WithLabelValues("GetEmployee", OperationResultError))
+
+	emp, err := repo.CreateEmployee(ctx, &Employee{FirstName: "John", LastName: "Doe", Email: "johndoe@example.com"})
+	require.NoError(t, err)
+
+	_, err = repo.GetEmployee(ctx, emp.ID, QueryOptions{})
+	require.NoError(t, err)
+
+	_, err = repo.GetEmployee(ctx, "missing", QueryOptions{})
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.Equal(t, created+1, testutil.ToFloat64(Operations.WithLabelValues("CreateEmployee", OperationResultOK)))
+	require.Equal(t, found+1, testutil.ToFloat64(Operations.WithLabelValues("GetEmployee", OperationResultOK)))
+	require.Equal(t, notFound+1, testutil.ToFloat64(Operations.WithLabelValues("GetEmployee", OperationResultError)))
+}
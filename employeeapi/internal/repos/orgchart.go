@@ -0,0 +1,234 @@
+package repos
+
+import (
+	"context"
+	"sort"
+)
+
+const (
+	ManagerNotFound = "manager not found"
+	ManagerCycle    = "manager assignment would create a cycle"
+)
+
+// OrgNode is an employee with the employees reporting to them
+type OrgNode struct {
+	Employee *Employee
+	Reports  []*OrgNode
+}
+
+// GetReports gets the employees reporting to an employee,
+// directly and transitively (all levels below, including the direct reports)
+func (e *employeeRepo) GetReports(ctx context.Context, id string) ([]*Employee, []*Employee, error) {
+	l := requestLogger(ctx, e.logger).With().Str("package", packageName).Str("func", "GetReports").Logger()
+
+	// Ensure we read once it's safe to do so
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if emp, ok := e.empData[id]; !ok || emp.DeletedAt != nil {
+		err := ErrNotFound
+
+		l.Error().Err(err).Msg("failed to get reports")
+
+		return nil, nil, err
+	}
+
+	reports := e.reportsByManager()
+
+	direct := e.openEmployees(reports[id])
+	transitive := make([]*Employee, 0)
+
+	// Walk down breadth first, guarding against cycles in the existing data
+	visited := map[string]bool{id: true}
+	queue := []string{id}
+	for len(queue) > 0 {
+		managerID := queue[0]
+		queue = queue[1:]
+
+		for _, report := range reports[managerID] {
+			if visited[report.ID] {
+				continue
+			}
+			visited[report.ID] = true
+
+			transitive = append(transitive, e.open(report))
+			queue = append(queue, report.ID)
+		}
+	}
+
+	return direct, transitive, nil
+}
+
+// GetManagementChain gets the managers of an employee, from the direct manager to the top
+func (e *employeeRepo) GetManagementChain(ctx context.Context, id string) ([]*Employee, error) {
+	l := requestLogger(ctx, e.logger).With().Str("package", packageName).Str("func", "GetManagementChain").Logger()
+
+	// Ensure we read once it's safe to do so
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	emp, ok := e.empData[id]
+	if !ok || emp.DeletedAt != nil {
+		err := ErrNotFound
+
+		l.Error().Err(err).Msg("failed to get management chain")
+
+		return nil, err
+	}
+
+	chain := make([]*Employee, 0)
+
+	visited := map[string]bool{id: true}
+	for manager := e.activeManager(emp); manager != nil && !visited[manager.ID]; manager = e.activeManager(manager) {
+		visited[manager.ID] = true
+
+		chain = append(chain, e.open(manager))
+	}
+
+	return chain, nil
+}
+
+// GetOrgChart gets the whole organisation as trees
+// The roots are the employees without a manager (or whose manager is deleted)
+func (e *employeeRepo) GetOrgChart(ctx context.Context) ([]*OrgNode, error) {
+	// Ensure we read once it's safe to do so
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	reports := e.reportsByManager()
+
+	roots := make([]*Employee, 0)
+	for _, emp := range e.empData {
+		if emp.DeletedAt == nil && e.activeManager(emp) == nil {
+			roots = append(roots, emp)
+		}
+	}
+	sortEmployees(roots)
+
+	visited := make(map[string]bool)
+
+	var buildNode func(emp *Employee) *OrgNode
+	buildNode = func(emp *Employee) *OrgNode {
+		visited[emp.ID] = true
+
+		node := &OrgNode{
+			Employee: e.open(emp),
+			Reports:  make([]*OrgNode, 0),
+		}
+
+		for _, report := range reports[emp.ID] {
+			if !visited[report.ID] {
+				node.Reports = append(node.Reports, buildNode(report))
+			}
+		}
+
+		return node
+	}
+
+	chart := make([]*OrgNode, 0, len(roots))
+	for _, root := range roots {
+		chart = append(chart, buildNode(root))
+	}
+
+	return chart, nil
+}
+
+// Checks that managerID can be assigned as the manager of employee id
+// The caller must hold the lock
+func (e *employeeRepo) checkManager(id, managerID string) error {
+	if managerID == "" {
+		return nil
+	}
+
+	if managerID == id {
+		return ErrManagerCycle
+	}
+
+	manager, ok := e.empData[managerID]
+	if !ok || manager.DeletedAt != nil {
+		return &ValidationError{Field: "manager_id", Err: ErrManagerNotFound}
+	}
+
+	if e.managesIndirectly(id, managerID) {
+		return ErrManagerCycle
+	}
+
+	return nil
+}
+
+// Reports whether employee id is in the management chain above managerID
+// Deleted employees are walked too: they keep their manager and can be restored
+// The caller must hold the lock
+func (e *employeeRepo) managesIndirectly(id, managerID string) bool {
+	visited := make(map[string]bool)
+	for next := managerID; next != "" && !visited[next]; {
+		if next == id {
+			return true
+		}
+		visited[next] = true
+
+		manager, ok := e.empData[next]
+		if !ok {
+			return false
+		}
+		next = manager.ManagerID
+	}
+
+	return false
+}
+
+// Returns the manager of an employee if it exists and isn't deleted
+// The caller must hold the lock
+func (e *employeeRepo) activeManager(emp *Employee) *Employee {
+	if emp.ManagerID == "" {
+		return nil
+	}
+
+	manager, ok := e.empData[emp.ManagerID]
+	if !ok || manager.DeletedAt != nil {
+		return nil
+	}
+
+	return manager
+}
+
+// Returns the employees that are not deleted grouped by manager id
+// The caller must hold the lock
+func (e *employeeRepo) reportsByManager() map[string][]*Employee {
+	reports := make(map[string][]*Employee)
+
+	for _, emp := range e.empData {
+		if emp.DeletedAt == nil && emp.ManagerID != "" {
+			reports[emp.ManagerID] = append(reports[emp.ManagerID], emp)
+		}
+	}
+
+	for _, group := range reports {
+		sortEmployees(group)
+	}
+
+	return reports
+}
+
+// Sorts employees by name so listings are stable
+func sortEmployees(emps []*Employee) {
+	sort.Slice(emps, func(i, j int) bool {
+		if emps[i].LastName != emps[j].LastName {
+			return emps[i].LastName < emps[j].LastName
+		}
+		if emps[i].FirstName != emps[j].FirstName {
+			return emps[i].FirstName < emps[j].FirstName
+		}
+		return emps[i].ID < emps[j].ID
+	})
+}
+
+// Returns decrypted copies of stored employees
+func (e *employeeRepo) openEmployees(emps []*Employee) []*Employee {
+	copies := make([]*Employee, 0, len(emps))
+	for _, emp := range emps {
+		copies = append(copies, e.open(emp))
+	}
+
+	return copies
+}
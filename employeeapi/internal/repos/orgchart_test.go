@@ -0,0 +1,173 @@
+package repos
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// Builds the org below, returning the repo and its data
+//
+//	ceo
+//	├── cto
+//	│   └── dev
+//	└── cfo
+func mockOrgRepo() (EmployeeRepo, map[string]*Employee) {
+	data := map[string]*Employee{
+		"ceo": {ID: "ceo", FirstName: "Alice", LastName: "Adams", Email: "ceo@example.com"},
+		"cto": {ID: "cto", FirstName: "Bob", LastName: "Brown", Email: "cto@example.com", ManagerID: "ceo"},
+		"cfo": {ID: "cfo", FirstName: "Carol", LastName: "Clark", Email: "cfo@example.com", ManagerID: "ceo"},
+		"dev": {ID: "dev", FirstName: "Dave", LastName: "Davis", Email: "dev@example.com", ManagerID: "cto"},
+	}
+
+	return NewEmployeeRepo(zerolog.New(os.Stdout), &data), data
+}
+
+// Tests manager assignment on create and update
+func TestManagerAssignment(t *testing.T) {
+	testCases := []struct {
+		name      string
+		id        string
+		managerID string
+		err       error
+	}{
+		{
+			name:      "Successful - Assign Manager",
+			id:        "cfo",
+			managerID: "cto",
+		},
+		{
+			name:      "Successful - Remove Manager",
+			id:        "cto",
+			managerID: "",
+		},
+		{
+			name:      "Failed - Assign Manager - self",
+			id:        "cto",
+			managerID: "cto",
+			err:       ErrManagerCycle,
+		},
+		{
+			name:      "Failed - Assign Manager - report of the employee",
+			id:        "ceo",
+			managerID: "dev",
+			err:       ErrManagerCycle,
+		},
+		{
+			name:      "Failed - Assign Manager - not found",
+			id:        "dev",
+			managerID: "123",
+			err:       ErrManagerNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, data := mockOrgRepo()
+
+			update := *data[tc.id]
+			update.ManagerID = tc.managerID
+
+			_, err := repo.UpdateEmployee(context.Background(), &update)
+
+			require.ErrorIs(t, err, tc.err)
+
+			if tc.err == nil {
+				require.Equal(t, tc.managerID, data[tc.id].ManagerID)
+			}
+		})
+	}
+
+	repo, _ := mockOrgRepo()
+
+	_, err := repo.CreateEmployee(context.Background(), &Employee{FirstName: "Eve", Email: "eve@example.com", ManagerID: "123"})
+	require.ErrorIs(t, err, ErrManagerNotFound)
+
+	_, err = repo.CreateEmployee(context.Background(), &Employee{FirstName: "Eve", Email: "eve@example.com", ManagerID: "dev"})
+	require.NoError(t, err)
+}
+
+// Tests that a deleted manager doesn't hide a management cycle
+func TestManagerCycleThroughDeleted(t *testing.T) {
+	ctx := context.Background()
+	repo, data := mockOrgRepo()
+
+	// dev reports to ceo through cto, which is deleted but can be restored
+	require.NoError(t, repo.DeleteEmployee(ctx, "cto"))
+
+	update := *data["ceo"]
+	update.ManagerID = "dev"
+
+	_, err := repo.UpdateEmployee(ctx, &update)
+	require.ErrorIs(t, err, ErrManagerCycle)
+
+	// A cycle stored before deleted managers were checked is caught on restore
+	repo.(*employeeRepo).empData["ceo"].ManagerID = "dev"
+
+	_, err = repo.RestoreEmployee(ctx, "cto")
+	require.ErrorIs(t, err, ErrManagerCycle)
+
+	// The rejected restore leaves the chart intact
+	chart, err := repo.GetOrgChart(ctx)
+	require.NoError(t, err)
+	require.Len(t, chart, 1)
+	require.Equal(t, "dev", chart[0].Employee.ID)
+}
+
+// Tests the GetReports method
+func TestGetReports(t *testing.T) {
+	repo, _ := mockOrgRepo()
+
+	direct, transitive, err := repo.GetReports(context.Background(), "ceo")
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"cto", "cfo"}, employeeIDs(direct))
+	require.Equal(t, []string{"cto", "cfo", "dev"}, employeeIDs(transitive))
+
+	_, _, err = repo.GetReports(context.Background(), "123")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+// Tests the GetManagementChain method
+func TestGetManagementChain(t *testing.T) {
+	repo, _ := mockOrgRepo()
+
+	chain, err := repo.GetManagementChain(context.Background(), "dev")
+	require.NoError(t, err)
+	require.Equal(t, []string{"cto", "ceo"}, employeeIDs(chain))
+
+	// A deleted manager ends the chain
+	require.NoError(t, repo.DeleteEmployee(context.Background(), "ceo"))
+
+	chain, err = repo.GetManagementChain(context.Background(), "dev")
+	require.NoError(t, err)
+	require.Equal(t, []string{"cto"}, employeeIDs(chain))
+}
+
+// Tests the GetOrgChart method
+func TestGetOrgChart(t *testing.T) {
+	repo, _ := mockOrgRepo()
+
+	chart, err := repo.GetOrgChart(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, chart, 1)
+	require.Equal(t, "ceo", chart[0].Employee.ID)
+	require.Len(t, chart[0].Reports, 2)
+	require.Equal(t, "cto", chart[0].Reports[0].Employee.ID)
+	require.Equal(t, "dev", chart[0].Reports[0].Reports[0].Employee.ID)
+	require.Equal(t, "cfo", chart[0].Reports[1].Employee.ID)
+	require.Empty(t, chart[0].Reports[1].Reports)
+}
+
+func employeeIDs(emps []*Employee) []string {
+	ids := make([]string, 0, len(emps))
+	for _, emp := range emps {
+		ids = append(ids, emp.ID)
+	}
+
+	return ids
+}
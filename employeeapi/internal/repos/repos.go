@@ -1,47 +1,166 @@
 package repos
 
 import (
-	"errors"
+	"context"
 	"sync"
+	"time"
 
-	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 )
 
 const (
-	packageName    = "repos"
-	RecordNotFound = "record not found"
+	packageName      = "repos"
+	RecordNotFound   = "record not found"
+	RecordNotDeleted = "record is not deleted"
 )
 
 type Employee struct {
-	ID          string `json:"id"`
-	FirstName   string `json:"first_name"`
-	LastName    string `json:"last_name"`
-	DateOfBirth string `json:"dob"`
-	Email       string `json:"email"`
-	IsActive    bool   `json:"is_active"`
-	Department  string `json:"department"`
-	Role        string `json:"role"`
+	ID          string     `json:"id"`
+	FirstName   string     `json:"first_name"`
+	LastName    string     `json:"last_name"`
+	DateOfBirth string     `json:"dob"`
+	Email       string     `json:"email"`
+	IsActive    bool       `json:"is_active"`
+	Department  string     `json:"department"`
+	Role        string     `json:"role"`
+	ManagerID   string     `json:"manager_id"`
+	HireDate    string     `json:"hire_date"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+// EmployeeUpdate holds the fields of an update to an employee
+// The department, role and hire date are kept if empty and the manager if ManagerID is nil;
+// an empty ManagerID removes the manager
+type EmployeeUpdate struct {
+	FirstName   string
+	LastName    string
+	DateOfBirth string
+	Email       string
+	IsActive    bool
+	Department  string
+	Role        string
+	HireDate    string
+	ManagerID   *string
+}
+
+// MergeEmployee applies an update to an existing employee, see EmployeeUpdate
+// The REST and gRPC updates share it so they keep the same fields
+func MergeEmployee(existing *Employee, update EmployeeUpdate) {
+	existing.FirstName = update.FirstName
+	existing.LastName = update.LastName
+	existing.DateOfBirth = update.DateOfBirth
+	existing.Email = update.Email
+	existing.IsActive = update.IsActive
+
+	if update.Department != "" {
+		existing.Department = update.Department
+	}
+
+	if update.Role != "" {
+		existing.Role = update.Role
+	}
+
+	if update.HireDate != "" {
+		existing.HireDate = update.HireDate
+	}
+
+	if update.ManagerID != nil {
+		existing.ManagerID = *update.ManagerID
+	}
+}
+
+// QueryOptions controls which records are returned by the read methods
+type QueryOptions struct {
+	IncludeDeleted bool
+	// AsOf returns the employees as they were at a past time; the current ones if zero
+	// Only the department, role and active status are kept over time, see Period
+	AsOf time.Time
+}
+
+// SearchOptions controls how employees are searched
+type SearchOptions struct {
+	// Limit caps the number of results; no cap if 0
+	Limit int
+	// ExcludePII ignores the terms of PII fields, e.g. for callers that can't see the email, so
+	// a match doesn't confirm an address exists
+	ExcludePII bool
 }
 
 type EmployeeRepo interface {
-	GetEmployee(id string) (*Employee, error)
-	GetEmployees() ([]*Employee, error)
-	CreateEmployee(emp *Employee) (*Employee, error)
-	DeleteEmployee(id string) error
-	UpdateEmployee(emp *Employee) (*Employee, error)
+	GetEmployee(ctx context.Context, id string, opts QueryOptions) (*Employee, error)
+	GetEmployees(ctx context.Context, opts QueryOptions) ([]*Employee, error)
+	CreateEmployee(ctx context.Context, emp *Employee) (*Employee, error)
+	CreateEmployees(ctx context.Context, emps []*Employee) ([]*Employee, error)
+	ApplyBatch(ctx context.Context, ops []*BatchOperation, dryRun bool) ([]*Employee, error)
+	DeleteEmployee(ctx context.Context, id string) error
+	UpdateEmployee(ctx context.Context, emp *Employee) (*Employee, error)
+	RestoreEmployee(ctx context.Context, id string) (*Employee, error)
+	PurgeEmployees(ctx context.Context, deletedBefore time.Time) ([]string, error)
+	GetEmployeeHistory(ctx context.Context, id string) ([]*AuditEntry, error)
+	GetEmployeeTimeline(ctx context.Context, id string) ([]*Period, error)
+	FindDuplicateEmployees(ctx context.Context) ([][]*Employee, error)
+	GetReports(ctx context.Context, id string) ([]*Employee, []*Employee, error)
+	GetManagementChain(ctx context.Context, id string) ([]*Employee, error)
+	GetOrgChart(ctx context.Context) ([]*OrgNode, error)
+	SearchEmployees(ctx context.Context, query string, opts SearchOptions) ([]*SearchResult, error)
+	AddListener(listener Listener)
+	Health(ctx context.Context) error
+
+	CatalogueRepo
+}
+
+type requestIDCtxKey struct{}
+
+// ContextWithRequestID returns a copy of ctx that carries the id of the request calling the
+// repository; it's added to the repository logs
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDCtxKey{}, id)
+}
+
+// Returns the logger with the id of the request carried by ctx, if any
+func requestLogger(ctx context.Context, logger zerolog.Logger) zerolog.Logger {
+	if ctx != nil {
+		if id, ok := ctx.Value(requestIDCtxKey{}).(string); ok && id != "" {
+			return logger.With().Str("request_id", id).Logger()
+		}
+	}
+
+	return logger
 }
 
 type employeeRepo struct {
-	logger  zerolog.Logger
-	empData map[string]*Employee
-	mu      sync.RWMutex
+	logger      zerolog.Logger
+	empData     map[string]*Employee
+	auditLog    map[string][]*AuditEntry
+	periods     map[string][]*Period // department, role and active status periods by employee id, oldest first
+	emailIndex  map[string]string    // lowercased (and hashed if encrypted) email -> employee id
+	cipher      *FieldCipher         // encrypts the PII of the stored records; nil stores them in clear
+	departments *catalogue
+	roles       *catalogue
+	searchIndex *searchIndex
+	listeners   []Listener
+	mu          sync.RWMutex
 }
 
-// NewEmployeeRepo creates a new employee repository
+// NewEmployeeRepo creates a new employee repository storing the records in clear
 func NewEmployeeRepo(logger zerolog.Logger, empData *map[string]*Employee) EmployeeRepo {
+	return NewEncryptedEmployeeRepo(logger, empData, nil)
+}
+
+// NewEncryptedEmployeeRepo creates a new employee repository encrypting the date of birth
+// and email of the stored records with cipher; empData is encrypted in place
+func NewEncryptedEmployeeRepo(logger zerolog.Logger, empData *map[string]*Employee, cipher *FieldCipher) EmployeeRepo {
 	repo := &employeeRepo{
-		logger: logger,
+		logger:   logger,
+		auditLog: make(map[string][]*AuditEntry),
+		periods:  make(map[string][]*Period),
+		cipher:   cipher,
+		departments: newCatalogue("department", DepartmentUnassigned, DefaultDepartments, ErrDepartmentNotFound, func(emp *Employee, name string) bool {
+			return emp.Department == name
+		}),
+		roles: newCatalogue("role", RoleUnassigned, DefaultRoles, ErrRoleNotFound, func(emp *Employee, name string) bool {
+			return emp.Role == name
+		}),
 	}
 
 	if empData != nil {
@@ -50,97 +169,242 @@ func NewEmployeeRepo(logger zerolog.Logger, empData *map[string]*Employee) Emplo
 		repo.empData = make(map[string]*Employee)
 	}
 
+	if cipher != nil {
+		for id, emp := range repo.empData {
+			repo.empData[id] = repo.seal(repo.open(emp))
+		}
+	}
+
+	repo.buildEmailIndex()
+	repo.buildPeriods()
+
+	repo.searchIndex = newSearchIndex(cipher)
+	for _, emp := range repo.empData {
+		if emp.DeletedAt == nil {
+			repo.searchIndex.add(repo.open(emp))
+		}
+	}
+
 	return repo
 }
 
 // GetEmployee gets an employee by id
-func (e *employeeRepo) GetEmployee(id string) (*Employee, error) {
+// Deleted employees are only returned if opts.IncludeDeleted is set
+func (e *employeeRepo) GetEmployee(ctx context.Context, id string, opts QueryOptions) (*Employee, error) {
 	// Ensure we read once it's safe to do so
 	e.mu.RLock()
 	defer e.mu.RUnlock()
 
-	if emp, ok := e.empData[id]; ok {
+	// Return a copy so callers can't modify the stored record without going through the repo
+	if emp, ok := e.query(e.empData[id], opts); ok {
 		return emp, nil
 	}
 
-	err := errors.New(RecordNotFound)
+	err := ErrNotFound
 
-	e.logger.Error().Err(err).Msg("failed to get employee")
+	l := requestLogger(ctx, e.logger)
+	l.Error().Err(err).Msg("failed to get employee")
 
 	return nil, err
 }
 
 // UpdateEmployee updates an employee
-func (e *employeeRepo) UpdateEmployee(emp *Employee) (*Employee, error) {
-	l := e.logger.With().Str("package", packageName).Str("func", "UpdateEmployee").Logger()
+// Returns a ConflictError if the email is already used by another employee,
+// a ValidationError if the manager, department or role doesn't exist and ErrManagerCycle if the employee
+// would end up managing itself
+func (e *employeeRepo) UpdateEmployee(ctx context.Context, emp *Employee) (*Employee, error) {
+	l := requestLogger(ctx, e.logger).With().Str("package", packageName).Str("func", "UpdateEmployee").Logger()
 
 	// Ensure only one write at a time
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	// Update the employee once found
-	for _, empData := range e.empData {
-		if empData.ID == emp.ID {
-			empData.FirstName = emp.FirstName
-			empData.LastName = emp.LastName
-			empData.DateOfBirth = emp.DateOfBirth
-			empData.Email = emp.Email
-			empData.IsActive = emp.IsActive
-			empData.Department = emp.Department
-			empData.Role = emp.Role
-
-			return empData, nil
-		}
+	write, err := e.stageUpdate(emp)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to update employee")
+		return nil, err
 	}
 
-	// If we get here, the employee was not found
-	err := errors.New(RecordNotFound)
-
-	l.Error().Err(err).Msg("failed to update employee")
+	e.onWrite(ctx, write.action, write.before, write.after)
 
-	return nil, err
+	return write.after, nil
 }
 
-func (e *employeeRepo) GetEmployees() ([]*Employee, error) {
+// GetEmployees gets all employees sorted by name
+// Deleted employees are only returned if opts.IncludeDeleted is set
+func (e *employeeRepo) GetEmployees(ctx context.Context, opts QueryOptions) ([]*Employee, error) {
 	// Ensure we read once it's safe to do so
 	e.mu.RLock()
 	defer e.mu.RUnlock()
 
 	employees := make([]*Employee, 0)
 
-	for _, emp := range e.empData {
-		employees = append(employees, emp)
+	for _, stored := range e.empData {
+		if emp, ok := e.query(stored, opts); ok {
+			employees = append(employees, emp)
+		}
 	}
 
+	// Map iteration order is random so sort to keep listings stable
+	sortEmployees(employees)
+
 	return employees, nil
 }
 
-func (e *employeeRepo) CreateEmployee(emp *Employee) (*Employee, error) {
+// CreateEmployee creates an employee
+// Returns a ConflictError if the email is already used by another employee
+// and a ValidationError if the manager, department or role doesn't exist
+func (e *employeeRepo) CreateEmployee(ctx context.Context, emp *Employee) (*Employee, error) {
+	l := requestLogger(ctx, e.logger).With().Str("package", packageName).Str("func", "CreateEmployee").Logger()
+
 	// Ensure only one write at a time
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	emp.ID = uuid.New().String()
-	e.empData[emp.ID] = emp
+	write, err := e.stageCreate(emp)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to create employee")
+		return nil, err
+	}
+
+	e.onWrite(ctx, write.action, write.before, write.after)
 
 	return emp, nil
 }
 
-func (e *employeeRepo) DeleteEmployee(id string) error {
-	l := e.logger.With().Str("package", packageName).Str("func", "DeleteEmployee").Logger()
+// DeleteEmployee soft deletes an employee by marking it as deleted
+// The record is kept until it's purged
+func (e *employeeRepo) DeleteEmployee(ctx context.Context, id string) error {
+	l := requestLogger(ctx, e.logger).With().Str("package", packageName).Str("func", "DeleteEmployee").Logger()
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	// Delete the employee if found
-	if _, ok := e.empData[id]; ok {
+	write, err := e.stageDelete(id)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to delete employee")
+		return err
+	}
+
+	e.onWrite(ctx, write.action, write.before, write.after)
+
+	return nil
+}
+
+// RestoreEmployee restores a soft deleted employee
+// Returns a ConflictError if its email was taken by another employee in the meantime, and
+// ErrManagerCycle if restoring it would close a management cycle
+func (e *employeeRepo) RestoreEmployee(ctx context.Context, id string) (*Employee, error) {
+	l := requestLogger(ctx, e.logger).With().Str("package", packageName).Str("func", "RestoreEmployee").Logger()
+
+	// Ensure only one write at a time
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	stored, ok := e.empData[id]
+	if !ok {
+		err := ErrNotFound
+
+		l.Error().Err(err).Msg("failed to restore employee")
+
+		return nil, err
+	}
+
+	emp := e.open(stored)
+
+	if emp.DeletedAt == nil {
+		err := ErrNotDeleted
+
+		l.Error().Err(err).Msg("failed to restore employee")
+
+		return nil, err
+	}
+
+	// The email may have been taken while the employee was deleted
+	if err := e.checkEmailAvailable(emp.ID, emp.Email); err != nil {
+		l.Error().Err(err).Msg("failed to restore employee")
+		return nil, err
+	}
+
+	// Its manager may have been moved below it in the meantime
+	if e.managesIndirectly(emp.ID, emp.ManagerID) {
+		err := ErrManagerCycle
+
+		l.Error().Err(err).Msg("failed to restore employee")
+
+		return nil, err
+	}
+
+	before := *emp
+	emp.DeletedAt = nil
+
+	e.empData[id] = e.seal(emp)
+	e.emailIndex[e.emailIndexKey(emp.Email)] = emp.ID
+
+	e.onWrite(ctx, AuditActionRestore, &before, emp)
+
+	return emp, nil
+}
+
+// PurgeEmployees permanently removes the employees deleted before the given time
+// Returns the ids of the purged employees
+func (e *employeeRepo) PurgeEmployees(ctx context.Context, deletedBefore time.Time) ([]string, error) {
+	// Ensure only one write at a time
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	purged := make([]string, 0)
+
+	for id, emp := range e.empData {
+		if emp.DeletedAt == nil || !emp.DeletedAt.Before(deletedBefore) {
+			continue
+		}
+
 		delete(e.empData, id)
-		return nil
+
+		// The audit trail is kept after purging for compliance
+		e.onWrite(ctx, AuditActionPurge, e.open(emp), nil)
+
+		purged = append(purged, id)
 	}
 
-	// If we get here, the employee was not found
-	err := errors.New(RecordNotFound)
+	return purged, nil
+}
+
+// Returns a decrypted copy of a stored employee if it matches opts, as it was at opts.AsOf if set
+// The caller must hold the lock
+func (e *employeeRepo) query(stored *Employee, opts QueryOptions) (*Employee, bool) {
+	if stored == nil {
+		return nil, false
+	}
+
+	emp := e.open(stored)
+	if !opts.AsOf.IsZero() {
+		var ok bool
+		if emp, ok = e.asOf(emp, opts.AsOf); !ok {
+			return nil, false
+		}
+	}
 
-	l.Error().Err(err).Msg("failed to delete employee")
+	if emp.DeletedAt != nil && !opts.IncludeDeleted {
+		return nil, false
+	}
+
+	return emp, true
+}
+
+// Runs after every write to an employee: records it in the audit trail and the periods,
+// keeps the search index in sync and notifies the listeners
+// before and after are decrypted records
+// The caller must hold the write lock
+func (e *employeeRepo) onWrite(ctx context.Context, action string, before, after *Employee) {
+	e.recordAudit(ctx, action, before, after)
+	e.recordPeriod(before, after, time.Now().UTC())
+
+	if after != nil && after.DeletedAt == nil {
+		e.searchIndex.add(after)
+	} else if before != nil {
+		e.searchIndex.remove(before.ID)
+	}
 
-	return err
+	e.publish(ctx, action, before, after)
 }
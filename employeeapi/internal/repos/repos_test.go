@@ -1,9 +1,10 @@
 package repos
 
 import (
-	"errors"
+	"context"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
@@ -45,15 +46,15 @@ func TestGetEmployee(t *testing.T) {
 		},
 		{
 			name: "Failed - Get Employee",
-			err:  errors.New(RecordNotFound),
+			err:  ErrNotFound,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			employee, err := repo.GetEmployee(tc.id)
+			employee, err := repo.GetEmployee(context.Background(), tc.id, QueryOptions{})
 
-			require.Equal(t, tc.err, err)
+			require.ErrorIs(t, err, tc.err)
 			require.Equal(t, tc.employee, employee)
 		})
 	}
@@ -107,7 +108,7 @@ func TestGetEmployees(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			employees, err := repo.GetEmployees()
+			employees, err := repo.GetEmployees(context.Background(), QueryOptions{})
 
 			require.Equal(t, tc.employees, employees)
 			require.Nil(t, err)
@@ -150,7 +151,7 @@ func TestCreateEmployee(t *testing.T) {
 	for _, tc := range testCases {
 
 		t.Run(tc.name, func(t *testing.T) {
-			employee, err := repo.CreateEmployee(tc.employee)
+			employee, err := repo.CreateEmployee(context.Background(), tc.employee)
 
 			require.Equal(t, tc.employee, employee)
 			require.Nil(t, err)
@@ -193,19 +194,24 @@ func TestDeleteEmployee(t *testing.T) {
 		{
 			name: "Failed - Delete Employee",
 			id:   "123",
-			err:  errors.New(RecordNotFound),
+			err:  ErrNotFound,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			err := repo.DeleteEmployee(tc.id)
+			err := repo.DeleteEmployee(context.Background(), tc.id)
 
-			require.Equal(t, tc.err, err)
+			require.ErrorIs(t, err, tc.err)
 
+			// The record is kept but marked as deleted
 			if tc.err == nil {
-				_, ok := data[tc.id]
-				require.False(t, ok)
+				emp, ok := data[tc.id]
+				require.True(t, ok)
+				require.NotNil(t, emp.DeletedAt)
+
+				_, err := repo.GetEmployee(context.Background(), tc.id, QueryOptions{})
+				require.ErrorIs(t, err, ErrNotFound)
 			}
 		})
 	}
@@ -264,15 +270,15 @@ func TestUpdateEmployee(t *testing.T) {
 				Department:  "Marketing",
 				Role:        "Marketing Specialist",
 			},
-			err: errors.New(RecordNotFound),
+			err: ErrNotFound,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			employee, err := repo.UpdateEmployee(tc.employee)
+			employee, err := repo.UpdateEmployee(context.Background(), tc.employee)
 
-			require.Equal(t, tc.err, err)
+			require.ErrorIs(t, err, tc.err)
 
 			if tc.err == nil {
 				require.Equal(t, tc.employee, employee)
@@ -281,3 +287,104 @@ func TestUpdateEmployee(t *testing.T) {
 		})
 	}
 }
+
+// Tests the RestoreEmployee method
+func TestRestoreEmployee(t *testing.T) {
+
+	logger := zerolog.New(os.Stdout)
+
+	data := make(map[string]*Employee)
+
+	deletedAt := time.Now().UTC()
+
+	deleted := &Employee{
+		ID:        uuid.New().String(),
+		FirstName: "John",
+		LastName:  "Doe",
+		Email:     "johndoe@example.com",
+		DeletedAt: &deletedAt,
+	}
+
+	active := &Employee{
+		ID:        uuid.New().String(),
+		FirstName: "Jane",
+		LastName:  "Smith",
+		Email:     "janesmith@example.com",
+	}
+
+	data[deleted.ID] = deleted
+	data[active.ID] = active
+
+	repo := NewEmployeeRepo(logger, &data)
+
+	testCases := []struct {
+		name string
+		id   string
+		err  error
+	}{
+		{
+			name: "Successful - Restore Employee",
+			id:   deleted.ID,
+			err:  nil,
+		},
+		{
+			name: "Failed - Restore Employee - not deleted",
+			id:   active.ID,
+			err:  ErrNotDeleted,
+		},
+		{
+			name: "Failed - Restore Employee - not found",
+			id:   "123",
+			err:  ErrNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			employee, err := repo.RestoreEmployee(context.Background(), tc.id)
+
+			require.ErrorIs(t, err, tc.err)
+
+			if tc.err == nil {
+				require.Nil(t, employee.DeletedAt)
+				require.Nil(t, data[tc.id].DeletedAt)
+			}
+		})
+	}
+}
+
+// Tests the PurgeEmployees method
+func TestPurgeEmployees(t *testing.T) {
+
+	logger := zerolog.New(os.Stdout)
+
+	data := make(map[string]*Employee)
+
+	now := time.Now().UTC()
+	deletedLongAgo := now.Add(-48 * time.Hour)
+	deletedRecently := now.Add(-time.Hour)
+
+	old := &Employee{ID: uuid.New().String(), DeletedAt: &deletedLongAgo}
+	recent := &Employee{ID: uuid.New().String(), DeletedAt: &deletedRecently}
+	active := &Employee{ID: uuid.New().String()}
+
+	data[old.ID] = old
+	data[recent.ID] = recent
+	data[active.ID] = active
+
+	repo := NewEmployeeRepo(logger, &data)
+
+	ids, err := repo.PurgeEmployees(context.Background(), now.Add(-24*time.Hour))
+
+	require.Nil(t, err)
+	require.Equal(t, []string{old.ID}, ids)
+
+	_, ok := data[old.ID]
+	require.False(t, ok)
+
+	_, ok = data[recent.ID]
+	require.True(t, ok)
+
+	_, ok = data[active.ID]
+	require.True(t, ok)
+}
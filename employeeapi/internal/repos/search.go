@@ -0,0 +1,325 @@
+package repos
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// Scores of a query term matching an indexed term, before field weights
+	exactMatchScore  = 1.0
+	prefixMatchScore = 0.75
+	fuzzyMatchScore  = 0.5
+
+	// Prefix of the hashed terms of PII; it can't start a term made by tokenize
+	hashedTermPrefix = "#"
+)
+
+// Weights of the indexed fields; a match on a name ranks higher than one on a role
+// The terms of PII fields are hashed when the PII is encrypted so they're only matched exactly
+var searchFieldWeights = []struct {
+	weight float64
+	pii    bool
+	value  func(emp *Employee) string
+}{
+	{3, false, func(emp *Employee) string { return emp.FirstName }},
+	{3, false, func(emp *Employee) string { return emp.LastName }},
+	{2, true, func(emp *Employee) string { return emp.Email }},
+	{1, false, func(emp *Employee) string { return emp.Department }},
+	{1, false, func(emp *Employee) string { return emp.Role }},
+}
+
+// SearchResult is an employee matching a search with its relevance score
+type SearchResult struct {
+	Employee *Employee
+	Score    float64
+}
+
+// Weights of a term in an employee
+type termWeights struct {
+	// Weight of the best field the term appears in
+	best float64
+	// Weight of the best field that isn't PII the term appears in; 0 if it's only in PII fields
+	public float64
+}
+
+// Returns the weight of the term, ignoring the PII fields if excludePII is set
+func (w termWeights) weight(excludePII bool) float64 {
+	if excludePII {
+		return w.public
+	}
+
+	return w.best
+}
+
+// searchIndex is an inverted index of the terms of employees that are not deleted
+type searchIndex struct {
+	// term -> employee id -> weights of the fields the term appears in
+	postings map[string]map[string]termWeights
+	// trigram -> terms containing it, used to find fuzzy match candidates
+	trigrams map[string]map[string]struct{}
+	// employee id -> indexed terms, used to remove an employee
+	terms map[string][]string
+	// hashes the terms of PII fields so the index doesn't keep them in clear; nil keeps them as they are
+	cipher *FieldCipher
+}
+
+func newSearchIndex(cipher *FieldCipher) *searchIndex {
+	return &searchIndex{
+		postings: make(map[string]map[string]termWeights),
+		trigrams: make(map[string]map[string]struct{}),
+		terms:    make(map[string][]string),
+		cipher:   cipher,
+	}
+}
+
+// Indexes an employee, replacing what was indexed for it before
+func (s *searchIndex) add(emp *Employee) {
+	s.remove(emp.ID)
+
+	weights := make(map[string]termWeights)
+	for _, field := range searchFieldWeights {
+		for _, term := range tokenize(field.value(emp)) {
+			if field.pii && s.cipher != nil {
+				term = s.hashTerm(term)
+			}
+
+			w := weights[term]
+			w.best = max(w.best, field.weight)
+			if !field.pii {
+				w.public = max(w.public, field.weight)
+			}
+			weights[term] = w
+		}
+	}
+
+	for term, weight := range weights {
+		if _, ok := s.postings[term]; !ok {
+			s.postings[term] = make(map[string]termWeights)
+
+			for _, trigram := range termTrigrams(term) {
+				if _, ok := s.trigrams[trigram]; !ok {
+					s.trigrams[trigram] = make(map[string]struct{})
+				}
+				s.trigrams[trigram][term] = struct{}{}
+			}
+		}
+
+		s.postings[term][emp.ID] = weight
+		s.terms[emp.ID] = append(s.terms[emp.ID], term)
+	}
+}
+
+// Removes an employee from the index
+func (s *searchIndex) remove(id string) {
+	for _, term := range s.terms[id] {
+		delete(s.postings[term], id)
+
+		// Drop terms no employee uses anymore
+		if len(s.postings[term]) == 0 {
+			delete(s.postings, term)
+
+			for _, trigram := range termTrigrams(term) {
+				delete(s.trigrams[trigram], term)
+				if len(s.trigrams[trigram]) == 0 {
+					delete(s.trigrams, trigram)
+				}
+			}
+		}
+	}
+
+	delete(s.terms, id)
+}
+
+// Returns the ids of the employees matching the query with their scores
+// Each query term adds the score of its best match in the employee; the matches in PII fields are
+// ignored if excludePII is set
+func (s *searchIndex) search(query string, excludePII bool) map[string]float64 {
+	scores := make(map[string]float64)
+
+	for _, queryTerm := range tokenize(query) {
+		best := make(map[string]float64)
+
+		for term, score := range s.matchingTerms(queryTerm) {
+			for id, weights := range s.postings[term] {
+				weight := weights.weight(excludePII)
+				if weight > 0 && score*weight > best[id] {
+					best[id] = score * weight
+				}
+			}
+		}
+
+		for id, score := range best {
+			scores[id] += score
+		}
+	}
+
+	return scores
+}
+
+// Returns the indexed terms matching a query term: exactly, by prefix or within a few typos
+func (s *searchIndex) matchingTerms(queryTerm string) map[string]float64 {
+	matches := make(map[string]float64)
+
+	if _, ok := s.postings[queryTerm]; ok {
+		matches[queryTerm] = exactMatchScore
+	}
+
+	if s.cipher != nil {
+		if hashed := s.hashTerm(queryTerm); s.postings[hashed] != nil {
+			matches[hashed] = exactMatchScore
+		}
+	}
+
+	// Candidates share at least one trigram with the query term
+	candidates := make(map[string]struct{})
+	for _, trigram := range trigrams(queryTerm) {
+		for term := range s.trigrams[trigram] {
+			candidates[term] = struct{}{}
+		}
+	}
+
+	maxEdits := maxEditDistance(queryTerm)
+
+	for term := range candidates {
+		if term == queryTerm {
+			continue
+		}
+
+		if len(queryTerm) >= 2 && strings.HasPrefix(term, queryTerm) {
+			matches[term] = prefixMatchScore
+			continue
+		}
+
+		if maxEdits == 0 {
+			continue
+		}
+
+		distance := editDistance(queryTerm, term)
+		if distance <= maxEdits {
+			longest := max(len([]rune(queryTerm)), len([]rune(term)))
+			matches[term] = fuzzyMatchScore * (1 - float64(distance)/float64(longest))
+		}
+	}
+
+	return matches
+}
+
+// SearchEmployees finds employees by name, email, department or role,
+// tolerating partial and misspelled terms, most relevant first
+// Email terms are only matched exactly when the PII is encrypted, and not at all with opts.ExcludePII
+func (e *employeeRepo) SearchEmployees(ctx context.Context, query string, opts SearchOptions) ([]*SearchResult, error) {
+	// Ensure we read once it's safe to do so
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	results := make([]*SearchResult, 0)
+	for id, score := range e.searchIndex.search(query, opts.ExcludePII) {
+		emp, ok := e.empData[id]
+		if !ok {
+			continue
+		}
+
+		results = append(results, &SearchResult{Employee: e.open(emp), Score: score})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		a, b := results[i].Employee, results[j].Employee
+		if a.LastName != b.LastName {
+			return a.LastName < b.LastName
+		}
+		if a.FirstName != b.FirstName {
+			return a.FirstName < b.FirstName
+		}
+		return a.ID < b.ID
+	})
+
+	if opts.Limit > 0 && len(results) > opts.Limit {
+		results = results[:opts.Limit]
+	}
+
+	return results, nil
+}
+
+// Splits text into lowercase terms made of letters and digits
+// e.g. "john.doe@example.com" is split into john, doe, example and com
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Returns the hashed form of a term of a PII field
+func (s *searchIndex) hashTerm(term string) string {
+	return hashedTermPrefix + s.cipher.Index(term)
+}
+
+// Returns the trigrams of an indexed term; hashed terms have none since they can't be matched fuzzily
+func termTrigrams(term string) []string {
+	if strings.HasPrefix(term, hashedTermPrefix) {
+		return nil
+	}
+
+	return trigrams(term)
+}
+
+// Returns the trigrams of a term padded with $ so short terms have trigrams too
+func trigrams(term string) []string {
+	runes := []rune("$" + term + "$")
+
+	result := make([]string, 0, len(runes))
+	for i := 0; i+3 <= len(runes); i++ {
+		result = append(result, string(runes[i:i+3]))
+	}
+
+	return result
+}
+
+// Number of typos tolerated in a query term; short terms must match exactly
+func maxEditDistance(term string) int {
+	switch n := len([]rune(term)); {
+	case n <= 3:
+		return 0
+	case n <= 6:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Edit distance between two terms where swapping two adjacent letters counts as one typo
+// (optimal string alignment distance)
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// d[i][j] is the distance between the first i runes of a and the first j runes of b
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(ra)][len(rb)]
+}
@@ -0,0 +1,131 @@
+package repos
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests the SearchEmployees method
+func TestSearchEmployees(t *testing.T) {
+	data := map[string]*Employee{
+		"1": {ID: "1", FirstName: "Jonathan", LastName: "Doe", Email: "jonathan.doe@example.com", Department: "Engineering", Role: "Software Developer"},
+		"2": {ID: "2", FirstName: "Jane", LastName: "Smith", Email: "jane.smith@example.com", Department: "Marketing", Role: "Marketing Specialist"},
+		"3": {ID: "3", FirstName: "Robert", LastName: "Johnson", Email: "robert.johnson@example.com", Department: "Finance", Role: "Financial Analyst"},
+		"4": {ID: "4", FirstName: "Emily", LastName: "Smithers", Email: "emily.smithers@example.com", Department: "Engineering", Role: "Software Developer"},
+	}
+
+	repo := NewEmployeeRepo(zerolog.New(os.Stdout), &data)
+
+	testCases := []struct {
+		name  string
+		query string
+		opts  SearchOptions
+		ids   []string
+	}{
+		{
+			name:  "Successful - Search Employees - exact",
+			query: "Smith",
+			ids:   []string{"2", "4"},
+		},
+		{
+			name:  "Successful - Search Employees - partial",
+			query: "jona",
+			ids:   []string{"1"},
+		},
+		{
+			name:  "Successful - Search Employees - misspelled",
+			query: "Jonhson",
+			ids:   []string{"3"},
+		},
+		{
+			name:  "Successful - Search Employees - multiple terms",
+			query: "emily engineering",
+			ids:   []string{"4", "1"},
+		},
+		{
+			name:  "Successful - Search Employees - email",
+			query: "jane.smith@example.com",
+			ids:   []string{"2", "4", "1", "3"},
+		},
+		{
+			name:  "Successful - Search Employees - limit",
+			query: "developer",
+			opts:  SearchOptions{Limit: 1},
+			ids:   []string{"1"},
+		},
+		{
+			name:  "Successful - Search Employees - email without PII",
+			query: "jane.smith@example.com",
+			opts:  SearchOptions{ExcludePII: true},
+			ids:   []string{"2", "4"},
+		},
+		{
+			name:  "Successful - Search Employees - email only without PII",
+			query: "example",
+			opts:  SearchOptions{ExcludePII: true},
+			ids:   []string{},
+		},
+		{
+			name:  "Successful - Search Employees - no match",
+			query: "xyz",
+			ids:   []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			results, err := repo.SearchEmployees(context.Background(), tc.query, tc.opts)
+			require.NoError(t, err)
+
+			ids := make([]string, 0)
+			for _, result := range results {
+				ids = append(ids, result.Employee.ID)
+			}
+
+			require.Equal(t, tc.ids, ids)
+		})
+	}
+}
+
+// Tests that the search index follows the repo writes
+func TestSearchIndexSync(t *testing.T) {
+	repo := NewEmployeeRepo(zerolog.New(os.Stdout), nil)
+	ctx := context.Background()
+
+	search := func(query string) int {
+		results, err := repo.SearchEmployees(ctx, query, SearchOptions{})
+		require.NoError(t, err)
+		return len(results)
+	}
+
+	emp, err := repo.CreateEmployee(ctx, &Employee{FirstName: "Jonathan", LastName: "Doe", Email: "jd@example.com"})
+	require.NoError(t, err)
+	require.Equal(t, 1, search("jonathan"))
+
+	update := *emp
+	update.FirstName = "Johnny"
+	_, err = repo.UpdateEmployee(ctx, &update)
+	require.NoError(t, err)
+	require.Equal(t, 0, search("jonathan"))
+	require.Equal(t, 1, search("johnny"))
+
+	require.NoError(t, repo.DeleteEmployee(ctx, emp.ID))
+	require.Equal(t, 0, search("johnny"))
+
+	_, err = repo.RestoreEmployee(ctx, emp.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1, search("johnny"))
+}
+
+// Tests the editDistance function
+func TestEditDistance(t *testing.T) {
+	require.Equal(t, 0, editDistance("smith", "smith"))
+	require.Equal(t, 1, editDistance("smith", "smyth"))
+	require.Equal(t, 1, editDistance("johnson", "jonhson"))
+	require.Equal(t, 2, editDistance("johnson", "jonsen"))
+	require.Equal(t, 3, editDistance("", "doe"))
+}
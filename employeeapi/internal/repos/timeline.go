@@ -0,0 +1,123 @@
+package repos
+
+import (
+	"context"
+	"time"
+)
+
+// Period is a department, role and active status assignment of an employee and when it was in effect
+type Period struct {
+	Department string `json:"department"`
+	Role       string `json:"role"`
+	IsActive   bool   `json:"is_active"`
+	// From is nil for the first period of the employees recorded before periods were kept
+	From *time.Time `json:"from"`
+	// To is nil for the current period
+	To *time.Time `json:"to"`
+}
+
+// Returns whether the period was in effect at t
+func (p *Period) inEffect(t time.Time) bool {
+	return (p.From == nil || !p.From.After(t)) && (p.To == nil || t.Before(*p.To))
+}
+
+// Returns whether the assignment of an employee differs from the period
+func (p *Period) differs(emp *Employee) bool {
+	return p.Department != emp.Department || p.Role != emp.Role || p.IsActive != emp.IsActive
+}
+
+// GetEmployeeTimeline gets the department, role and active status periods of an employee, oldest first
+// The periods of deleted employees are kept until they're purged
+func (e *employeeRepo) GetEmployeeTimeline(ctx context.Context, id string) ([]*Period, error) {
+	l := requestLogger(ctx, e.logger).With().Str("package", packageName).Str("func", "GetEmployeeTimeline").Logger()
+
+	// Ensure we read once it's safe to do so
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	periods, ok := e.periods[id]
+	if !ok {
+		err := ErrNotFound
+
+		l.Error().Err(err).Msg("failed to get employee timeline")
+
+		return nil, err
+	}
+
+	timeline := make([]*Period, 0, len(periods))
+	for _, period := range periods {
+		periodCopy := *period
+		timeline = append(timeline, &periodCopy)
+	}
+
+	return timeline, nil
+}
+
+// Starts the first period of the employees recorded without one, from an unknown time
+// The caller must hold the write lock
+func (e *employeeRepo) buildPeriods() {
+	for id, emp := range e.empData {
+		if _, ok := e.periods[id]; !ok {
+			e.periods[id] = []*Period{newPeriod(emp, nil)}
+		}
+	}
+}
+
+func newPeriod(emp *Employee, from *time.Time) *Period {
+	return &Period{
+		Department: emp.Department,
+		Role:       emp.Role,
+		IsActive:   emp.IsActive,
+		From:       from,
+	}
+}
+
+// Keeps the periods of an employee in sync after a write: a created employee starts its first period,
+// a change of department, role or active status ends the current period and starts a new one,
+// and purged employees lose their periods
+// The caller must hold the write lock
+func (e *employeeRepo) recordPeriod(before, after *Employee, at time.Time) {
+	switch {
+	case after == nil:
+		delete(e.periods, before.ID)
+	case before == nil:
+		e.periods[after.ID] = []*Period{newPeriod(after, &at)}
+	default:
+		periods := e.periods[after.ID]
+		if len(periods) == 0 {
+			e.periods[after.ID] = []*Period{newPeriod(after, &at)}
+			return
+		}
+
+		current := periods[len(periods)-1]
+		if !current.differs(after) {
+			return
+		}
+
+		current.To = &at
+		e.periods[after.ID] = append(periods, newPeriod(after, &at))
+	}
+}
+
+// Returns the employee as it was at t: its department, role and active status are the ones in effect then
+// Returns false if the employee didn't exist yet
+// The caller must hold the lock
+func (e *employeeRepo) asOf(emp *Employee, t time.Time) (*Employee, bool) {
+	for _, period := range e.periods[emp.ID] {
+		if period.inEffect(t) {
+			empAsOf := *emp
+			empAsOf.Department = period.Department
+			empAsOf.Role = period.Role
+			empAsOf.IsActive = period.IsActive
+
+			// The employee may have been deleted since
+			if emp.DeletedAt != nil && emp.DeletedAt.After(t) {
+				empAsOf.DeletedAt = nil
+			}
+
+			return &empAsOf, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,124 @@
+package repos
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests the periods kept by the writes and the AsOf queries
+func TestEmployeeTimeline(t *testing.T) {
+	logger := zerolog.New(os.Stdout)
+
+	existing := &Employee{
+		ID:          uuid.New().String(),
+		FirstName:   "John",
+		LastName:    "Doe",
+		DateOfBirth: "1985-05-15",
+		Email:       "johndoe@example.com",
+		IsActive:    true,
+		Department:  "Engineering",
+		Role:        "Software Developer",
+	}
+
+	data := map[string]*Employee{existing.ID: existing}
+
+	repo := NewEmployeeRepo(logger, &data)
+	ctx := context.Background()
+
+	// Existing employees have a single period from an unknown time
+	timeline, err := repo.GetEmployeeTimeline(ctx, existing.ID)
+	require.NoError(t, err)
+	require.Equal(t, []*Period{{Department: "Engineering", Role: "Software Developer", IsActive: true}}, timeline)
+
+	beforeCreate := time.Now().UTC()
+
+	created, err := repo.CreateEmployee(ctx, &Employee{
+		FirstName:   "Jane",
+		LastName:    "Smith",
+		DateOfBirth: "1990-09-22",
+		Email:       "jane.smith@example.com",
+		IsActive:    true,
+		Department:  "Marketing",
+		Role:        "Marketing Specialist",
+	})
+	require.NoError(t, err)
+
+	// Changing something else than the department, role or active status keeps the period
+	created.LastName = "Doe"
+	_, err = repo.UpdateEmployee(ctx, created)
+	require.NoError(t, err)
+
+	timeline, err = repo.GetEmployeeTimeline(ctx, created.ID)
+	require.NoError(t, err)
+	require.Len(t, timeline, 1)
+	require.NotNil(t, timeline[0].From)
+	require.Nil(t, timeline[0].To)
+
+	beforeUpdate := time.Now().UTC()
+
+	created.Department = "Finance"
+	created.Role = "Financial Analyst"
+	_, err = repo.UpdateEmployee(ctx, created)
+	require.NoError(t, err)
+
+	timeline, err = repo.GetEmployeeTimeline(ctx, created.ID)
+	require.NoError(t, err)
+	require.Len(t, timeline, 2)
+	require.Equal(t, "Marketing", timeline[0].Department)
+	require.Equal(t, timeline[0].To, timeline[1].From)
+	require.Equal(t, "Finance", timeline[1].Department)
+	require.Nil(t, timeline[1].To)
+
+	// Move the change back in time so the queries can tell the periods apart
+	lastYear := beforeUpdate.AddDate(-1, 0, 0)
+	repo.(*employeeRepo).periods[created.ID][0].From = &lastYear
+	changed := beforeUpdate.AddDate(0, -1, 0)
+	repo.(*employeeRepo).periods[created.ID][0].To = &changed
+	repo.(*employeeRepo).periods[created.ID][1].From = &changed
+
+	emp, err := repo.GetEmployee(ctx, created.ID, QueryOptions{AsOf: beforeUpdate.AddDate(0, -6, 0)})
+	require.NoError(t, err)
+	require.Equal(t, "Marketing", emp.Department)
+	require.Equal(t, "Marketing Specialist", emp.Role)
+	require.Equal(t, "Doe", emp.LastName)
+
+	emp, err = repo.GetEmployee(ctx, created.ID, QueryOptions{AsOf: beforeUpdate})
+	require.NoError(t, err)
+	require.Equal(t, "Finance", emp.Department)
+
+	// The employee didn't exist yet
+	_, err = repo.GetEmployee(ctx, created.ID, QueryOptions{AsOf: lastYear.AddDate(0, 0, -1)})
+	require.ErrorIs(t, err, ErrNotFound)
+
+	emps, err := repo.GetEmployees(ctx, QueryOptions{AsOf: lastYear.AddDate(0, 0, -1)})
+	require.NoError(t, err)
+	require.Len(t, emps, 1)
+	require.Equal(t, existing.ID, emps[0].ID)
+
+	// Deleted employees were still there before they were deleted
+	require.NoError(t, repo.DeleteEmployee(ctx, created.ID))
+
+	_, err = repo.GetEmployee(ctx, created.ID, QueryOptions{})
+	require.ErrorIs(t, err, ErrNotFound)
+
+	emp, err = repo.GetEmployee(ctx, created.ID, QueryOptions{AsOf: beforeCreate.AddDate(0, -6, 0)})
+	require.NoError(t, err)
+	require.Nil(t, emp.DeletedAt)
+
+	timeline, err = repo.GetEmployeeTimeline(ctx, created.ID)
+	require.NoError(t, err)
+	require.Len(t, timeline, 2)
+
+	// Purged employees lose their periods
+	_, err = repo.PurgeEmployees(ctx, time.Now().UTC().Add(time.Hour))
+	require.NoError(t, err)
+
+	_, err = repo.GetEmployeeTimeline(ctx, created.ID)
+	require.ErrorIs(t, err, ErrNotFound)
+}
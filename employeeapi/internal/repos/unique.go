@@ -0,0 +1,88 @@
+package repos
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// Returns the key used by the case-insensitive email index
+func emailKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// Builds the email index from the employees that are not deleted
+func (e *employeeRepo) buildEmailIndex() {
+	e.emailIndex = make(map[string]string)
+
+	for id, emp := range e.empData {
+		if emp.DeletedAt == nil {
+			e.emailIndex[e.emailIndexKey(e.open(emp).Email)] = id
+		}
+	}
+}
+
+// Checks that the email isn't used by an employee other than id
+// The caller must hold the lock
+func (e *employeeRepo) checkEmailAvailable(id, email string) error {
+	if ownerID, ok := e.emailIndex[e.emailIndexKey(email)]; ok && ownerID != id {
+		return &ConflictError{
+			Field:      "email",
+			Value:      email,
+			ConflictID: ownerID,
+		}
+	}
+
+	return nil
+}
+
+// FindDuplicateEmployees returns groups of employees that are likely the same person,
+// i.e. that share the same first name, last name and date of birth
+func (e *employeeRepo) FindDuplicateEmployees(ctx context.Context) ([][]*Employee, error) {
+	// Ensure we read once it's safe to do so
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	groups := make(map[string][]*Employee)
+
+	for _, stored := range e.empData {
+		if stored.DeletedAt != nil {
+			continue
+		}
+
+		emp := e.open(stored)
+
+		key := strings.Join([]string{
+			strings.ToLower(strings.TrimSpace(emp.FirstName)),
+			strings.ToLower(strings.TrimSpace(emp.LastName)),
+			emp.DateOfBirth,
+		}, "|")
+
+		groups[key] = append(groups[key], emp)
+	}
+
+	duplicates := make([][]*Employee, 0)
+
+	for _, group := range groups {
+		if len(group) < 2 {
+			continue
+		}
+
+		sort.Slice(group, func(i, j int) bool { return group[i].ID < group[j].ID })
+		duplicates = append(duplicates, group)
+	}
+
+	// Keep the report stable between calls
+	sort.Slice(duplicates, func(i, j int) bool {
+		a, b := duplicates[i][0], duplicates[j][0]
+		if a.LastName != b.LastName {
+			return a.LastName < b.LastName
+		}
+		if a.FirstName != b.FirstName {
+			return a.FirstName < b.FirstName
+		}
+		return a.ID < b.ID
+	})
+
+	return duplicates, nil
+}
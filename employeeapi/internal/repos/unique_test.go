@@ -0,0 +1,75 @@
+package repos
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests that emails are unique regardless of case
+func TestUniqueEmail(t *testing.T) {
+	logger := zerolog.New(os.Stdout)
+
+	repo := NewEmployeeRepo(logger, nil)
+	ctx := context.Background()
+
+	john, err := repo.CreateEmployee(ctx, &Employee{FirstName: "John", Email: "john.doe@example.com"})
+	require.NoError(t, err)
+
+	jane, err := repo.CreateEmployee(ctx, &Employee{FirstName: "Jane", Email: "jane.smith@example.com"})
+	require.NoError(t, err)
+
+	var conflictErr *ConflictError
+
+	// Create with an email in a different case
+	_, err = repo.CreateEmployee(ctx, &Employee{FirstName: "Johnny", Email: " John.Doe@Example.com"})
+	require.True(t, errors.As(err, &conflictErr))
+	require.Equal(t, john.ID, conflictErr.ConflictID)
+
+	// Update with the email of another employee
+	janeUpdate := *jane
+	janeUpdate.Email = "JOHN.DOE@example.com"
+	_, err = repo.UpdateEmployee(ctx, &janeUpdate)
+	require.True(t, errors.As(err, &conflictErr))
+
+	// Update keeping its own email
+	johnUpdate := *john
+	johnUpdate.Email = "John.Doe@example.com"
+	_, err = repo.UpdateEmployee(ctx, &johnUpdate)
+	require.NoError(t, err)
+
+	// Deleting releases the email
+	require.NoError(t, repo.DeleteEmployee(ctx, john.ID))
+	_, err = repo.CreateEmployee(ctx, &Employee{FirstName: "Johnny", Email: "john.doe@example.com"})
+	require.NoError(t, err)
+
+	// Restoring fails once the email is taken
+	_, err = repo.RestoreEmployee(ctx, john.ID)
+	require.True(t, errors.As(err, &conflictErr))
+}
+
+// Tests the FindDuplicateEmployees method
+func TestFindDuplicateEmployees(t *testing.T) {
+	logger := zerolog.New(os.Stdout)
+
+	data := map[string]*Employee{
+		"1": {ID: "1", FirstName: "John", LastName: "Doe", DateOfBirth: "1985-05-15", Email: "john.doe@example.com"},
+		"2": {ID: "2", FirstName: "john", LastName: "DOE", DateOfBirth: "1985-05-15", Email: "jdoe@example.com"},
+		"3": {ID: "3", FirstName: "John", LastName: "Doe", DateOfBirth: "1990-01-01", Email: "john.doe2@example.com"},
+		"4": {ID: "4", FirstName: "Jane", LastName: "Smith", DateOfBirth: "1990-09-22", Email: "jane.smith@example.com"},
+	}
+
+	repo := NewEmployeeRepo(logger, &data)
+
+	duplicates, err := repo.FindDuplicateEmployees(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, duplicates, 1)
+	require.Len(t, duplicates[0], 2)
+	require.Equal(t, "1", duplicates[0][0].ID)
+	require.Equal(t, "2", duplicates[0][1].ID)
+}
@@ -0,0 +1,276 @@
+package repos
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusSucceeded = "succeeded"
+	DeliveryStatusFailed    = "failed"
+
+	// MaxDeliveriesPerWebhook is the number of deliveries kept in the log of a webhook;
+	// the oldest ones are dropped first
+	MaxDeliveriesPerWebhook = 100
+
+	// Number of random bytes of a generated webhook secret
+	webhookSecretSize = 32
+)
+
+// Webhook is a subscription of a downstream system to employee events
+type Webhook struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+	// Events subscribed to; an empty list subscribes to every event
+	Events []string `json:"events"`
+	// Fields of the employee sent in the payloads; an empty list sends every field
+	Fields []string `json:"fields"`
+	// Secret signs the payloads sent to the webhook
+	Secret    string    `json:"secret"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookDelivery records the attempts to send an event to a webhook
+type WebhookDelivery struct {
+	ID         string    `json:"id"`
+	WebhookID  string    `json:"webhook_id"`
+	EventID    string    `json:"event_id"`
+	EventType  string    `json:"event_type"`
+	Status     string    `json:"status"`
+	Attempts   int       `json:"attempts"`
+	StatusCode int       `json:"status_code"`
+	Error      string    `json:"error"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type WebhookRepo interface {
+	GetWebhooks(ctx context.Context) ([]*Webhook, error)
+	GetWebhook(ctx context.Context, id string) (*Webhook, error)
+	CreateWebhook(ctx context.Context, webhook *Webhook) (*Webhook, error)
+	UpdateWebhook(ctx context.Context, webhook *Webhook) (*Webhook, error)
+	DeleteWebhook(ctx context.Context, id string) error
+	GetDeliveries(ctx context.Context, webhookID string) ([]*WebhookDelivery, error)
+	SaveDelivery(ctx context.Context, delivery *WebhookDelivery) (*WebhookDelivery, error)
+}
+
+type webhookRepo struct {
+	logger     zerolog.Logger
+	webhooks   map[string]*Webhook
+	deliveries map[string][]*WebhookDelivery // webhook id -> deliveries, oldest first
+	mu         sync.RWMutex
+}
+
+// NewWebhookRepo creates a new webhook repository
+func NewWebhookRepo(logger zerolog.Logger) WebhookRepo {
+	return &webhookRepo{
+		logger:     logger,
+		webhooks:   make(map[string]*Webhook),
+		deliveries: make(map[string][]*WebhookDelivery),
+	}
+}
+
+// GetWebhooks gets all webhooks, oldest first
+func (w *webhookRepo) GetWebhooks(ctx context.Context) ([]*Webhook, error) {
+	// Ensure we read once it's safe to do so
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	webhooks := make([]*Webhook, 0, len(w.webhooks))
+	for _, webhook := range w.webhooks {
+		webhooks = append(webhooks, copyWebhook(webhook))
+	}
+
+	sort.Slice(webhooks, func(i, j int) bool {
+		if !webhooks[i].CreatedAt.Equal(webhooks[j].CreatedAt) {
+			return webhooks[i].CreatedAt.Before(webhooks[j].CreatedAt)
+		}
+		return webhooks[i].ID < webhooks[j].ID
+	})
+
+	return webhooks, nil
+}
+
+// GetWebhook gets a webhook by id
+func (w *webhookRepo) GetWebhook(ctx context.Context, id string) (*Webhook, error) {
+	// Ensure we read once it's safe to do so
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	webhook, ok := w.webhooks[id]
+	if !ok {
+		err := ErrNotFound
+
+		l := requestLogger(ctx, w.logger)
+		l.Error().Err(err).Str("package", packageName).Str("func", "GetWebhook").Msg("failed to get webhook")
+
+		return nil, err
+	}
+
+	return copyWebhook(webhook), nil
+}
+
+// CreateWebhook creates a webhook
+// A secret is generated if the webhook has none
+func (w *webhookRepo) CreateWebhook(ctx context.Context, webhook *Webhook) (*Webhook, error) {
+	l := requestLogger(ctx, w.logger).With().Str("package", packageName).Str("func", "CreateWebhook").Logger()
+
+	if webhook.Secret == "" {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			l.Error().Err(err).Msg("failed to generate webhook secret")
+
+			return nil, err
+		}
+		webhook.Secret = secret
+	}
+
+	// Ensure only one write at a time
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	webhook.ID = uuid.New().String()
+	webhook.CreatedAt = time.Now().UTC()
+	w.webhooks[webhook.ID] = copyWebhook(webhook)
+
+	return webhook, nil
+}
+
+// UpdateWebhook updates the url, events, fields and status of a webhook
+// The secret and creation time are kept
+func (w *webhookRepo) UpdateWebhook(ctx context.Context, webhook *Webhook) (*Webhook, error) {
+	// Ensure only one write at a time
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	stored, ok := w.webhooks[webhook.ID]
+	if !ok {
+		err := ErrNotFound
+
+		l := requestLogger(ctx, w.logger)
+		l.Error().Err(err).Str("package", packageName).Str("func", "UpdateWebhook").Msg("failed to update webhook")
+
+		return nil, err
+	}
+
+	stored.URL = webhook.URL
+	stored.Events = append([]string(nil), webhook.Events...)
+	stored.Fields = append([]string(nil), webhook.Fields...)
+	stored.IsActive = webhook.IsActive
+
+	return copyWebhook(stored), nil
+}
+
+// DeleteWebhook deletes a webhook and its delivery log
+func (w *webhookRepo) DeleteWebhook(ctx context.Context, id string) error {
+	// Ensure only one write at a time
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.webhooks[id]; !ok {
+		err := ErrNotFound
+
+		l := requestLogger(ctx, w.logger)
+		l.Error().Err(err).Str("package", packageName).Str("func", "DeleteWebhook").Msg("failed to delete webhook")
+
+		return err
+	}
+
+	delete(w.webhooks, id)
+	delete(w.deliveries, id)
+
+	return nil
+}
+
+// GetDeliveries gets the delivery log of a webhook, newest first
+func (w *webhookRepo) GetDeliveries(ctx context.Context, webhookID string) ([]*WebhookDelivery, error) {
+	// Ensure we read once it's safe to do so
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if _, ok := w.webhooks[webhookID]; !ok {
+		err := ErrNotFound
+
+		l := requestLogger(ctx, w.logger)
+		l.Error().Err(err).Str("package", packageName).Str("func", "GetDeliveries").Msg("failed to get deliveries")
+
+		return nil, err
+	}
+
+	stored := w.deliveries[webhookID]
+
+	deliveries := make([]*WebhookDelivery, 0, len(stored))
+	for i := len(stored) - 1; i >= 0; i-- {
+		delivery := *stored[i]
+		deliveries = append(deliveries, &delivery)
+	}
+
+	return deliveries, nil
+}
+
+// SaveDelivery adds a delivery to the log of its webhook, or updates it if it has an id
+// Returns ErrNotFound if the webhook or the delivery doesn't exist anymore
+func (w *webhookRepo) SaveDelivery(ctx context.Context, delivery *WebhookDelivery) (*WebhookDelivery, error) {
+	// Ensure only one write at a time
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.webhooks[delivery.WebhookID]; !ok {
+		return nil, ErrNotFound
+	}
+
+	now := time.Now().UTC()
+	delivery.UpdatedAt = now
+
+	if delivery.ID == "" {
+		delivery.ID = uuid.New().String()
+		delivery.CreatedAt = now
+
+		stored := *delivery
+		deliveries := append(w.deliveries[delivery.WebhookID], &stored)
+		if len(deliveries) > MaxDeliveriesPerWebhook {
+			deliveries = deliveries[len(deliveries)-MaxDeliveriesPerWebhook:]
+		}
+		w.deliveries[delivery.WebhookID] = deliveries
+
+		return delivery, nil
+	}
+
+	for i, stored := range w.deliveries[delivery.WebhookID] {
+		if stored.ID == delivery.ID {
+			updated := *delivery
+			w.deliveries[delivery.WebhookID][i] = &updated
+
+			return delivery, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+func copyWebhook(webhook *Webhook) *Webhook {
+	webhookCopy := *webhook
+	webhookCopy.Events = append([]string(nil), webhook.Events...)
+	webhookCopy.Fields = append([]string(nil), webhook.Fields...)
+
+	return &webhookCopy
+}
+
+// Generates a random hex encoded secret
+func newWebhookSecret() (string, error) {
+	b := make([]byte, webhookSecretSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,114 @@
+package repos
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests the webhook subscription methods
+func TestWebhooks(t *testing.T) {
+	logger := zerolog.New(os.Stdout)
+
+	repo := NewWebhookRepo(logger)
+	ctx := context.Background()
+
+	created, err := repo.CreateWebhook(ctx, &Webhook{
+		URL:      "https://payroll.example.com/hooks",
+		Events:   []string{"employee.created"},
+		IsActive: true,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, created.ID)
+	// A secret is generated when none is given
+	require.Len(t, created.Secret, webhookSecretSize*2)
+
+	withSecret, err := repo.CreateWebhook(ctx, &Webhook{
+		URL:    "https://badges.example.com/hooks",
+		Secret: "badge-access-secret",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "badge-access-secret", withSecret.Secret)
+
+	webhooks, err := repo.GetWebhooks(ctx)
+	require.NoError(t, err)
+	require.Len(t, webhooks, 2)
+	require.Equal(t, created.ID, webhooks[0].ID)
+
+	updated, err := repo.UpdateWebhook(ctx, &Webhook{
+		ID:     created.ID,
+		URL:    "https://payroll.example.com/v2/hooks",
+		Events: []string{"employee.deleted"},
+		// The secret can't be changed by an update
+		Secret: "ignored",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "https://payroll.example.com/v2/hooks", updated.URL)
+	require.Equal(t, []string{"employee.deleted"}, updated.Events)
+	require.Equal(t, created.Secret, updated.Secret)
+	require.False(t, updated.IsActive)
+
+	_, err = repo.UpdateWebhook(ctx, &Webhook{ID: "missing"})
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, repo.DeleteWebhook(ctx, created.ID))
+
+	_, err = repo.GetWebhook(ctx, created.ID)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.ErrorIs(t, repo.DeleteWebhook(ctx, created.ID), ErrNotFound)
+}
+
+// Tests the webhook delivery log
+func TestWebhookDeliveries(t *testing.T) {
+	logger := zerolog.New(os.Stdout)
+
+	repo := NewWebhookRepo(logger)
+	ctx := context.Background()
+
+	webhook, err := repo.CreateWebhook(ctx, &Webhook{URL: "https://payroll.example.com/hooks", IsActive: true})
+	require.NoError(t, err)
+
+	first, err := repo.SaveDelivery(ctx, &WebhookDelivery{
+		WebhookID: webhook.ID,
+		EventType: "employee.created",
+		Status:    DeliveryStatusPending,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, first.ID)
+
+	// Updating a delivery replaces it in the log
+	first.Status = DeliveryStatusSucceeded
+	first.Attempts = 1
+	_, err = repo.SaveDelivery(ctx, first)
+	require.NoError(t, err)
+
+	// Only the most recent deliveries are kept
+	for i := 0; i < MaxDeliveriesPerWebhook; i++ {
+		_, err = repo.SaveDelivery(ctx, &WebhookDelivery{
+			WebhookID: webhook.ID,
+			EventType: "employee.updated",
+			Status:    DeliveryStatusPending,
+		})
+		require.NoError(t, err)
+	}
+
+	deliveries, err := repo.GetDeliveries(ctx, webhook.ID)
+	require.NoError(t, err)
+	require.Len(t, deliveries, MaxDeliveriesPerWebhook)
+	for _, delivery := range deliveries {
+		require.NotEqual(t, first.ID, delivery.ID)
+	}
+
+	// The log goes away with the webhook
+	require.NoError(t, repo.DeleteWebhook(ctx, webhook.ID))
+
+	_, err = repo.GetDeliveries(ctx, webhook.ID)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = repo.SaveDelivery(ctx, &WebhookDelivery{WebhookID: webhook.ID})
+	require.ErrorIs(t, err, ErrNotFound)
+}
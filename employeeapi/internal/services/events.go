@@ -0,0 +1,184 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"employeeapi/internal/repos"
+)
+
+const (
+	// EventEmployeeRestored is only sent on the change stream; webhooks aren't notified of restores
+	EventEmployeeRestored = "employee.restored"
+
+	// DefaultEventHistory is the number of events kept for the subscribers resuming the stream
+	DefaultEventHistory = 1000
+	// Number of events waiting to be sent to a subscriber before it's dropped
+	subscriberBuffer = 64
+)
+
+// StreamEvent is an employee event of the change stream
+// IDs increase in the order the events happened so a subscriber can resume after the last one it got
+type StreamEvent struct {
+	ID         uint64
+	Type       string
+	OccurredAt time.Time
+	Actor      string
+	Employee   *repos.Employee
+}
+
+// Subscription receives the events published after it was made
+// Events is closed when the subscriber falls too far behind or the hub is stopped;
+// the subscriber can then subscribe again from the last event it got
+type Subscription struct {
+	Events <-chan *StreamEvent
+	events chan *StreamEvent
+}
+
+// EventHub broadcasts the writes of the employee repository to the subscribers of the change stream
+// and keeps the last events so subscribers can resume
+type EventHub struct {
+	mu          sync.Mutex
+	history     []*StreamEvent
+	size        int
+	lastID      uint64
+	subscribers map[*Subscription]bool
+	stopped     bool
+}
+
+// NewEventHub creates a hub keeping the given number of events; it gets no events until started
+func NewEventHub(history int) *EventHub {
+	return &EventHub{
+		size:        history,
+		subscribers: make(map[*Subscription]bool),
+	}
+}
+
+// Start listens to the writes of the employee repository
+func (h *EventHub) Start(empRepo repos.EmployeeRepo) {
+	empRepo.AddListener(h.publish)
+}
+
+// Stop closes the subscriptions so the streams end, e.g. before shutting down the server
+func (h *EventHub) Stop() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.stopped = true
+	for sub := range h.subscribers {
+		h.drop(sub)
+	}
+}
+
+// Subscribe subscribes to the events published from now on
+// With resume, the events kept after lastID are returned to be sent first; complete is false if
+// some events after lastID were already forgotten or lastID is unknown, in which case the
+// subscriber has missed some
+func (h *EventHub) Subscribe(lastID uint64, resume bool) (sub *Subscription, missed []*StreamEvent, complete bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	events := make(chan *StreamEvent, subscriberBuffer)
+	sub = &Subscription{Events: events, events: events}
+
+	if h.stopped {
+		close(events)
+		return sub, nil, true
+	}
+
+	h.subscribers[sub] = true
+
+	if !resume {
+		return sub, nil, true
+	}
+
+	// IDs start over when the server restarts so an ID that wasn't given yet is from before
+	if lastID >= h.lastID {
+		return sub, nil, lastID == h.lastID
+	}
+
+	// The events kept are the last ones so the first one tells whether some are missing
+	complete = len(h.history) > 0 && h.history[0].ID <= lastID+1
+
+	for _, event := range h.history {
+		if event.ID > lastID {
+			missed = append(missed, event)
+		}
+	}
+
+	return sub, missed, complete
+}
+
+// Unsubscribe stops sending events to a subscription
+func (h *EventHub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.subscribers[sub] {
+		h.drop(sub)
+	}
+}
+
+// Removes a subscription and closes its channel
+// The caller must hold the lock
+func (h *EventHub) drop(sub *Subscription) {
+	delete(h.subscribers, sub)
+	close(sub.events)
+}
+
+// Numbers a write to an employee, keeps it and sends it to the subscribers
+// Called while the employee repository is locked so it never blocks: subscribers that
+// aren't keeping up are dropped
+func (h *EventHub) publish(event repos.Event) {
+	eventType := streamEventType(event)
+	if eventType == "" {
+		return
+	}
+
+	employee := event.After
+	if employee == nil {
+		employee = event.Before
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastID++
+	streamEvent := &StreamEvent{
+		ID:         h.lastID,
+		Type:       eventType,
+		OccurredAt: event.Timestamp,
+		Actor:      event.Actor,
+		Employee:   employee,
+	}
+
+	h.history = append(h.history, streamEvent)
+	if len(h.history) > h.size {
+		h.history = h.history[len(h.history)-h.size:]
+	}
+
+	for sub := range h.subscribers {
+		select {
+		case sub.events <- streamEvent:
+		default:
+			h.drop(sub)
+		}
+	}
+}
+
+// Returns the type of the change stream event of a write, empty if it's not streamed
+func streamEventType(event repos.Event) string {
+	switch event.Action {
+	case repos.AuditActionCreate:
+		return EventEmployeeCreated
+	case repos.AuditActionUpdate:
+		return EventEmployeeUpdated
+	case repos.AuditActionDelete:
+		return EventEmployeeDeleted
+	case repos.AuditActionRestore:
+		return EventEmployeeRestored
+	default:
+		// Purged employees were already deleted
+		return ""
+	}
+}
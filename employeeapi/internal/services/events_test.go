@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"employeeapi/internal/repos"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+// Tests that the hub numbers the employee events, sends them to the subscribers and resumes them
+func TestEventHub(t *testing.T) {
+	logger := zerolog.New(os.Stdout)
+	empRepo := repos.NewEmployeeRepo(logger, nil)
+
+	hub := NewEventHub(2)
+	hub.Start(empRepo)
+
+	ctx := context.Background()
+
+	sub, missed, complete := hub.Subscribe(0, false)
+	require.Empty(t, missed)
+	require.True(t, complete)
+
+	emp, err := empRepo.CreateEmployee(ctx, &repos.Employee{FirstName: "John", Email: "john@example.com"})
+	require.NoError(t, err)
+
+	emp.IsActive = true
+	_, err = empRepo.UpdateEmployee(ctx, emp)
+	require.NoError(t, err)
+
+	require.NoError(t, empRepo.DeleteEmployee(ctx, emp.ID))
+
+	// Purges aren't streamed
+	_, err = empRepo.PurgeEmployees(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	for i, eventType := range []string{EventEmployeeCreated, EventEmployeeUpdated, EventEmployeeDeleted} {
+		event := <-sub.Events
+		require.Equal(t, uint64(i+1), event.ID)
+		require.Equal(t, eventType, event.Type)
+		require.Equal(t, emp.ID, event.Employee.ID)
+	}
+	require.Empty(t, sub.Events)
+
+	// Only the last two events are kept
+	resumed, missed, complete := hub.Subscribe(1, true)
+	require.True(t, complete)
+	require.Len(t, missed, 2)
+	require.Equal(t, uint64(2), missed[0].ID)
+
+	_, missed, complete = hub.Subscribe(0, true)
+	require.False(t, complete)
+	require.Len(t, missed, 2)
+
+	// Up to date, and unknown ids from before a restart
+	_, missed, complete = hub.Subscribe(3, true)
+	require.True(t, complete)
+	require.Empty(t, missed)
+
+	_, _, complete = hub.Subscribe(10, true)
+	require.False(t, complete)
+
+	hub.Unsubscribe(sub)
+	_, ok := <-sub.Events
+	require.False(t, ok)
+
+	hub.Stop()
+	_, ok = <-resumed.Events
+	require.False(t, ok)
+
+	stopped, _, _ := hub.Subscribe(0, false)
+	_, ok = <-stopped.Events
+	require.False(t, ok)
+}
+
+// Tests that subscribers that don't keep up are dropped rather than blocking the repository
+func TestEventHubSlowSubscriber(t *testing.T) {
+	hub := NewEventHub(DefaultEventHistory)
+
+	sub, _, _ := hub.Subscribe(0, false)
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		hub.publish(repos.Event{Action: repos.AuditActionCreate, After: &repos.Employee{}})
+	}
+
+	received := 0
+	for range sub.Events {
+		received++
+	}
+	require.Equal(t, subscriberBuffer, received)
+
+	// It can resume from the last event it got
+	_, missed, complete := hub.Subscribe(uint64(received), true)
+	require.True(t, complete)
+	require.Len(t, missed, 1)
+}
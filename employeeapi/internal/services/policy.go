@@ -0,0 +1,49 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Policy is the validation policy of the employees, enforced by the validators of the APIs
+type Policy struct {
+	// MinAge and MaxAge bound the age of the employees in years; 0 disables the bound
+	MinAge int
+	MaxAge int
+	// EmailDomains are the domains the emails must be at; any domain is allowed when empty
+	EmailDomains []string
+	// NameCharacters are the characters allowed in names besides letters
+	NameCharacters string
+}
+
+// DefaultPolicy is the policy of a service unless it's set otherwise
+var DefaultPolicy = Policy{
+	MinAge:         16,
+	MaxAge:         100,
+	NameCharacters: " -'.",
+}
+
+// Validate checks that the policy can be enforced and normalizes its email domains
+func (p *Policy) Validate() error {
+	if p.MinAge < 0 || p.MaxAge < 0 {
+		return errors.New("ages can't be negative")
+	}
+
+	if p.MaxAge > 0 && p.MaxAge < p.MinAge {
+		return fmt.Errorf("maximum age %d is below the minimum age %d", p.MaxAge, p.MinAge)
+	}
+
+	domains := make([]string, 0, len(p.EmailDomains))
+	for _, domain := range p.EmailDomains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if domain == "" || strings.ContainsAny(domain, "@ |,") {
+			return fmt.Errorf("invalid email domain %q", domain)
+		}
+
+		domains = append(domains, domain)
+	}
+	p.EmailDomains = domains
+
+	return nil
+}
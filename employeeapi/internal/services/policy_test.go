@@ -0,0 +1,66 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests the validation of the policy
+func TestPolicyValidate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		policy  Policy
+		domains []string
+		err     bool
+	}{
+		{
+			name:   "Successful - default policy",
+			policy: DefaultPolicy,
+		},
+		{
+			name:    "Successful - domains are normalized",
+			policy:  Policy{MinAge: 18, EmailDomains: []string{" Example.COM", "example.org"}},
+			domains: []string{"example.com", "example.org"},
+		},
+		{
+			name:   "Successful - no maximum age",
+			policy: Policy{MinAge: 18},
+		},
+		{
+			name:   "Failed - negative age",
+			policy: Policy{MinAge: -1},
+			err:    true,
+		},
+		{
+			name:   "Failed - maximum age below the minimum",
+			policy: Policy{MinAge: 30, MaxAge: 20},
+			err:    true,
+		},
+		{
+			name:   "Failed - email domain with @",
+			policy: Policy{EmailDomains: []string{"@example.com"}},
+			err:    true,
+		},
+		{
+			name:   "Failed - empty email domain",
+			policy: Policy{EmailDomains: []string{""}},
+			err:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.policy.Validate()
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			if tc.domains != nil {
+				require.Equal(t, tc.domains, tc.policy.EmailDomains)
+			}
+		})
+	}
+}
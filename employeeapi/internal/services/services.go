@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+
 	"employeeapi/internal/repos"
 
 	"github.com/google/uuid"
@@ -8,16 +10,32 @@ import (
 )
 
 type Service struct {
-	EmpRepo repos.EmployeeRepo
+	EmpRepo     repos.EmployeeRepo
+	WebhookRepo repos.WebhookRepo
+	// Webhooks sends employee events to the subscribed webhooks once started
+	Webhooks *WebhookDispatcher
+	// Events broadcasts employee events to the change stream once started
+	Events *EventHub
+	// Policy is the validation policy of the employees
+	Policy Policy
 }
 
 // NewService creates a new service
 // Service layer wraps the repository layer and performs any additional process
 func NewService(logger zerolog.Logger, initData bool) Service {
-	empRepo := repos.NewEmployeeRepo(logger, nil)
+	return NewEncryptedService(logger, initData, nil)
+}
+
+// NewEncryptedService creates a new service whose repository encrypts the PII of the employees
+// with cipher; a nil cipher stores it in clear like NewService
+func NewEncryptedService(logger zerolog.Logger, initData bool, cipher *repos.FieldCipher) Service {
+	empRepo := repos.NewEncryptedEmployeeRepo(logger, nil, cipher)
+
+	// Seed data is attributed to the system actor
+	ctx := context.Background()
 
 	if initData {
-		_, err := empRepo.CreateEmployee(&repos.Employee{
+		_, err := empRepo.CreateEmployee(ctx, &repos.Employee{
 			ID:          uuid.New().String(),
 			FirstName:   "John",
 			LastName:    "Doe",
@@ -26,12 +44,13 @@ func NewService(logger zerolog.Logger, initData bool) Service {
 			IsActive:    true,
 			Department:  "Engineering",
 			Role:        "Software Developer",
+			HireDate:    "2012-04-02",
 		})
 		if err != nil {
 			logger.Error().Err(err).Msg("failed to create employee")
 		}
 
-		_, err = empRepo.CreateEmployee(&repos.Employee{
+		_, err = empRepo.CreateEmployee(ctx, &repos.Employee{
 			ID:          uuid.New().String(),
 			FirstName:   "Jane",
 			LastName:    "Smith",
@@ -40,12 +59,13 @@ func NewService(logger zerolog.Logger, initData bool) Service {
 			IsActive:    true,
 			Department:  "Marketing",
 			Role:        "Marketing Specialist",
+			HireDate:    "2016-10-17",
 		})
 		if err != nil {
 			logger.Error().Err(err).Msg("failed to create employee")
 		}
 
-		_, err = empRepo.CreateEmployee(&repos.Employee{
+		_, err = empRepo.CreateEmployee(ctx, &repos.Employee{
 			ID:          uuid.New().String(),
 			FirstName:   "Robert",
 			LastName:    "Johnson",
@@ -54,12 +74,13 @@ func NewService(logger zerolog.Logger, initData bool) Service {
 			IsActive:    false,
 			Department:  "Finance",
 			Role:        "Financial Analyst",
+			HireDate:    "2014-07-01",
 		})
 		if err != nil {
 			logger.Error().Err(err).Msg("failed to create employee")
 		}
 
-		_, err = empRepo.CreateEmployee(&repos.Employee{
+		_, err = empRepo.CreateEmployee(ctx, &repos.Employee{
 			ID:          uuid.New().String(),
 			FirstName:   "Emily",
 			LastName:    "Williams",
@@ -68,13 +89,20 @@ func NewService(logger zerolog.Logger, initData bool) Service {
 			IsActive:    true,
 			Department:  "Human Resources",
 			Role:        "HR Specialist",
+			HireDate:    "2019-02-11",
 		})
 		if err != nil {
 			logger.Error().Err(err).Msg("failed to create employee")
 		}
 	}
 
+	webhookRepo := repos.NewWebhookRepo(logger)
+
 	return Service{
-		EmpRepo: empRepo,
+		EmpRepo:     repos.NewInstrumentedEmployeeRepo(empRepo),
+		WebhookRepo: webhookRepo,
+		Webhooks:    NewWebhookDispatcher(logger, webhookRepo, DefaultDispatcherConfig),
+		Events:      NewEventHub(DefaultEventHistory),
+		Policy:      DefaultPolicy,
 	}
 }
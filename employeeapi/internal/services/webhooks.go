@@ -0,0 +1,398 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"employeeapi/internal/repos"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+const (
+	EventEmployeeCreated     = "employee.created"
+	EventEmployeeUpdated     = "employee.updated"
+	EventEmployeeDeactivated = "employee.deactivated"
+	EventEmployeeDeleted     = "employee.deleted"
+
+	// Headers sent with every webhook request
+	HeaderWebhookEvent     = "X-Webhook-Event"
+	HeaderWebhookDelivery  = "X-Webhook-Delivery"
+	HeaderWebhookTimestamp = "X-Webhook-Timestamp"
+	// HeaderWebhookSignature is "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>"
+	// keyed with the secret of the webhook
+	HeaderWebhookSignature = "X-Webhook-Signature"
+
+	packageName = "services"
+)
+
+// WebhookEvents are the events webhooks can subscribe to
+var WebhookEvents = []string{
+	EventEmployeeCreated,
+	EventEmployeeUpdated,
+	EventEmployeeDeactivated,
+	EventEmployeeDeleted,
+}
+
+// WebhookEvent is the payload POSTed to webhooks
+type WebhookEvent struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Actor      string          `json:"actor"`
+	Employee   *repos.Employee `json:"employee"`
+}
+
+// Returns the JSON of the payload with only the given fields of its employee
+func (e WebhookEvent) marshalFields(fields []string) ([]byte, error) {
+	data, err := json.Marshal(e.Employee)
+	if err != nil {
+		return nil, err
+	}
+
+	employee := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &employee); err != nil {
+		return nil, err
+	}
+
+	keep := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		keep[field] = true
+	}
+
+	for name := range employee {
+		if !keep[name] {
+			delete(employee, name)
+		}
+	}
+
+	// The employee of the outer struct takes the place of the embedded one
+	return json.Marshal(struct {
+		WebhookEvent
+		Employee map[string]json.RawMessage `json:"employee"`
+	}{e, employee})
+}
+
+// DispatcherConfig controls how webhook deliveries are sent and retried
+type DispatcherConfig struct {
+	// Number of deliveries sent concurrently
+	Workers int
+	// Number of deliveries waiting to be sent before new ones are dropped
+	QueueSize int
+	// Number of attempts before a delivery is marked as failed
+	MaxAttempts int
+	// Wait before the first retry, doubled on every retry up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Timeout of one attempt
+	Timeout time.Duration
+}
+
+var DefaultDispatcherConfig = DispatcherConfig{
+	Workers:        4,
+	QueueSize:      1000,
+	MaxAttempts:    5,
+	InitialBackoff: time.Second,
+	MaxBackoff:     time.Minute,
+	Timeout:        10 * time.Second,
+}
+
+// WebhookDispatcher sends employee events to the subscribed webhooks in the background
+type WebhookDispatcher struct {
+	logger zerolog.Logger
+	repo   repos.WebhookRepo
+	cfg    DispatcherConfig
+	client *http.Client
+	queue  chan *webhookJob
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// webhookJob is a delivery waiting to be sent with the webhook as it was when the event happened
+type webhookJob struct {
+	webhook  *repos.Webhook
+	delivery *repos.WebhookDelivery
+	body     []byte
+}
+
+// NewWebhookDispatcher creates a dispatcher; it doesn't send anything until started
+func NewWebhookDispatcher(logger zerolog.Logger, repo repos.WebhookRepo, cfg DispatcherConfig) *WebhookDispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &WebhookDispatcher{
+		logger: logger,
+		repo:   repo,
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+		queue:  make(chan *webhookJob, cfg.QueueSize),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Start listens to the writes of the employee repository and starts sending deliveries
+func (d *WebhookDispatcher) Start(empRepo repos.EmployeeRepo) {
+	empRepo.AddListener(d.handleEvent)
+
+	for i := 0; i < d.cfg.Workers; i++ {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			d.work()
+		}()
+	}
+}
+
+// Stop stops sending deliveries and waits for the ones in progress to be abandoned
+// Deliveries that weren't sent are marked as failed
+func (d *WebhookDispatcher) Stop() {
+	d.cancel()
+	d.wg.Wait()
+}
+
+// Turns a write to an employee into deliveries to the subscribed webhooks
+// Called while the employee repository is locked so sending is left to the workers
+func (d *WebhookDispatcher) handleEvent(event repos.Event) {
+	l := d.logger.With().Str("package", packageName).Str("func", "handleEvent").Logger()
+
+	if d.ctx.Err() != nil {
+		return
+	}
+
+	types := webhookEventTypes(event)
+	if len(types) == 0 {
+		return
+	}
+
+	webhooks, err := d.repo.GetWebhooks(d.ctx)
+	if err != nil {
+		l.Error().Err(err).Msg("failed to get webhooks")
+		return
+	}
+
+	employee := event.After
+	if employee == nil {
+		employee = event.Before
+	}
+
+	for _, eventType := range types {
+		payload := WebhookEvent{
+			ID:         uuid.New().String(),
+			Type:       eventType,
+			OccurredAt: event.Timestamp,
+			Actor:      event.Actor,
+			Employee:   employee,
+		}
+
+		body, err := json.Marshal(payload)
+		if err != nil {
+			l.Error().Err(err).Str("event", eventType).Msg("failed to marshal event")
+			continue
+		}
+
+		for _, webhook := range webhooks {
+			if !webhook.IsActive || !subscribed(webhook, eventType) {
+				continue
+			}
+
+			body := body
+			if len(webhook.Fields) > 0 {
+				if body, err = payload.marshalFields(webhook.Fields); err != nil {
+					l.Error().Err(err).Str("webhook", webhook.ID).Msg("failed to marshal event")
+					continue
+				}
+			}
+
+			delivery, err := d.repo.SaveDelivery(d.ctx, &repos.WebhookDelivery{
+				WebhookID: webhook.ID,
+				EventID:   payload.ID,
+				EventType: eventType,
+				Status:    repos.DeliveryStatusPending,
+			})
+			if err != nil {
+				l.Error().Err(err).Str("webhook", webhook.ID).Msg("failed to save delivery")
+				continue
+			}
+
+			select {
+			case d.queue <- &webhookJob{webhook: webhook, delivery: delivery, body: body}:
+			default:
+				l.Error().Str("webhook", webhook.ID).Str("event", eventType).Msg("delivery queue is full")
+				d.finish(delivery, repos.DeliveryStatusFailed, "delivery queue is full")
+			}
+		}
+	}
+}
+
+// Sends the queued deliveries until the dispatcher is stopped
+func (d *WebhookDispatcher) work() {
+	for {
+		select {
+		case <-d.ctx.Done():
+			// Anything left in the queue won't be sent
+			for {
+				select {
+				case job := <-d.queue:
+					d.finish(job.delivery, repos.DeliveryStatusFailed, "dispatcher stopped")
+				default:
+					return
+				}
+			}
+		case job := <-d.queue:
+			d.deliver(job)
+		}
+	}
+}
+
+// Sends a delivery, retrying with exponential backoff on network errors,
+// 429 and 5xx responses; other responses aren't retried
+func (d *WebhookDispatcher) deliver(job *webhookJob) {
+	l := d.logger.With().Str("package", packageName).Str("func", "deliver").
+		Str("webhook", job.webhook.ID).Str("delivery", job.delivery.ID).Logger()
+
+	backoff := d.cfg.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		statusCode, err := d.send(job)
+
+		job.delivery.Attempts = attempt
+		job.delivery.StatusCode = statusCode
+		job.delivery.Error = ""
+		if err != nil {
+			job.delivery.Error = err.Error()
+		}
+
+		if err == nil && statusCode >= 200 && statusCode < 300 {
+			d.finish(job.delivery, repos.DeliveryStatusSucceeded, "")
+			return
+		}
+
+		if !retryable(statusCode, err) || attempt >= d.cfg.MaxAttempts {
+			l.Error().Err(err).Int("status", statusCode).Int("attempts", attempt).Msg("failed to deliver event")
+			d.finish(job.delivery, repos.DeliveryStatusFailed, job.delivery.Error)
+			return
+		}
+
+		if _, err := d.repo.SaveDelivery(d.ctx, job.delivery); err != nil {
+			// The webhook was deleted in the meantime
+			return
+		}
+
+		select {
+		case <-d.ctx.Done():
+			d.finish(job.delivery, repos.DeliveryStatusFailed, "dispatcher stopped")
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, d.cfg.MaxBackoff)
+	}
+}
+
+// Makes one attempt to send a delivery and returns the response status code
+func (d *WebhookDispatcher) send(job *webhookJob) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(d.ctx, http.MethodPost, job.webhook.URL, bytes.NewReader(job.body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderWebhookEvent, job.delivery.EventType)
+	req.Header.Set(HeaderWebhookDelivery, job.delivery.ID)
+	req.Header.Set(HeaderWebhookTimestamp, timestamp)
+	req.Header.Set(HeaderWebhookSignature, SignWebhookPayload(job.webhook.Secret, timestamp, job.body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Drain the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Records the outcome of a delivery
+func (d *WebhookDispatcher) finish(delivery *repos.WebhookDelivery, status, errMsg string) {
+	delivery.Status = status
+	delivery.Error = errMsg
+
+	// Saved even when stopping so the log shows what wasn't sent
+	if _, err := d.repo.SaveDelivery(context.Background(), delivery); err != nil && !errors.Is(err, repos.ErrNotFound) {
+		d.logger.Error().Err(err).Str("package", packageName).Str("func", "finish").Msg("failed to save delivery")
+	}
+}
+
+// SignWebhookPayload returns the value of the signature header of a payload
+// Receivers recompute it from the timestamp header and the raw body to verify the request
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Returns the webhook events raised by a write to an employee
+// An update that deactivates an employee raises both an update and a deactivation
+func webhookEventTypes(event repos.Event) []string {
+	switch event.Action {
+	case repos.AuditActionCreate:
+		return []string{EventEmployeeCreated}
+	case repos.AuditActionUpdate:
+		if event.Before != nil && event.After != nil && event.Before.IsActive && !event.After.IsActive {
+			return []string{EventEmployeeUpdated, EventEmployeeDeactivated}
+		}
+		return []string{EventEmployeeUpdated}
+	case repos.AuditActionDelete:
+		return []string{EventEmployeeDeleted}
+	default:
+		return nil
+	}
+}
+
+func subscribed(webhook *repos.Webhook, eventType string) bool {
+	if len(webhook.Events) == 0 {
+		return true
+	}
+
+	for _, event := range webhook.Events {
+		if event == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func retryable(statusCode int, err error) bool {
+	if statusCode == 0 {
+		// No response: network error or timeout, unless the dispatcher was stopped
+		return !errors.Is(err, context.Canceled)
+	}
+
+	return statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
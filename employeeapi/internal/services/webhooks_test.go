@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"employeeapi/internal/repos"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+var testDispatcherConfig = DispatcherConfig{
+	Workers:        2,
+	QueueSize:      10,
+	MaxAttempts:    3,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     5 * time.Millisecond,
+	Timeout:        time.Second,
+}
+
+// receivedEvent is a webhook request captured by the test server
+type receivedEvent struct {
+	event     WebhookEvent
+	signature string
+	timestamp string
+	body      []byte
+}
+
+// Starts a server replying with the given status codes in turn (the last one is repeated)
+// and returns it with the events it received
+func webhookServer(t *testing.T, statusCodes ...int) (*httptest.Server, func() []receivedEvent) {
+	var mu sync.Mutex
+	received := make([]receivedEvent, 0)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var event WebhookEvent
+		require.NoError(t, json.Unmarshal(body, &event))
+		require.Equal(t, event.Type, r.Header.Get(HeaderWebhookEvent))
+		require.NotEmpty(t, r.Header.Get(HeaderWebhookDelivery))
+
+		mu.Lock()
+		code := statusCodes[min(len(received), len(statusCodes)-1)]
+		received = append(received, receivedEvent{
+			event:     event,
+			signature: r.Header.Get(HeaderWebhookSignature),
+			timestamp: r.Header.Get(HeaderWebhookTimestamp),
+			body:      body,
+		})
+		mu.Unlock()
+
+		w.WriteHeader(code)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []receivedEvent {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]receivedEvent(nil), received...)
+	}
+}
+
+// Waits until the deliveries of a webhook are all done and returns them, newest first
+func waitForDeliveries(t *testing.T, repo repos.WebhookRepo, webhookID string, count int) []*repos.WebhookDelivery {
+	var deliveries []*repos.WebhookDelivery
+
+	require.Eventually(t, func() bool {
+		var err error
+		deliveries, err = repo.GetDeliveries(context.Background(), webhookID)
+		require.NoError(t, err)
+
+		if len(deliveries) != count {
+			return false
+		}
+		for _, delivery := range deliveries {
+			if delivery.Status == repos.DeliveryStatusPending {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 5*time.Millisecond)
+
+	return deliveries
+}
+
+// Tests that employee writes are delivered to the subscribed webhooks with a valid signature
+func TestWebhookDispatcher(t *testing.T) {
+	logger := zerolog.New(os.Stdout)
+
+	server, received := webhookServer(t, http.StatusOK)
+	deactivationServer, deactivationsReceived := webhookServer(t, http.StatusOK)
+
+	svc := NewService(logger, false)
+	svc.Webhooks = NewWebhookDispatcher(logger, svc.WebhookRepo, testDispatcherConfig)
+	svc.Webhooks.Start(svc.EmpRepo)
+	defer svc.Webhooks.Stop()
+
+	ctx := repos.ContextWithActor(context.Background(), "hr.admin")
+
+	all, err := svc.WebhookRepo.CreateWebhook(ctx, &repos.Webhook{URL: server.URL, IsActive: true})
+	require.NoError(t, err)
+
+	deactivations, err := svc.WebhookRepo.CreateWebhook(ctx, &repos.Webhook{
+		URL:      deactivationServer.URL,
+		Events:   []string{EventEmployeeDeactivated},
+		Fields:   []string{"id", "is_active"},
+		IsActive: true,
+	})
+	require.NoError(t, err)
+
+	inactive, err := svc.WebhookRepo.CreateWebhook(ctx, &repos.Webhook{URL: server.URL})
+	require.NoError(t, err)
+
+	emp, err := svc.EmpRepo.CreateEmployee(ctx, &repos.Employee{
+		FirstName: "John",
+		LastName:  "Doe",
+		Email:     "john.doe@example.com",
+		IsActive:  true,
+	})
+	require.NoError(t, err)
+
+	update := *emp
+	update.IsActive = false
+	_, err = svc.EmpRepo.UpdateEmployee(ctx, &update)
+	require.NoError(t, err)
+
+	require.NoError(t, svc.EmpRepo.DeleteEmployee(ctx, emp.ID))
+
+	// Created, updated, deactivated and deleted
+	deliveries := waitForDeliveries(t, svc.WebhookRepo, all.ID, 4)
+	for _, delivery := range deliveries {
+		require.Equal(t, repos.DeliveryStatusSucceeded, delivery.Status)
+		require.Equal(t, 1, delivery.Attempts)
+		require.Equal(t, http.StatusOK, delivery.StatusCode)
+	}
+
+	deliveries = waitForDeliveries(t, svc.WebhookRepo, deactivations.ID, 1)
+	require.Equal(t, EventEmployeeDeactivated, deliveries[0].EventType)
+
+	deliveries, err = svc.WebhookRepo.GetDeliveries(ctx, inactive.ID)
+	require.NoError(t, err)
+	require.Empty(t, deliveries)
+
+	types := make(map[string]int)
+	for _, e := range received() {
+		types[e.event.Type]++
+
+		require.Equal(t, emp.ID, e.event.Employee.ID)
+		require.Equal(t, "hr.admin", e.event.Actor)
+		require.Equal(t, SignWebhookPayload(all.Secret, e.timestamp, e.body), e.signature)
+	}
+
+	require.Equal(t, map[string]int{
+		EventEmployeeCreated:     1,
+		EventEmployeeUpdated:     1,
+		EventEmployeeDeactivated: 1,
+		EventEmployeeDeleted:     1,
+	}, types)
+
+	events := deactivationsReceived()
+	require.Len(t, events, 1)
+	require.False(t, events[0].event.Employee.IsActive)
+	require.Equal(t, emp.ID, events[0].event.Employee.ID)
+	require.Equal(t, "hr.admin", events[0].event.Actor)
+
+	// Only the fields of the subscription are sent
+	var payload struct {
+		Employee map[string]any `json:"employee"`
+	}
+	require.NoError(t, json.Unmarshal(events[0].body, &payload))
+	require.Equal(t, map[string]any{"id": emp.ID, "is_active": false}, payload.Employee)
+	require.Equal(t, SignWebhookPayload(deactivations.Secret, events[0].timestamp, events[0].body), events[0].signature)
+}
+
+// Tests that failed deliveries are retried only when the failure may be temporary
+func TestWebhookDispatcherRetries(t *testing.T) {
+	testCases := []struct {
+		name        string
+		statusCodes []int
+		status      string
+		attempts    int
+		statusCode  int
+	}{
+		{
+			name:        "Successful - Deliver - after retries",
+			statusCodes: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusNoContent},
+			status:      repos.DeliveryStatusSucceeded,
+			attempts:    3,
+			statusCode:  http.StatusNoContent,
+		},
+		{
+			name:        "Failed - Deliver - too many attempts",
+			statusCodes: []int{http.StatusInternalServerError},
+			status:      repos.DeliveryStatusFailed,
+			attempts:    testDispatcherConfig.MaxAttempts,
+			statusCode:  http.StatusInternalServerError,
+		},
+		{
+			name:        "Failed - Deliver - not retried",
+			statusCodes: []int{http.StatusGone},
+			status:      repos.DeliveryStatusFailed,
+			attempts:    1,
+			statusCode:  http.StatusGone,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := zerolog.New(os.Stdout)
+
+			server, received := webhookServer(t, tc.statusCodes...)
+
+			svc := NewService(logger, false)
+			svc.Webhooks = NewWebhookDispatcher(logger, svc.WebhookRepo, testDispatcherConfig)
+			svc.Webhooks.Start(svc.EmpRepo)
+			defer svc.Webhooks.Stop()
+
+			ctx := context.Background()
+
+			webhook, err := svc.WebhookRepo.CreateWebhook(ctx, &repos.Webhook{
+				URL:      server.URL,
+				Events:   []string{EventEmployeeCreated},
+				IsActive: true,
+			})
+			require.NoError(t, err)
+
+			_, err = svc.EmpRepo.CreateEmployee(ctx, &repos.Employee{FirstName: "John", LastName: "Doe", Email: "john.doe@example.com"})
+			require.NoError(t, err)
+
+			deliveries := waitForDeliveries(t, svc.WebhookRepo, webhook.ID, 1)
+			require.Equal(t, tc.status, deliveries[0].Status)
+			require.Equal(t, tc.attempts, deliveries[0].Attempts)
+			require.Equal(t, tc.statusCode, deliveries[0].StatusCode)
+			require.Len(t, received(), tc.attempts)
+
+			// Every attempt carries the same event
+			for _, e := range received() {
+				require.Equal(t, deliveries[0].EventID, e.event.ID)
+			}
+		})
+	}
+}
+
+// Tests the SignWebhookPayload function
+func TestSignWebhookPayload(t *testing.T) {
+	signature := SignWebhookPayload("secret", "1700000000", []byte(`{"id":"1"}`))
+
+	require.Equal(t, "sha256=", signature[:7])
+	require.Len(t, signature, 7+64)
+	require.Equal(t, signature, SignWebhookPayload("secret", "1700000000", []byte(`{"id":"1"}`)))
+	require.NotEqual(t, signature, SignWebhookPayload("other", "1700000000", []byte(`{"id":"1"}`)))
+	require.NotEqual(t, signature, SignWebhookPayload("secret", "1700000001", []byte(`{"id":"1"}`)))
+}
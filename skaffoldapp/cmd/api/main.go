@@ -14,20 +14,19 @@ import (
 	"skaffoldapp/internal/api/services"
 	"skaffoldapp/internal/shared/database"
 	"skaffoldapp/internal/shared/repos"
+	"skaffoldapp/internal/shared/storage"
 
 	"github.com/kelseyhightower/envconfig"
 	"github.com/rs/zerolog"
 )
 
 type Environment struct {
-	MongoDBUsername       string `required:"true" envconfig:"MONGODB_USERNAME"`
-	MongoDBPassword       string `required:"true" envconfig:"MONGODB_PASSWORD"`
-	MongoDBHost           string `required:"true" envconfig:"MONGODB_HOST"`
-	MongoDBPort           string `required:"true" envconfig:"MONGODB_PORT"`
-	AzStorageAccount      string `required:"true" envconfig:"AZURE_STORAGE_ACCOUNT"`
-	AzStorageKey          string `required:"true" envconfig:"AZURE_STORAGE_KEY"`
-	AzStorageBlobEndpoint string `required:"true" envconfig:"AZURE_STORAGE_BLOB_ENDPOINT"`
-	AzContainerName       string `required:"true" envconfig:"AZURE_STORAGE_CONTAINER_NAME"`
+	MongoDBUsername string `required:"true" envconfig:"MONGODB_USERNAME"`
+	MongoDBPassword string `required:"true" envconfig:"MONGODB_PASSWORD"`
+	MongoDBHost     string `required:"true" envconfig:"MONGODB_HOST"`
+	MongoDBPort     string `required:"true" envconfig:"MONGODB_PORT"`
+
+	storage.Config
 }
 
 func main() {
@@ -43,15 +42,15 @@ func main() {
 		log.Fatalf("failed to connect to MongoDB: %v", err)
 	}
 
-	azureManager, err := services.NewAzureManager(env.AzStorageAccount, env.AzStorageKey, env.AzStorageBlobEndpoint, env.AzContainerName)
+	blobStore, err := storage.New(env.Config)
 	if err != nil {
-		log.Fatalf("failed to create AzureManager: %v", err)
+		log.Fatalf("failed to create blob store: %v", err)
 	}
 
 	repoCollection := repos.NewRepoCollection(mongoDBClient)
-	userService := services.NewUserService(logger, repoCollection)
+	userService := services.NewUserService(logger, repoCollection, blobStore)
 	userController := controllers.NewUsersController(userService)
-	batchController := controllers.NewBatchController(services.NewBatchService(logger, repoCollection, azureManager))
+	batchController := controllers.NewBatchController(services.NewBatchService(logger, repoCollection, blobStore))
 
 	mux := http.NewServeMux()
 	routers.SetupUserRoutes(mux, userController)
@@ -1,17 +1,84 @@
 package main
 
 import (
+	"context"
+	"log"
 	"os"
+	"os/signal"
 	"skaffoldapp/internal/job"
+	"skaffoldapp/internal/shared/database"
+	"skaffoldapp/internal/shared/repos"
+	"skaffoldapp/internal/shared/storage"
+	"syscall"
+	"time"
 
+	"github.com/kelseyhightower/envconfig"
 	"github.com/rs/zerolog"
 )
 
+type Environment struct {
+	MongoDBUsername string `required:"true" envconfig:"MONGODB_USERNAME"`
+	MongoDBPassword string `required:"true" envconfig:"MONGODB_PASSWORD"`
+	MongoDBHost     string `required:"true" envconfig:"MONGODB_HOST"`
+	MongoDBPort     string `required:"true" envconfig:"MONGODB_PORT"`
+
+	storage.Config
+
+	// How often pending exports are looked for, and how long the claim of a replica on an export
+	// lasts; it's renewed every third of the lease while the export is processed, so another
+	// replica only claims it once the replica stopped
+	PollInterval time.Duration `default:"10s" envconfig:"JOB_POLL_INTERVAL"`
+	ExportLease  time.Duration `default:"5m" envconfig:"JOB_EXPORT_LEASE"`
+}
+
 func main() {
 	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
 
-	jobService := job.NewJobService(logger)
+	var env Environment
+	if err := envconfig.Process("", &env); err != nil {
+		log.Fatalf("failed to load environment variables.: %v", err)
+	}
+
+	// Pending exports are polled and the lease renewed on tickers, which need a positive period;
+	// the lease has to outlast its renewals by enough that a slow one doesn't lose the claim
+	if env.PollInterval <= 0 {
+		log.Fatalf("JOB_POLL_INTERVAL must be positive: %v", env.PollInterval)
+	}
+	if env.ExportLease < job.MinExportLease {
+		log.Fatalf("JOB_EXPORT_LEASE must be at least %v: %v", job.MinExportLease, env.ExportLease)
+	}
+
+	mongoDBClient, err := database.NewMongoDatabase(env.MongoDBHost, env.MongoDBPort, env.MongoDBUsername, env.MongoDBPassword)
+	if err != nil {
+		log.Fatalf("failed to connect to MongoDB: %v", err)
+	}
+
+	blobStore, err := storage.New(env.Config)
+	if err != nil {
+		log.Fatalf("failed to create blob store: %v", err)
+	}
+
+	// The pod name identifies the replica claiming the exports
+	workerID, err := os.Hostname()
+	if err != nil {
+		log.Fatalf("failed to get hostname: %v", err)
+	}
+
+	repoCollection := repos.NewRepoCollection(mongoDBClient)
+	jobService := job.NewJobService(logger, repoCollection, blobStore, workerID, env.PollInterval, env.ExportLease)
+
+	// Stop polling on termination signal; an export being processed is claimed again by another
+	// replica once its lease expires
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	jobService.Run(ctx)
 
-	jobService.Run()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
+	log.Println("Disconnecting database...")
+	if err := mongoDBClient.Disconnect(shutdownCtx); err != nil {
+		log.Printf("Failed to disconnect from MongoDB: %v", err)
+	}
 }
@@ -2,12 +2,20 @@ package integration_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"skaffoldapp/integration_test/utils"
-	"skaffoldapp/internal/api/services"
 	"skaffoldapp/internal/shared/database"
 	"skaffoldapp/internal/shared/models"
+	"skaffoldapp/internal/shared/repos"
+	"skaffoldapp/internal/shared/storage"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -67,26 +75,37 @@ func TestGenerateDBExport(t *testing.T) {
 
 	// When
 	dbExport, err := batchService.GenerateDBExport(ctx)
+	require.NoError(t, err)
+
+	require.Equal(t, models.BatchStatusPending, dbExport.Status, "export should be pending")
+	require.NotEmpty(t, dbExport.DateRequested, "date requested should not be empty")
+	require.NotEqual(t, primitive.NilObjectID, dbExport.ID, "id should not be empty")
+
+	processed, err := jobService.ProcessDBExports(ctx)
 
 	// Then
 	require.NoError(t, err)
+	require.Equal(t, 1, processed)
 
+	dbExport, err = repoCollection.Batch.GetDBExport(ctx, dbExport.ID)
+	require.NoError(t, err)
+
+	require.Equal(t, models.BatchStatusComplete, dbExport.Status)
 	require.NotEmpty(t, dbExport.FileName, "file name should not be empty")
-	require.NotEmpty(t, dbExport.Status, "status should not be empty")
-	require.NotEmpty(t, dbExport.DateRequested, "date requested should not be empty")
-	require.NotEqual(t, primitive.NilObjectID, dbExport.ID, "id should not be empty")
 	require.Empty(t, dbExport.ErrorMessage, "error message should be empty")
-
-	azureManager, err := services.NewAzureManager(utils.DefaultAzureAccountName, utils.DefaultAzureBlobKey, AzBlobEndpoint, utils.TestContainerName)
-	require.NoError(t, err, "failed to create AzureManager")
+	require.Empty(t, dbExport.ClaimedBy, "export should be released")
 
 	tmpFilePath := filepath.Join(os.TempDir(), dbExport.FileName)
 	defer os.Remove(tmpFilePath)
 
-	err = azureManager.GetBlobFile(ctx, dbExport.FileName, tmpFilePath)
+	tmpFile, err := os.Create(tmpFilePath)
+	require.NoError(t, err, "failed to create file")
+
+	err = storage.DownloadTo(ctx, azureManager, dbExport.FileName, tmpFile)
 	require.NoError(t, err, "failed to get blob file")
+	require.NoError(t, tmpFile.Close())
 
-	usersFromAzure, err := database.GetUsersFromDatabase(tmpFilePath)
+	usersFromAzure, _, err := database.GetUsersFromDatabase(ctx, tmpFilePath)
 	require.NoError(t, err, "failed to get users from database")
 
 	require.Equal(t, len(users), len(usersFromAzure), "number of users should be the same")
@@ -101,3 +120,237 @@ func TestGenerateDBExport(t *testing.T) {
 		t.Fatalf("failed to clean up MongoDB: %v", err)
 	}
 }
+
+func TestGenerateDBExportNoUsers(t *testing.T) {
+	ctx := context.Background()
+	// Given
+	dbExport, err := batchService.GenerateDBExport(ctx)
+	require.NoError(t, err)
+
+	// When
+	processed, err := jobService.ProcessDBExports(ctx)
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, 1, processed)
+
+	dbExport, err = repoCollection.Batch.GetDBExport(ctx, dbExport.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.BatchStatusError, dbExport.Status)
+	require.Equal(t, "No users found", dbExport.ErrorMessage)
+
+	err = utils.CleanupMongoDB()
+	if err != nil {
+		t.Fatalf("failed to clean up MongoDB: %v", err)
+	}
+}
+
+func TestClaimPendingDBExport(t *testing.T) {
+	ctx := context.Background()
+	// Given
+	var dbExports []interface{}
+	dbExports = append(dbExports, models.DBExport{
+		DateRequested: gofakeit.Date().Truncate(time.Millisecond),
+		Status:        models.BatchStatusPending,
+	})
+	dbExports = append(dbExports, models.DBExport{
+		DateRequested:  gofakeit.Date().Truncate(time.Millisecond),
+		Status:         models.BatchStatusPending,
+		ClaimedBy:      "crashed-job",
+		LeaseExpiresAt: time.Now().Add(-time.Minute),
+	})
+	dbExports = append(dbExports, models.DBExport{
+		DateRequested:  gofakeit.Date().Truncate(time.Millisecond),
+		Status:         models.BatchStatusPending,
+		ClaimedBy:      "running-job",
+		LeaseExpiresAt: time.Now().Add(time.Hour),
+	})
+	dbExports = append(dbExports, models.DBExport{
+		DateRequested: gofakeit.Date().Truncate(time.Millisecond),
+		Status:        models.BatchStatusComplete,
+	})
+	if err := utils.InsertBatches(ctx, dbExports); err != nil {
+		require.NoError(t, err)
+	}
+
+	// When
+	// Replicas claim concurrently
+	claims := make(chan models.DBExport, 10)
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for {
+				dbExport, err := repoCollection.Batch.ClaimPendingDBExport(ctx, fmt.Sprintf("job-%d", i), time.Minute)
+				if errors.Is(err, repos.ErrNotFound) {
+					return
+				}
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				claims <- dbExport
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(claims)
+
+	// Then
+	// Only the unclaimed export and the one whose lease expired are claimed, once each
+	claimed := make(map[primitive.ObjectID]models.DBExport)
+	for dbExport := range claims {
+		require.NotContains(t, claimed, dbExport.ID, "export claimed twice")
+		claimed[dbExport.ID] = dbExport
+	}
+	require.Len(t, claimed, 2)
+
+	for _, dbExport := range claimed {
+		require.NotEqual(t, "running-job", dbExport.ClaimedBy)
+		require.True(t, dbExport.LeaseExpiresAt.After(time.Now()), "lease should be renewed")
+
+		// The replica which lost its claim can't finish the export
+		dbExport.Status = models.BatchStatusComplete
+		require.ErrorIs(t, repoCollection.Batch.FinishDBExport(ctx, dbExport, "crashed-job"), repos.ErrNotFound)
+		require.NoError(t, repoCollection.Batch.FinishDBExport(ctx, dbExport, dbExport.ClaimedBy))
+	}
+
+	err := utils.CleanupMongoDB()
+	if err != nil {
+		t.Fatalf("failed to clean up MongoDB: %v", err)
+	}
+}
+
+func TestRenewDBExportLease(t *testing.T) {
+	ctx := context.Background()
+	// Given
+	var dbExports []interface{}
+	dbExports = append(dbExports, models.DBExport{
+		DateRequested: gofakeit.Date().Truncate(time.Millisecond),
+		Status:        models.BatchStatusPending,
+	})
+	if err := utils.InsertBatches(ctx, dbExports); err != nil {
+		require.NoError(t, err)
+	}
+
+	dbExport, err := repoCollection.Batch.ClaimPendingDBExport(ctx, "job-0", time.Minute)
+	require.NoError(t, err)
+
+	// When
+	err = repoCollection.Batch.RenewDBExportLease(ctx, dbExport.ID, "job-0", time.Hour)
+
+	// Then
+	require.NoError(t, err)
+
+	renewed, err := repoCollection.Batch.GetDBExport(ctx, dbExport.ID)
+	require.NoError(t, err)
+	require.True(t, renewed.LeaseExpiresAt.After(time.Now().Add(time.Minute)), "lease should be extended")
+
+	// A replica without the claim can't renew it
+	require.ErrorIs(t, repoCollection.Batch.RenewDBExportLease(ctx, dbExport.ID, "job-1", time.Hour), repos.ErrNotFound)
+
+	err = utils.CleanupMongoDB()
+	if err != nil {
+		t.Fatalf("failed to clean up MongoDB: %v", err)
+	}
+}
+
+func TestDownloadDBExport(t *testing.T) {
+	ctx := context.Background()
+	// Given
+	var users []interface{}
+	users = append(users, models.User{
+		Name: gofakeit.Name(),
+	})
+	if err := utils.InsertUsers(ctx, users); err != nil {
+		require.NoError(t, err)
+	}
+
+	complete, err := batchService.GenerateDBExport(ctx)
+	require.NoError(t, err)
+	_, err = jobService.ProcessDBExports(ctx)
+	require.NoError(t, err)
+
+	complete, err = repoCollection.Batch.GetDBExport(ctx, complete.ID)
+	require.NoError(t, err)
+
+	pending, err := batchService.GenerateDBExport(ctx)
+	require.NoError(t, err)
+
+	failedID, err := repoCollection.Batch.InsertDBExports(ctx, models.DBExport{
+		DateRequested: time.Now().Truncate(time.Millisecond),
+		Status:        models.BatchStatusError,
+		ErrorMessage:  "No users found",
+	})
+	require.NoError(t, err)
+
+	missingFileID, err := repoCollection.Batch.InsertDBExports(ctx, models.DBExport{
+		DateRequested: time.Now().Truncate(time.Millisecond),
+		Status:        models.BatchStatusComplete,
+		FileName:      "missing.db",
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		id         string
+		httpStatus int
+	}{
+		{name: "complete export", id: complete.ID.Hex(), httpStatus: http.StatusOK},
+		{name: "pending export", id: pending.ID.Hex(), httpStatus: http.StatusConflict},
+		{name: "failed export", id: failedID.Hex(), httpStatus: http.StatusConflict},
+		{name: "missing file", id: missingFileID.Hex(), httpStatus: http.StatusNotFound},
+		{name: "unknown export", id: primitive.NewObjectID().Hex(), httpStatus: http.StatusNotFound},
+		{name: "invalid id", id: "abc", httpStatus: http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// When
+			req := httptest.NewRequest(http.MethodGet, "/generateddbexport/"+tc.id+"/download", nil)
+			rr := httptest.NewRecorder()
+			batchMux.ServeHTTP(rr, req)
+
+			// Then
+			require.Equal(t, tc.httpStatus, rr.Code)
+			if tc.httpStatus != http.StatusOK {
+				// The internal error message of a failed export isn't sent
+				require.NotContains(t, rr.Body.String(), "No users found")
+				return
+			}
+
+			require.Equal(t, `attachment; filename=`+complete.FileName, rr.Header().Get("Content-Disposition"))
+			require.Equal(t, strconv.Itoa(rr.Body.Len()), rr.Header().Get("Content-Length"))
+
+			tmpFilePath := filepath.Join(t.TempDir(), complete.FileName)
+			require.NoError(t, os.WriteFile(tmpFilePath, rr.Body.Bytes(), 0o600))
+
+			usersFromFile, _, err := database.GetUsersFromDatabase(ctx, tmpFilePath)
+			require.NoError(t, err)
+			require.Len(t, usersFromFile, len(users))
+		})
+	}
+
+	// Only the complete exports link to their download
+	req := httptest.NewRequest(http.MethodGet, "/generateddbexport", nil)
+	rr := httptest.NewRecorder()
+	batchMux.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var listed []models.DBExportResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &listed))
+
+	downloadURLs := make(map[string]string)
+	for _, dbExport := range listed {
+		downloadURLs[dbExport.ID] = dbExport.DownloadURL
+	}
+	require.Equal(t, "/generateddbexport/"+complete.ID.Hex()+"/download", downloadURLs[complete.ID.Hex()])
+	require.Empty(t, downloadURLs[pending.ID.Hex()])
+	require.Empty(t, downloadURLs[failedID.Hex()])
+
+	err = utils.CleanupMongoDB()
+	if err != nil {
+		t.Fatalf("failed to clean up MongoDB: %v", err)
+	}
+}
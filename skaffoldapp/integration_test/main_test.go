@@ -3,12 +3,17 @@ package integration_test
 import (
 	"context"
 	"log"
+	"net/http"
 	"os"
 	"skaffoldapp/integration_test/utils"
 	"skaffoldapp/internal/api/controllers"
+	"skaffoldapp/internal/api/routers"
 	"skaffoldapp/internal/api/services"
+	"skaffoldapp/internal/job"
 	"skaffoldapp/internal/shared/repos"
+	"skaffoldapp/internal/shared/storage"
 	"testing"
+	"time"
 
 	"github.com/rs/zerolog"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -19,7 +24,11 @@ var (
 	repoCollection repos.RepoCollection
 	userService    services.UserService
 	userController controllers.UsersController
+	userMux        *http.ServeMux
 	batchService   services.BatchService
+	batchMux       *http.ServeMux
+	azureManager   storage.AzureManager
+	jobService     job.JobService
 	AzBlobEndpoint string
 )
 
@@ -53,7 +62,7 @@ func TestMain(m *testing.M) {
 	AzBlobEndpoint = blobEndpoint
 
 	// Initialize Azure Manager with Azurite
-	azureManager, err := services.NewAzureManager(utils.DefaultAzureAccountName, utils.DefaultAzureBlobKey, AzBlobEndpoint, utils.TestContainerName)
+	azureManager, err = storage.NewAzureManager(utils.DefaultAzureAccountName, utils.DefaultAzureBlobKey, AzBlobEndpoint, utils.TestContainerName)
 	if err != nil {
 		log.Fatalf("failed to create AzureManager: %v", err)
 	}
@@ -61,9 +70,14 @@ func TestMain(m *testing.M) {
 	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
 
 	repoCollection = repos.NewRepoCollection(utils.MongoClient)
-	userService = services.NewUserService(logger, repoCollection)
+	userService = services.NewUserService(logger, repoCollection, azureManager)
 	userController = controllers.NewUsersController(userService)
+	userMux = http.NewServeMux()
+	routers.SetupUserRoutes(userMux, userController)
 	batchService = services.NewBatchService(logger, repoCollection, azureManager)
+	batchMux = http.NewServeMux()
+	routers.SetupBatchRoutes(batchMux, controllers.NewBatchController(batchService))
+	jobService = job.NewJobService(logger, repoCollection, azureManager, "test-job", time.Second, time.Minute)
 
 	// Run tests
 	code := m.Run()
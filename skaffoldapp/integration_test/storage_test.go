@@ -0,0 +1,103 @@
+package integration_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"skaffoldapp/internal/shared/storage"
+	"strings"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlobStores(t *testing.T) {
+	localStore, err := storage.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	blobStores := map[string]storage.BlobStore{
+		storage.BlobStoreAzure: azureManager,
+		storage.BlobStoreLocal: localStore,
+	}
+
+	for name, blobStore := range blobStores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			// Given
+			blobName := gofakeit.UUID() + ".db"
+			content := gofakeit.Sentence(20)
+
+			// When
+			err := blobStore.Upload(ctx, blobName, strings.NewReader(content))
+
+			// Then
+			require.NoError(t, err)
+
+			info, err := blobStore.Stat(ctx, blobName)
+			require.NoError(t, err)
+			require.Equal(t, blobName, info.Name)
+			require.Equal(t, int64(len(content)), info.Size)
+			require.False(t, info.LastModified.IsZero(), "last modified should be set")
+
+			r, size, err := blobStore.Download(ctx, blobName)
+			require.NoError(t, err)
+			require.Equal(t, int64(len(content)), size)
+			require.NoError(t, r.Close())
+
+			var downloaded bytes.Buffer
+			require.NoError(t, storage.DownloadTo(ctx, blobStore, blobName, &downloaded))
+			require.Equal(t, content, downloaded.String())
+
+			// Uploading again replaces the blob
+			content = gofakeit.Sentence(5)
+			require.NoError(t, blobStore.Upload(ctx, blobName, strings.NewReader(content)))
+
+			downloaded.Reset()
+			require.NoError(t, storage.DownloadTo(ctx, blobStore, blobName, &downloaded))
+			require.Equal(t, content, downloaded.String())
+
+			blobs, err := blobStore.List(ctx)
+			require.NoError(t, err)
+			names := []string{}
+			for _, blob := range blobs {
+				names = append(names, blob.Name)
+			}
+			require.Contains(t, names, blobName)
+
+			require.NoError(t, blobStore.Delete(ctx, blobName))
+
+			_, err = blobStore.Stat(ctx, blobName)
+			require.ErrorIs(t, err, storage.ErrBlobNotFound)
+			_, _, err = blobStore.Download(ctx, blobName)
+			require.ErrorIs(t, err, storage.ErrBlobNotFound)
+			require.ErrorIs(t, blobStore.Delete(ctx, blobName), storage.ErrBlobNotFound)
+		})
+	}
+}
+
+func TestLocalStoreBlobNames(t *testing.T) {
+	localStore, err := storage.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	// Blobs can't be written outside of the directory
+	for _, blobName := range []string{"", ".", "..", "../export.db", "exports/export.db"} {
+		err := localStore.Upload(context.Background(), blobName, strings.NewReader("content"))
+		require.Error(t, err, blobName)
+	}
+}
+
+func TestLocalStoreBlobMode(t *testing.T) {
+	dir := t.TempDir()
+	localStore, err := storage.NewLocalStore(dir)
+	require.NoError(t, err)
+
+	// When
+	require.NoError(t, localStore.Upload(context.Background(), "export.db", strings.NewReader("content")))
+
+	// Then the blob can be read by the other users of the directory
+	info, err := os.Stat(filepath.Join(dir, "export.db"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o644), info.Mode().Perm())
+}
@@ -1,14 +1,30 @@
 package integration_test
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"skaffoldapp/integration_test/utils"
+	"skaffoldapp/internal/shared/database"
 	"skaffoldapp/internal/shared/models"
 
 	"github.com/brianvoe/gofakeit/v7"
 	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func TestGetUsers(t *testing.T) {
@@ -65,3 +81,208 @@ func TestCreateUser(t *testing.T) {
 		t.Fatalf("failed to clean up MongoDB: %v", err)
 	}
 }
+
+func TestImportUsers(t *testing.T) {
+	ctx := context.Background()
+
+	unchanged := models.User{ID: primitive.NewObjectID(), Name: gofakeit.Name()}
+	updated := models.User{ID: primitive.NewObjectID(), Name: gofakeit.Name()}
+	inserted := models.User{ID: primitive.NewObjectID(), Name: gofakeit.Name()}
+	unnamed := models.User{ID: primitive.NewObjectID()}
+
+	// The db has the users of the collection, one of them renamed, a new user and invalid ones
+	dbUsers := []models.User{unchanged, {ID: updated.ID, Name: gofakeit.Name()}, inserted, unnamed}
+	dbPath := filepath.Join(t.TempDir(), "users.db")
+	require.NoError(t, database.CreateUsersDB(dbUsers, dbPath))
+
+	// Rows CreateUsersDB doesn't write but an uploaded db can have: an id that isn't an ObjectID
+	// and a NULL name
+	db, err := sql.Open("sqlite", dbPath)
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO users (id, name) VALUES (?, ?), (?, NULL)", "not-an-object-id", gofakeit.Name(), primitive.NewObjectID().Hex())
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	dbFile, err := os.ReadFile(dbPath)
+	require.NoError(t, err)
+
+	multipartBody := func(content []byte) (string, []byte) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("file", "users.db")
+		require.NoError(t, err)
+		_, err = part.Write(content)
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+		return writer.FormDataContentType(), body.Bytes()
+	}
+	uploadType, upload := multipartBody(dbFile)
+	invalidType, invalidUpload := multipartBody([]byte(gofakeit.Paragraph(5, 5, 20, " ")))
+
+	// A db whose users is a view over another table rather than a table
+	viewPath := filepath.Join(t.TempDir(), "view.db")
+	viewDB, err := sql.Open("sqlite", viewPath)
+	require.NoError(t, err)
+	_, err = viewDB.Exec("CREATE TABLE people (id TEXT, name TEXT); CREATE VIEW users AS SELECT id, name FROM people")
+	require.NoError(t, err)
+	require.NoError(t, viewDB.Close())
+
+	viewFile, err := os.ReadFile(viewPath)
+	require.NoError(t, err)
+	viewType, viewUpload := multipartBody(viewFile)
+
+	// A complete export of the db and a pending one
+	exportName := primitive.NewObjectID().Hex() + ".db"
+	require.NoError(t, azureManager.Upload(ctx, exportName, bytes.NewReader(dbFile)))
+
+	completeID, err := repoCollection.Batch.InsertDBExports(ctx, models.DBExport{
+		DateRequested: time.Now().Truncate(time.Millisecond),
+		Status:        models.BatchStatusComplete,
+		FileName:      exportName,
+	})
+	require.NoError(t, err)
+
+	pendingID, err := repoCollection.Batch.InsertDBExports(ctx, models.DBExport{
+		DateRequested: time.Now().Truncate(time.Millisecond),
+		Status:        models.BatchStatusPending,
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		request    func() (string, io.Reader)
+		httpStatus int
+		summary    models.UserImportResponse
+	}{
+		{
+			name:       "upload",
+			request:    func() (string, io.Reader) { return uploadType, bytes.NewReader(upload) },
+			httpStatus: http.StatusOK,
+			summary:    models.UserImportResponse{Inserted: 1, Updated: 1, Skipped: 4},
+		},
+		{
+			name: "export",
+			request: func() (string, io.Reader) {
+				return "application/json", strings.NewReader(`{"export_id":"` + completeID.Hex() + `"}`)
+			},
+			httpStatus: http.StatusOK,
+			summary:    models.UserImportResponse{Inserted: 1, Updated: 1, Skipped: 4},
+		},
+		{
+			name:       "not a users db",
+			request:    func() (string, io.Reader) { return invalidType, bytes.NewReader(invalidUpload) },
+			httpStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "users view",
+			request:    func() (string, io.Reader) { return viewType, bytes.NewReader(viewUpload) },
+			httpStatus: http.StatusBadRequest,
+		},
+		{
+			name: "pending export",
+			request: func() (string, io.Reader) {
+				return "application/json", strings.NewReader(`{"export_id":"` + pendingID.Hex() + `"}`)
+			},
+			httpStatus: http.StatusConflict,
+		},
+		{
+			name: "unknown export",
+			request: func() (string, io.Reader) {
+				return "application/json", strings.NewReader(`{"export_id":"` + primitive.NewObjectID().Hex() + `"}`)
+			},
+			httpStatus: http.StatusNotFound,
+		},
+		{
+			name:       "no file or export",
+			request:    func() (string, io.Reader) { return "application/json", strings.NewReader(`{}`) },
+			httpStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Given
+			_, err := utils.MongoClient.Database(database.DB_NAME).Collection(database.USER_COLLECTION).DeleteMany(ctx, bson.M{})
+			require.NoError(t, err)
+			require.NoError(t, utils.InsertUsers(ctx, []interface{}{unchanged, updated}))
+
+			// When
+			contentType, body := tc.request()
+			req := httptest.NewRequest(http.MethodPost, "/users/import", body)
+			req.Header.Set("Content-Type", contentType)
+			rr := httptest.NewRecorder()
+			userMux.ServeHTTP(rr, req)
+
+			// Then
+			require.Equal(t, tc.httpStatus, rr.Code, rr.Body.String())
+
+			users, err := utils.GetUsers(ctx)
+			require.NoError(t, err)
+
+			if tc.httpStatus != http.StatusOK {
+				require.Len(t, users, 2, "users should be left as they were")
+				return
+			}
+
+			var summary models.UserImportResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &summary))
+			require.Equal(t, tc.summary, summary)
+
+			names := make(map[primitive.ObjectID]string)
+			for _, user := range users {
+				names[user.ID] = user.Name
+			}
+			require.Equal(t, map[primitive.ObjectID]string{
+				unchanged.ID: unchanged.Name,
+				updated.ID:   dbUsers[1].Name,
+				inserted.ID:  inserted.Name,
+			}, names)
+		})
+	}
+
+	err = utils.CleanupMongoDB()
+	if err != nil {
+		t.Fatalf("failed to clean up MongoDB: %v", err)
+	}
+}
+
+func TestUpsertUsersPartialFailure(t *testing.T) {
+	ctx := context.Background()
+	// Given
+	coll := utils.MongoClient.Database(database.DB_NAME).Collection(database.USER_COLLECTION)
+
+	// A unique name makes the upsert of a user named like another one fail
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	require.NoError(t, err)
+
+	existing := models.User{ID: primitive.NewObjectID(), Name: gofakeit.Name()}
+	require.NoError(t, utils.InsertUsers(ctx, []interface{}{existing}))
+
+	users := []models.User{
+		{ID: primitive.NewObjectID(), Name: existing.Name},
+		{ID: primitive.NewObjectID(), Name: gofakeit.Name()},
+		existing,
+	}
+
+	// When
+	inserted, updated, unchanged, failed, err := repoCollection.User.UpsertUsers(ctx, users)
+
+	// Then
+	require.NoError(t, err)
+	require.Equal(t, 1, inserted, "the writes after the failed one should still be applied")
+	require.Equal(t, 0, updated)
+	require.Equal(t, 1, unchanged)
+	require.Equal(t, 1, failed)
+
+	stored, err := utils.GetUsers(ctx)
+	require.NoError(t, err)
+	require.Len(t, stored, 2)
+
+	err = utils.CleanupMongoDB()
+	if err != nil {
+		t.Fatalf("failed to clean up MongoDB: %v", err)
+	}
+}
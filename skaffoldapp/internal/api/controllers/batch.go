@@ -2,9 +2,16 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"mime"
 	"net/http"
 	"skaffoldapp/internal/api/services"
 	"skaffoldapp/internal/shared/models"
+	"skaffoldapp/internal/shared/repos"
+	"skaffoldapp/internal/shared/storage"
+	"strconv"
 )
 
 type BatchController struct {
@@ -26,12 +33,7 @@ func (u BatchController) GetGenerateDBExportRequests(w http.ResponseWriter, r *h
 
 	var response []models.DBExportResponse
 	for _, batch := range requests {
-		response = append(response, models.DBExportResponse{
-			ID:            batch.ID.Hex(),
-			Status:        batch.Status,
-			ErrorMessage:  batch.ErrorMessage,
-			DateRequested: batch.DateRequested,
-		})
+		response = append(response, newDBExportResponse(batch))
 	}
 
 	json.NewEncoder(w).Encode(response)
@@ -44,6 +46,60 @@ func (u BatchController) GenerateDBExport(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	response := newDBExportResponse(dbExport)
+
+	// The export is generated by the job; its status is polled with GET /generateddbexport
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(response)
+}
+
+// DownloadDBExport streams the SQLite file of a complete db export from the blob storage
+func (u BatchController) DownloadDBExport(w http.ResponseWriter, r *http.Request) {
+	dbExport, err := u.batchService.GetDBExport(r.Context(), r.PathValue("id"))
+	if errors.Is(err, repos.ErrNotFound) {
+		http.Error(w, "db export not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "failed fetching db export", http.StatusInternalServerError)
+		return
+	}
+
+	switch dbExport.Status {
+	case models.BatchStatusComplete:
+	case models.BatchStatusPending:
+		http.Error(w, "db export is still pending", http.StatusConflict)
+		return
+	default:
+		// The error message is internal, e.g. a storage error, so it's only logged
+		log.Printf("db export %s failed: %s", dbExport.ID.Hex(), dbExport.ErrorMessage)
+		http.Error(w, "db export failed", http.StatusConflict)
+		return
+	}
+
+	file, size, err := u.batchService.DownloadDBExport(r.Context(), dbExport)
+	if errors.Is(err, storage.ErrBlobNotFound) {
+		http.Error(w, "db export file not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "failed fetching db export file", http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", "application/vnd.sqlite3")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": dbExport.FileName}))
+	w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+
+	// The status is sent with the first bytes so a failure past this point only cuts the response short
+	if _, err := io.Copy(w, file); err != nil {
+		log.Printf("failed streaming db export %s: %v", dbExport.ID.Hex(), err)
+	}
+}
+
+// Builds the response of a db export; complete exports link to their download
+func newDBExportResponse(dbExport models.DBExport) models.DBExportResponse {
 	response := models.DBExportResponse{
 		ID:            dbExport.ID.Hex(),
 		Status:        dbExport.Status,
@@ -51,5 +107,9 @@ func (u BatchController) GenerateDBExport(w http.ResponseWriter, r *http.Request
 		DateRequested: dbExport.DateRequested,
 	}
 
-	json.NewEncoder(w).Encode(response)
+	if dbExport.Status == models.BatchStatusComplete {
+		response.DownloadURL = "/generateddbexport/" + dbExport.ID.Hex() + "/download"
+	}
+
+	return response
 }
@@ -2,11 +2,18 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
+	"mime"
 	"net/http"
 	"skaffoldapp/internal/api/services"
+	"skaffoldapp/internal/shared/database"
 	"skaffoldapp/internal/shared/models"
 )
 
+// Size cap of the db uploaded to import users
+const maxImportBytes = 64 << 20
+
 type UsersController struct {
 	userService services.UserService
 }
@@ -53,3 +60,78 @@ func (u UsersController) CreateUser(w http.ResponseWriter, r *http.Request) {
 		Name: req.Name,
 	})
 }
+
+// ImportUsers upserts the users of a SQLite db, either uploaded as the file field of a multipart form
+// or the file of a complete db export given by a JSON body with its export_id
+func (u UsersController) ImportUsers(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+
+	var summary models.UserImportSummary
+	var err error
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		file, fileErr := importFile(r)
+		if fileErr != nil {
+			http.Error(w, "Invalid request: "+fileErr.Error(), http.StatusBadRequest)
+			return
+		}
+
+		summary, err = u.userService.ImportUsers(r.Context(), file)
+	} else {
+		var req models.UserImportRequest
+		if decodeErr := json.NewDecoder(r.Body).Decode(&req); decodeErr != nil || req.ExportID == "" {
+			http.Error(w, "Invalid request: a file or an export_id is required", http.StatusBadRequest)
+			return
+		}
+
+		summary, err = u.userService.ImportUsersFromExport(r.Context(), req.ExportID)
+	}
+
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		http.Error(w, "File too large", http.StatusRequestEntityTooLarge)
+		return
+	case errors.Is(err, database.ErrInvalidUsersDB):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, services.ErrDBExportNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, services.ErrDBExportNotComplete):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		http.Error(w, "Failed to import users", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(models.UserImportResponse{
+		Inserted: summary.Inserted,
+		Updated:  summary.Updated,
+		Skipped:  summary.Skipped,
+	})
+}
+
+// Returns the file field of a multipart form, streamed rather than parsed into memory
+func importFile(r *http.Request) (io.Reader, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, errors.New("no file field")
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if part.FormName() == "file" {
+			return part, nil
+		}
+	}
+}
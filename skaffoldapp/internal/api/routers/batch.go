@@ -8,4 +8,5 @@ import (
 func SetupBatchRoutes(mux *http.ServeMux, batchController controllers.BatchController) {
 	mux.HandleFunc("GET /generateddbexport", batchController.GetGenerateDBExportRequests)
 	mux.HandleFunc("POST /generatedbexport", batchController.GenerateDBExport)
+	mux.HandleFunc("GET /generateddbexport/{id}/download", batchController.DownloadDBExport)
 }
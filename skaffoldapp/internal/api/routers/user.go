@@ -8,4 +8,5 @@ import (
 func SetupUserRoutes(mux *http.ServeMux, userController controllers.UsersController) {
 	mux.HandleFunc("GET /users", userController.GetUsers)
 	mux.HandleFunc("POST /users", userController.CreateUser)
+	mux.HandleFunc("POST /users/import", userController.ImportUsers)
 }
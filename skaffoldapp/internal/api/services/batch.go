@@ -2,28 +2,27 @@ package services
 
 import (
 	"context"
-	"fmt"
-	"os"
-	"path/filepath"
-	"skaffoldapp/internal/shared/database"
+	"io"
 	"skaffoldapp/internal/shared/models"
 	"skaffoldapp/internal/shared/repos"
+	"skaffoldapp/internal/shared/storage"
 	"time"
 
 	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type BatchService struct {
-	logger       zerolog.Logger
-	repo         repos.RepoCollection
-	azureManager AzureManager
+	logger    zerolog.Logger
+	repo      repos.RepoCollection
+	blobStore storage.BlobStore
 }
 
-func NewBatchService(logger zerolog.Logger, repo repos.RepoCollection, azureManager AzureManager) BatchService {
+func NewBatchService(logger zerolog.Logger, repo repos.RepoCollection, blobStore storage.BlobStore) BatchService {
 	return BatchService{
-		logger:       logger,
-		repo:         repo,
-		azureManager: azureManager,
+		logger:    logger,
+		repo:      repo,
+		blobStore: blobStore,
 	}
 }
 
@@ -31,6 +30,7 @@ func (b BatchService) GetGenerateDBExportRequests(ctx context.Context) ([]models
 	return b.repo.Batch.GetDBExports(ctx)
 }
 
+// GenerateDBExport requests a db export of the users; it's pending until the job generates it
 func (b BatchService) GenerateDBExport(ctx context.Context) (models.DBExport, error) {
 	dbExport := models.DBExport{
 		Status:        models.BatchStatusPending,
@@ -43,44 +43,21 @@ func (b BatchService) GenerateDBExport(ctx context.Context) (models.DBExport, er
 	}
 
 	dbExport.ID = id
+	return dbExport, nil
+}
 
-	users, err := b.repo.User.GetUsers(ctx)
+// GetDBExport returns a db export by id; repos.ErrNotFound is returned if it doesn't exist
+func (b BatchService) GetDBExport(ctx context.Context, id string) (models.DBExport, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return dbExport, fmt.Errorf("failed to get users: %w", err)
-	}
-
-	if len(users) == 0 {
-		dbExport.Status = models.BatchStatusError
-		dbExport.ErrorMessage = "No users found"
-		if err := b.repo.Batch.UpdateDBExport(ctx, dbExport); err != nil {
-			return dbExport, fmt.Errorf("failed to update dbExport: %w", err)
-		}
-		return dbExport, nil
-	}
-
-	// Generate sqlitedb
-	dbExportName := time.Now().Format("20060102150405") + ".db"
-	filepath.Join(os.TempDir(), dbExportName)
-	defer os.Remove(dbExportName)
-
-	if err := database.CreateUsersDB(users, dbExportName); err != nil {
-		dbExport.Status = models.BatchStatusError
-		dbExport.ErrorMessage = "Failed to create db: " + err.Error()
-		if err := b.repo.Batch.UpdateDBExport(ctx, dbExport); err != nil {
-			return dbExport, fmt.Errorf("updating dbExport: %w", err)
-		}
-		return dbExport, fmt.Errorf("creating db: %w", err)
+		return models.DBExport{}, repos.ErrNotFound
 	}
 
-	if err := b.azureManager.UploadFile(ctx, dbExportName, dbExportName); err != nil {
-		dbExport.Status = models.BatchStatusError
-		dbExport.ErrorMessage = "Failed to upload file: " + err.Error()
-		if err := b.repo.Batch.UpdateDBExport(ctx, dbExport); err != nil {
-			return dbExport, fmt.Errorf("updating dbExport: %w", err)
-		}
-		return dbExport, fmt.Errorf("uploading file: %w", err)
-	}
-	dbExport.FileName = dbExportName
+	return b.repo.Batch.GetDBExport(ctx, objectID)
+}
 
-	return dbExport, nil
+// DownloadDBExport opens the file of a complete db export and returns its size in bytes; the
+// caller closes the reader. storage.ErrBlobNotFound is returned if the file doesn't exist
+func (b BatchService) DownloadDBExport(ctx context.Context, dbExport models.DBExport) (io.ReadCloser, int64, error) {
+	return b.blobStore.Download(ctx, dbExport.FileName)
 }
@@ -2,22 +2,35 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"skaffoldapp/internal/shared/database"
 	"skaffoldapp/internal/shared/models"
 	"skaffoldapp/internal/shared/repos"
+	"skaffoldapp/internal/shared/storage"
 
 	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrDBExportNotFound    = errors.New("db export not found")
+	ErrDBExportNotComplete = errors.New("db export is not complete")
 )
 
 type UserService struct {
-	logger zerolog.Logger
-	repo   repos.RepoCollection
+	logger    zerolog.Logger
+	repo      repos.RepoCollection
+	blobStore storage.BlobStore
 }
 
-func NewUserService(logger zerolog.Logger, repo repos.RepoCollection) UserService {
+func NewUserService(logger zerolog.Logger, repo repos.RepoCollection, blobStore storage.BlobStore) UserService {
 	return UserService{
-		logger: logger,
-		repo:   repo,
+		logger:    logger,
+		repo:      repo,
+		blobStore: blobStore,
 	}
 }
 
@@ -34,3 +47,102 @@ func (u UserService) CreateUser(ctx context.Context, user models.UserRequest) (s
 		Name: user.Name,
 	})
 }
+
+// ImportUsers upserts the users of a SQLite db, as generated by the db exports, by their ID
+// database.ErrInvalidUsersDB is returned if it isn't a users db
+func (u UserService) ImportUsers(ctx context.Context, r io.Reader) (models.UserImportSummary, error) {
+	// SQLite only reads files
+	file, err := os.CreateTemp("", "users-import-*.db")
+	if err != nil {
+		return models.UserImportSummary{}, fmt.Errorf("creating file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	_, err = io.Copy(file, r)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return models.UserImportSummary{}, fmt.Errorf("writing file: %w", err)
+	}
+
+	return u.importUsersDB(ctx, file.Name())
+}
+
+// ImportUsersFromExport upserts the users of a complete db export by their ID
+func (u UserService) ImportUsersFromExport(ctx context.Context, exportID string) (models.UserImportSummary, error) {
+	id, err := primitive.ObjectIDFromHex(exportID)
+	if err != nil {
+		return models.UserImportSummary{}, ErrDBExportNotFound
+	}
+
+	dbExport, err := u.repo.Batch.GetDBExport(ctx, id)
+	if errors.Is(err, repos.ErrNotFound) {
+		return models.UserImportSummary{}, ErrDBExportNotFound
+	}
+	if err != nil {
+		return models.UserImportSummary{}, err
+	}
+
+	if dbExport.Status != models.BatchStatusComplete {
+		return models.UserImportSummary{}, ErrDBExportNotComplete
+	}
+
+	file, err := os.CreateTemp("", "users-import-*.db")
+	if err != nil {
+		return models.UserImportSummary{}, fmt.Errorf("creating file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	err = storage.DownloadTo(ctx, u.blobStore, dbExport.FileName, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if errors.Is(err, storage.ErrBlobNotFound) {
+		return models.UserImportSummary{}, ErrDBExportNotFound
+	}
+	if err != nil {
+		return models.UserImportSummary{}, fmt.Errorf("downloading db export: %w", err)
+	}
+
+	return u.importUsersDB(ctx, file.Name())
+}
+
+func (u UserService) importUsersDB(ctx context.Context, fileName string) (models.UserImportSummary, error) {
+	var summary models.UserImportSummary
+
+	if err := database.ValidateUsersDB(ctx, fileName); err != nil {
+		return summary, err
+	}
+
+	users, invalid, err := database.GetUsersFromDatabase(ctx, fileName)
+	if err != nil {
+		return summary, fmt.Errorf("%w: %v", database.ErrInvalidUsersDB, err)
+	}
+	summary.Skipped = invalid
+
+	// Users without a name can't be created either
+	valid := make([]models.User, 0, len(users))
+	for _, user := range users {
+		if user.Name == "" {
+			summary.Skipped++
+			continue
+		}
+		valid = append(valid, user)
+	}
+
+	inserted, updated, unchanged, failed, err := u.repo.User.UpsertUsers(ctx, valid)
+	if err != nil {
+		return summary, err
+	}
+	if failed > 0 {
+		u.logger.Warn().Int("failed", failed).Msg("failed to upsert some users")
+	}
+
+	summary.Inserted = inserted
+	summary.Updated = updated
+	summary.Skipped += unchanged + failed
+
+	u.logger.Info().Int("inserted", summary.Inserted).Int("updated", summary.Updated).Int("skipped", summary.Skipped).Msg("imported users")
+	return summary, nil
+}
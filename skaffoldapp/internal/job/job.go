@@ -1,31 +1,170 @@
 package job
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"skaffoldapp/internal/shared/database"
+	"skaffoldapp/internal/shared/models"
+	"skaffoldapp/internal/shared/repos"
+	"skaffoldapp/internal/shared/storage"
 	"time"
 
 	"github.com/rs/zerolog"
 )
 
+// MinExportLease is the shortest lease of a claimed export; it's renewed every third of the lease,
+// so a renewal can be slow or fail and be retried before the claim could be lost
+const MinExportLease = 3 * time.Second
+
 type JobService struct {
-	logger zerolog.Logger
+	logger    zerolog.Logger
+	repo      repos.RepoCollection
+	blobStore storage.BlobStore
+	// workerID identifies this replica in the claims of the exports
+	workerID     string
+	pollInterval time.Duration
+	lease        time.Duration
 }
 
-func NewJobService(logger zerolog.Logger) JobService {
+func NewJobService(logger zerolog.Logger, repo repos.RepoCollection, blobStore storage.BlobStore, workerID string, pollInterval, lease time.Duration) JobService {
 	return JobService{
-		logger: logger,
+		logger:       logger,
+		repo:         repo,
+		blobStore:    blobStore,
+		workerID:     workerID,
+		pollInterval: pollInterval,
+		lease:        lease,
 	}
+}
+
+// Run polls for pending db exports until ctx is done
+func (j JobService) Run(ctx context.Context) {
+	j.poll(ctx)
+}
 
+func (j JobService) poll(ctx context.Context) {
+	ticker := time.NewTicker(j.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.logger.Debug().Msg("Polling for new jobs...")
+			if _, err := j.ProcessDBExports(ctx); err != nil {
+				j.logger.Error().Err(err).Msg("failed to process db exports")
+			}
+		}
+	}
 }
 
-// Just a demo for another service
-func (j JobService) Run() {
-	// Implement the logic for running the job
-	j.poll()
+// ProcessDBExports claims and processes the pending db exports one at a time until there are none
+// left, returning how many it processed. Exports are claimed atomically with a lease so replicas
+// never process the same one, and one left pending by a crashed replica is claimed again once its
+// lease expires
+func (j JobService) ProcessDBExports(ctx context.Context) (int, error) {
+	processed := 0
+
+	for ctx.Err() == nil {
+		dbExport, err := j.repo.Batch.ClaimPendingDBExport(ctx, j.workerID, j.lease)
+		if errors.Is(err, repos.ErrNotFound) {
+			return processed, nil
+		}
+		if err != nil {
+			return processed, err
+		}
+
+		logger := j.logger.With().Str("dbExportID", dbExport.ID.Hex()).Logger()
+		logger.Info().Msg("processing db export")
+
+		// The lease is renewed while the export is generated so a slow one isn't claimed by
+		// another replica; the generation stops if the claim is lost anyway
+		genCtx, cancel := context.WithCancel(ctx)
+		heartbeatDone := make(chan struct{})
+		go func() {
+			defer close(heartbeatDone)
+			j.heartbeat(genCtx, cancel, dbExport, logger)
+		}()
+
+		dbExport.FileName, err = j.generateDBExport(genCtx, dbExport)
+		cancel()
+		<-heartbeatDone
+
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to generate db export")
+			dbExport.Status = models.BatchStatusError
+			dbExport.ErrorMessage = err.Error()
+		} else {
+			dbExport.Status = models.BatchStatusComplete
+		}
+
+		if err := j.repo.Batch.FinishDBExport(ctx, dbExport, j.workerID); err != nil {
+			if errors.Is(err, repos.ErrNotFound) {
+				// Another replica claimed it after the lease expired; it'll finish it
+				logger.Warn().Msg("lost the claim of the db export")
+				continue
+			}
+			return processed, err
+		}
+
+		logger.Info().Str("status", dbExport.Status).Msg("processed db export")
+		processed++
+	}
+
+	return processed, ctx.Err()
 }
 
-func (j JobService) poll() {
+// Renews the lease of a claimed export every third of the lease until ctx is done, calling cancel
+// once the claim is lost. A failed renewal is retried on the next tick while the lease lasts
+func (j JobService) heartbeat(ctx context.Context, cancel context.CancelFunc, dbExport models.DBExport, logger zerolog.Logger) {
+	ticker := time.NewTicker(j.lease / 3)
+	defer ticker.Stop()
+
 	for {
-		time.Sleep(time.Second * 10)
-		j.logger.Println("Polling for new jobs...")
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := j.repo.Batch.RenewDBExportLease(ctx, dbExport.ID, j.workerID, j.lease)
+			if errors.Is(err, repos.ErrNotFound) {
+				logger.Warn().Msg("lost the claim of the db export while generating it")
+				cancel()
+				return
+			}
+			if err != nil && ctx.Err() == nil {
+				logger.Error().Err(err).Msg("failed to renew the lease of the db export")
+			}
+		}
+	}
+}
+
+// Creates the SQLite file of the users and uploads it, returning the name of the blob
+func (j JobService) generateDBExport(ctx context.Context, dbExport models.DBExport) (string, error) {
+	users, err := j.repo.User.GetUsers(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get users: %w", err)
+	}
+
+	if len(users) == 0 {
+		return "", errors.New("No users found")
+	}
+
+	// Named after the export so a retried export overwrites its own blob
+	dbExportName := dbExport.DateRequested.Format("20060102150405") + "-" + dbExport.ID.Hex() + ".db"
+	dbExportPath := filepath.Join(os.TempDir(), dbExportName)
+	defer os.Remove(dbExportPath)
+
+	if err := database.CreateUsersDB(users, dbExportPath); err != nil {
+		return "", fmt.Errorf("Failed to create db: %w", err)
 	}
+
+	if err := storage.UploadFile(ctx, j.blobStore, dbExportName, dbExportPath); err != nil {
+		return "", fmt.Errorf("Failed to upload file: %w", err)
+	}
+
+	return dbExportName, nil
 }
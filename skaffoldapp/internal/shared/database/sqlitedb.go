@@ -1,14 +1,20 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"skaffoldapp/internal/shared/models"
+	"strings"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	_ "modernc.org/sqlite" // SQLite driver
 )
 
+// ErrInvalidUsersDB is returned for a file that isn't a SQLite database with the users table of CreateUsersDB
+var ErrInvalidUsersDB = errors.New("invalid users database")
+
 func CreateUsersDB(users []models.User, fileName string) error {
 	db, err := sql.Open("sqlite", fileName)
 	if err != nil {
@@ -37,32 +43,101 @@ func CreateUsersDB(users []models.User, fileName string) error {
 	return nil
 }
 
-func GetUsersFromDatabase(fileName string) ([]models.User, error) {
-	db, err := sql.Open("sqlite", fileName)
+// Opens a database read-only; the files read may come from users
+func openReadOnly(fileName string) (*sql.DB, error) {
+	return sql.Open("sqlite", "file:"+fileName+"?mode=ro")
+}
+
+// GetUsersFromDatabase returns the users of a database created by CreateUsersDB, and how many rows
+// were left out because their id isn't an ObjectID or their name is missing. The file may come
+// from a user, so an invalid row doesn't fail the others
+func GetUsersFromDatabase(ctx context.Context, fileName string) ([]models.User, int, error) {
+	db, err := openReadOnly(fileName)
 	if err != nil {
-		return nil, fmt.Errorf("opening database: %w", err)
+		return nil, 0, fmt.Errorf("opening database: %w", err)
 	}
 	defer db.Close()
 
-	rows, err := db.Query("SELECT id, name FROM users")
+	rows, err := db.QueryContext(ctx, "SELECT id, name FROM users")
 	if err != nil {
-		return nil, fmt.Errorf("querying users: %w", err)
+		return nil, 0, fmt.Errorf("querying users: %w", err)
 	}
 	defer rows.Close()
 
 	var users []models.User
+	invalid := 0
 	for rows.Next() {
-		var user models.User
-		var id string
-		if err := rows.Scan(&id, &user.Name); err != nil {
-			return nil, fmt.Errorf("scanning user: %w", err)
+		// Both columns can be NULL, the id too as it isn't an INTEGER PRIMARY KEY
+		var id, name sql.NullString
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, 0, fmt.Errorf("scanning user: %w", err)
 		}
-		user.ID, err = primitive.ObjectIDFromHex(id)
-		if err != nil {
-			return nil, fmt.Errorf("converting id [%s] to ObjectID: %w", id, err)
+
+		objectID, err := primitive.ObjectIDFromHex(id.String)
+		if !id.Valid || !name.Valid || err != nil {
+			invalid++
+			continue
 		}
-		users = append(users, user)
+
+		users = append(users, models.User{ID: objectID, Name: name.String})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("reading users: %w", err)
+	}
+
+	return users, invalid, nil
+}
+
+// ValidateUsersDB checks that a file is a SQLite database with a users table with text id and name
+// columns, as created by CreateUsersDB
+// users must be a table: a view could run any query once the users are read
+func ValidateUsersDB(ctx context.Context, fileName string) error {
+	db, err := openReadOnly(fileName)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
 	}
+	defer db.Close()
 
-	return users, nil
+	var tables int
+	err = db.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'users'").Scan(&tables)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidUsersDB, err)
+	}
+	if tables == 0 {
+		return fmt.Errorf("%w: no users table", ErrInvalidUsersDB)
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT name, type FROM pragma_table_info('users')")
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidUsersDB, err)
+	}
+	defer rows.Close()
+
+	columns := make(map[string]string)
+	for rows.Next() {
+		var name, columnType string
+		if err := rows.Scan(&name, &columnType); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidUsersDB, err)
+		}
+		columns[strings.ToLower(name)] = strings.ToUpper(columnType)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidUsersDB, err)
+	}
+
+	if len(columns) == 0 {
+		return fmt.Errorf("%w: no users table", ErrInvalidUsersDB)
+	}
+
+	for _, column := range []string{"id", "name"} {
+		columnType, ok := columns[column]
+		if !ok {
+			return fmt.Errorf("%w: no %s column in users table", ErrInvalidUsersDB, column)
+		}
+		if columnType != "TEXT" {
+			return fmt.Errorf("%w: %s column of users table is %s, not TEXT", ErrInvalidUsersDB, column, columnType)
+		}
+	}
+
+	return nil
 }
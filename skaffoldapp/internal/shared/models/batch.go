@@ -22,12 +22,17 @@ type DBExport struct {
 	Status        string             `bson:"status"`
 	FileName      string             `bson:"file_name"`
 	ErrorMessage  string             `bson:"error_message"`
+	// ClaimedBy is the job replica processing a pending export until LeaseExpiresAt,
+	// after which another replica can claim it
+	ClaimedBy      string    `bson:"claimed_by,omitempty"`
+	LeaseExpiresAt time.Time `bson:"lease_expires_at,omitempty"`
 }
 
 type DBExportResponse struct {
 	ID            string    `json:"id"`
 	DateRequested time.Time `json:"date_requested"`
-	FileName      string    `bson:"file_name"`
 	Status        string    `json:"status"`
 	ErrorMessage  string    `json:"error_message"`
+	// DownloadURL is the path the SQLite file is downloaded from once the export is complete
+	DownloadURL string `json:"download_url,omitempty"`
 }
@@ -15,3 +15,21 @@ type UserResponse struct {
 type UserRequest struct {
 	Name string `json:"name"`
 }
+
+// UserImportRequest imports the users of a complete db export
+type UserImportRequest struct {
+	ExportID string `json:"export_id"`
+}
+
+type UserImportSummary struct {
+	Inserted int
+	Updated  int
+	// Skipped users are invalid, already up to date or failed to be written
+	Skipped int
+}
+
+type UserImportResponse struct {
+	Inserted int `json:"inserted"`
+	Updated  int `json:"updated"`
+	Skipped  int `json:"skipped"`
+}
@@ -2,13 +2,16 @@ package repos
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"skaffoldapp/internal/shared/database"
 	"skaffoldapp/internal/shared/models"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type BatchRepo struct {
@@ -47,3 +50,83 @@ func (b BatchRepo) UpdateDBExport(ctx context.Context, dbExport models.DBExport)
 	}
 	return nil
 }
+
+func (b BatchRepo) GetDBExport(ctx context.Context, id primitive.ObjectID) (models.DBExport, error) {
+	var dbExport models.DBExport
+
+	err := b.exportedDBColl.FindOne(ctx, bson.M{"_id": id}).Decode(&dbExport)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return dbExport, ErrNotFound
+	}
+	if err != nil {
+		return dbExport, fmt.Errorf("failed to get dbExport: %w", err)
+	}
+	return dbExport, nil
+}
+
+// ClaimPendingDBExport atomically claims the oldest pending export that isn't claimed or whose lease
+// expired, so that only one job replica processes it; ErrNotFound is returned when there's none
+func (b BatchRepo) ClaimPendingDBExport(ctx context.Context, owner string, lease time.Duration) (models.DBExport, error) {
+	var dbExport models.DBExport
+
+	now := time.Now().Truncate(time.Millisecond)
+	filter := bson.M{
+		"status": models.BatchStatusPending,
+		"$or": bson.A{
+			bson.M{"lease_expires_at": bson.M{"$exists": false}},
+			bson.M{"lease_expires_at": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"claimed_by": owner, "lease_expires_at": now.Add(lease)}}
+	// "name" is the bson key of DateRequested since the first release, so the oldest request comes
+	// first; the stored documents use it, don't rename it to date_requested
+	opts := options.FindOneAndUpdate().SetSort(bson.D{{Key: "name", Value: 1}}).SetReturnDocument(options.After)
+
+	err := b.exportedDBColl.FindOneAndUpdate(ctx, filter, update, opts).Decode(&dbExport)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return dbExport, ErrNotFound
+	}
+	if err != nil {
+		return dbExport, fmt.Errorf("failed to claim dbExport: %w", err)
+	}
+	return dbExport, nil
+}
+
+// RenewDBExportLease extends the lease of an export claimed by owner while it's processed;
+// ErrNotFound is returned when owner lost its claim, e.g. after its lease expired
+func (b BatchRepo) RenewDBExportLease(ctx context.Context, id primitive.ObjectID, owner string, lease time.Duration) error {
+	filter := bson.M{"_id": id, "status": models.BatchStatusPending, "claimed_by": owner}
+	update := bson.M{"$set": bson.M{"lease_expires_at": time.Now().Truncate(time.Millisecond).Add(lease)}}
+
+	result, err := b.exportedDBColl.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to renew dbExport lease: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// FinishDBExport sets the status, file name and error message of an export claimed by owner and
+// releases it; ErrNotFound is returned when owner lost its claim, e.g. after its lease expired
+func (b BatchRepo) FinishDBExport(ctx context.Context, dbExport models.DBExport, owner string) error {
+	filter := bson.M{"_id": dbExport.ID, "status": models.BatchStatusPending, "claimed_by": owner}
+	update := bson.M{
+		"$set": bson.M{
+			"status":        dbExport.Status,
+			"file_name":     dbExport.FileName,
+			"error_message": dbExport.ErrorMessage,
+		},
+		"$unset": bson.M{"claimed_by": "", "lease_expires_at": ""},
+	}
+
+	result, err := b.exportedDBColl.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to finish dbExport: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
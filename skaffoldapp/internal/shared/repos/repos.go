@@ -1,11 +1,15 @@
 package repos
 
 import (
+	"errors"
 	"skaffoldapp/internal/shared/database"
 
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// ErrNotFound is returned when no document matches
+var ErrNotFound = errors.New("not found")
+
 type RepoCollection struct {
 	User  UserRepo
 	Batch BatchRepo
@@ -2,12 +2,15 @@ package repos
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"skaffoldapp/internal/shared/database"
 	"skaffoldapp/internal/shared/models"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type UserRepo struct {
@@ -42,3 +45,32 @@ func (u UserRepo) InsertUser(ctx context.Context, user models.User) (string, err
 
 	return id.Hex(), err
 }
+
+// UpsertUsers inserts the users or updates them by ID, returning how many were inserted, updated, left
+// unchanged because they were already up to date, and failed
+// The writes are unordered so a failed one doesn't stop the others; their failures are only counted
+func (u UserRepo) UpsertUsers(ctx context.Context, users []models.User) (inserted, updated, unchanged, failed int, err error) {
+	if len(users) == 0 {
+		return 0, 0, 0, 0, nil
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(users))
+	for _, user := range users {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": user.ID}).
+			SetUpdate(bson.M{"$set": bson.M{"name": user.Name}}).
+			SetUpsert(true))
+	}
+
+	result, err := u.coll.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+
+	// The result still counts the writes that succeeded when some of them failed
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) && bulkErr.WriteConcernError == nil && result != nil {
+		failed = len(bulkErr.WriteErrors)
+	} else if err != nil {
+		return 0, 0, 0, 0, fmt.Errorf("failed to upsert users: %w", err)
+	}
+
+	return int(result.UpsertedCount), int(result.ModifiedCount), int(result.MatchedCount - result.ModifiedCount), failed, nil
+}
@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+)
+
+// AzureManager is the BlobStore of a container of an Azure storage account
+type AzureManager struct {
+	client        *azblob.Client
+	containerName string
+}
+
+func NewAzureManager(accountName, accountKey, blobEndpoint, containerName string) (AzureManager, error) {
+	azureManager := AzureManager{
+		containerName: containerName,
+	}
+
+	if containerName == "" || accountName == "" || accountKey == "" || blobEndpoint == "" {
+		return azureManager, fmt.Errorf("AZURE_STORAGE_CONTAINER_NAME, AZURE_STORAGE_ACCOUNT, AZURE_STORAGE_KEY, and AZURE_STORAGE_BLOB_ENDPOINT must be set")
+	}
+
+	credential, err := azblob.NewSharedKeyCredential(accountName, accountKey)
+	if err != nil {
+		return azureManager, fmt.Errorf("failed to create credential: %w", err)
+	}
+
+	client, err := azblob.NewClientWithSharedKeyCredential(blobEndpoint, credential, nil)
+	if err != nil {
+		return azureManager, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	azureManager.client = client
+	return azureManager, nil
+}
+
+func (a AzureManager) Upload(ctx context.Context, blobName string, r io.Reader) error {
+	_, err := a.client.UploadStream(ctx, a.containerName, blobName, r, nil)
+	if err != nil {
+		return fmt.Errorf("failed to upload file to blob: %w", err)
+	}
+
+	return nil
+}
+
+func (a AzureManager) Download(ctx context.Context, blobName string) (io.ReadCloser, int64, error) {
+	resp, err := a.client.DownloadStream(ctx, a.containerName, blobName, nil)
+	if isBlobNotFound(err) {
+		return nil, 0, ErrBlobNotFound
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("downloading blob: %w", err)
+	}
+
+	if resp.ContentLength == nil {
+		resp.Body.Close()
+		return nil, 0, errors.New("downloading blob: missing content length")
+	}
+
+	return resp.Body, *resp.ContentLength, nil
+}
+
+func (a AzureManager) List(ctx context.Context) ([]BlobInfo, error) {
+	blobs := []BlobInfo{}
+
+	pager := a.client.NewListBlobsFlatPager(a.containerName, nil)
+	for pager.More() {
+		resp, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list blobs: %w", err)
+		}
+
+		for _, blob := range resp.Segment.BlobItems {
+			info := BlobInfo{Name: *blob.Name}
+			if blob.Properties != nil {
+				if blob.Properties.ContentLength != nil {
+					info.Size = *blob.Properties.ContentLength
+				}
+				if blob.Properties.LastModified != nil {
+					info.LastModified = *blob.Properties.LastModified
+				}
+			}
+			blobs = append(blobs, info)
+		}
+	}
+
+	return blobs, nil
+}
+
+func (a AzureManager) Delete(ctx context.Context, blobName string) error {
+	_, err := a.client.DeleteBlob(ctx, a.containerName, blobName, nil)
+	if isBlobNotFound(err) {
+		return ErrBlobNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("deleting blob: %w", err)
+	}
+
+	return nil
+}
+
+func (a AzureManager) Stat(ctx context.Context, blobName string) (BlobInfo, error) {
+	info := BlobInfo{Name: blobName}
+
+	blobClient := a.client.ServiceClient().NewContainerClient(a.containerName).NewBlobClient(blobName)
+	props, err := blobClient.GetProperties(ctx, nil)
+	if isBlobNotFound(err) {
+		return info, ErrBlobNotFound
+	}
+	if err != nil {
+		return info, fmt.Errorf("getting blob properties: %w", err)
+	}
+
+	if props.ContentLength != nil {
+		info.Size = *props.ContentLength
+	}
+	if props.LastModified != nil {
+		info.LastModified = *props.LastModified
+	}
+	return info, nil
+}
+
+// Responses to HEAD requests have no body so their error code may be missing
+func isBlobNotFound(err error) bool {
+	var respErr *azcore.ResponseError
+	return bloberror.HasCode(err, bloberror.BlobNotFound) || (errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound)
+}
@@ -0,0 +1,180 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// Uploads are written to temporary files first so a blob is never read half written
+	uploadTempPrefix = ".upload-"
+	// Blobs can be read by the other users of the directory, e.g. the api reading the exports of the job
+	blobFileMode = 0o644
+)
+
+// LocalStore is the BlobStore of a local directory, e.g. for running the app without Azure
+// The directory must be shared by the api and the job, e.g. a volume mounted in both
+type LocalStore struct {
+	dir string
+}
+
+func NewLocalStore(dir string) (LocalStore, error) {
+	if dir == "" {
+		return LocalStore{}, fmt.Errorf("LOCAL_BLOB_DIR must be set")
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return LocalStore{}, fmt.Errorf("creating blob directory: %w", err)
+	}
+
+	return LocalStore{dir: dir}, nil
+}
+
+func (l LocalStore) Upload(ctx context.Context, blobName string, r io.Reader) error {
+	path, err := l.path(blobName)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(l.dir, uploadTempPrefix)
+	if err != nil {
+		return fmt.Errorf("creating file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing file: %w", err)
+	}
+
+	// CreateTemp makes the file readable by its owner only, but the api and the job can run as
+	// different users sharing the directory
+	if err := tmp.Chmod(blobFileMode); err != nil {
+		tmp.Close()
+		return fmt.Errorf("setting file mode: %w", err)
+	}
+
+	// Flushed before the rename so a crash can't leave a truncated blob under its name
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("syncing file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("renaming file: %w", err)
+	}
+
+	return nil
+}
+
+func (l LocalStore) Download(ctx context.Context, blobName string) (io.ReadCloser, int64, error) {
+	path, err := l.path(blobName)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, 0, ErrBlobNotFound
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("opening file: %w", err)
+	}
+
+	// The size of the opened file, which an upload replacing the blob doesn't change
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, fmt.Errorf("reading file info: %w", err)
+	}
+
+	return file, info.Size(), nil
+}
+
+func (l LocalStore) List(ctx context.Context) ([]BlobInfo, error) {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading blob directory: %w", err)
+	}
+
+	blobs := []BlobInfo{}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), uploadTempPrefix) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			// Deleted while listing
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading file info: %w", err)
+		}
+
+		blobs = append(blobs, blobInfo(info))
+	}
+
+	return blobs, nil
+}
+
+func (l LocalStore) Delete(ctx context.Context, blobName string) error {
+	path, err := l.path(blobName)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrBlobNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("deleting file: %w", err)
+	}
+
+	return nil
+}
+
+func (l LocalStore) Stat(ctx context.Context, blobName string) (BlobInfo, error) {
+	path, err := l.path(blobName)
+	if err != nil {
+		return BlobInfo{Name: blobName}, err
+	}
+
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && !info.Mode().IsRegular()) {
+		return BlobInfo{Name: blobName}, ErrBlobNotFound
+	}
+	if err != nil {
+		return BlobInfo{Name: blobName}, fmt.Errorf("reading file info: %w", err)
+	}
+
+	return blobInfo(info), nil
+}
+
+// Returns the path of a blob; names can't be paths so blobs stay inside the directory
+func (l LocalStore) path(blobName string) (string, error) {
+	if blobName == "" || blobName != filepath.Base(blobName) || blobName == "." || blobName == ".." || strings.HasPrefix(blobName, uploadTempPrefix) {
+		return "", fmt.Errorf("invalid blob name %q", blobName)
+	}
+
+	return filepath.Join(l.dir, blobName), nil
+}
+
+func blobInfo(info fs.FileInfo) BlobInfo {
+	return BlobInfo{
+		Name:         info.Name(),
+		Size:         info.Size(),
+		LastModified: info.ModTime(),
+	}
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+const (
+	BlobStoreAzure = "azure"
+	BlobStoreLocal = "local"
+)
+
+var ErrBlobNotFound = errors.New("blob not found")
+
+// Config selects the blob store of the app and configures it; its fields are read from the
+// environment by the binaries embedding it
+type Config struct {
+	// BlobStore is where the exports are stored: azure, or local for the LocalBlobDir directory
+	BlobStore             string `default:"azure" envconfig:"BLOB_STORE"`
+	LocalBlobDir          string `envconfig:"LOCAL_BLOB_DIR"`
+	AzStorageAccount      string `envconfig:"AZURE_STORAGE_ACCOUNT"`
+	AzStorageKey          string `envconfig:"AZURE_STORAGE_KEY"`
+	AzStorageBlobEndpoint string `envconfig:"AZURE_STORAGE_BLOB_ENDPOINT"`
+	AzContainerName       string `envconfig:"AZURE_STORAGE_CONTAINER_NAME"`
+}
+
+// New creates the blob store selected by cfg
+func New(cfg Config) (BlobStore, error) {
+	switch cfg.BlobStore {
+	case BlobStoreAzure:
+		return NewAzureManager(cfg.AzStorageAccount, cfg.AzStorageKey, cfg.AzStorageBlobEndpoint, cfg.AzContainerName)
+	case BlobStoreLocal:
+		return NewLocalStore(cfg.LocalBlobDir)
+	default:
+		return nil, fmt.Errorf("unknown BLOB_STORE %q, must be %s or %s", cfg.BlobStore, BlobStoreAzure, BlobStoreLocal)
+	}
+}
+
+type BlobInfo struct {
+	Name         string
+	Size         int64
+	LastModified time.Time
+}
+
+// BlobStore stores the files of the app, e.g. the db exports, by name
+// Download, Delete and Stat return ErrBlobNotFound for a blob that doesn't exist
+type BlobStore interface {
+	// Upload stores the content of r as a blob, replacing the blob if it exists
+	Upload(ctx context.Context, blobName string, r io.Reader) error
+	// Download opens a blob for reading without buffering it and returns its size, both from the
+	// same read so the size always matches the content; the caller closes the reader
+	Download(ctx context.Context, blobName string) (io.ReadCloser, int64, error)
+	List(ctx context.Context) ([]BlobInfo, error)
+	Delete(ctx context.Context, blobName string) error
+	Stat(ctx context.Context, blobName string) (BlobInfo, error)
+}
+
+// UploadFile uploads a file as a blob of store
+func UploadFile(ctx context.Context, store BlobStore, blobName, filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	return store.Upload(ctx, blobName, file)
+}
+
+// DownloadTo copies a blob of store to w, e.g. a file
+func DownloadTo(ctx context.Context, store BlobStore, blobName string, w io.Writer) error {
+	r, _, err := store.Download(ctx, blobName)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	if _, err := io.Copy(w, r); err != nil {
+		return fmt.Errorf("copying blob: %w", err)
+	}
+
+	return nil
+}